@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"os"
 	"time"
 
 	"github.com/shopally-ai/cmd/api/middleware"
@@ -12,57 +16,97 @@ import (
 	"github.com/shopally-ai/internal/adapter/handler"
 	repo "github.com/shopally-ai/internal/adapter/repository"
 	"github.com/shopally-ai/internal/config"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/metrics"
 	"github.com/shopally-ai/internal/platform"
+	"github.com/shopally-ai/internal/tracing"
 	"github.com/shopally-ai/pkg/domain"
 	"github.com/shopally-ai/pkg/usecase"
+	"github.com/shopally-ai/pkg/util"
 )
 
+var mainLog = logging.Component("main")
+
 func main() {
 	// Load configuration
 	cfg, err := config.LoadConfig(".")
 	if err != nil {
-		log.Fatalf("failed to load config: %v", err)
+		fatal("failed to load config", err)
+	}
+	logging.Setup(logging.Config{
+		Level:         cfg.Logging.Level,
+		Format:        cfg.Logging.Format,
+		RedactQueries: cfg.Logging.RedactQueries,
+	})
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+		ServiceName: "shopally-api",
+	})
+	if err != nil {
+		fatal("failed to set up tracing", err)
 	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			mainLog.Error("failed to flush traces", logging.Err(err))
+		}
+	}()
 
 	// Connect to MongoDB using custom db package
 	client, err := platform.Connect(cfg.Mongo.URI)
 	if err != nil {
-		log.Fatalf("failed to connect to MongoDB: %v", err)
+		fatal("failed to connect to MongoDB", err)
 	}
 	defer func() {
 		if err := platform.Disconnect(client); err != nil {
-			log.Printf("failed to disconnect MongoDB: %v", err)
+			mainLog.Error("failed to disconnect MongoDB", logging.Err(err))
 		}
 	}()
 	db := client.Database(cfg.Mongo.Database)
-	fmt.Printf("Connected to MongoDB database: %s\n", db.Name())
+	mainLog.Info("connected to MongoDB", "database", db.Name())
 
 	// Initialize Redis client
 	rdb := platform.NewRedisClient(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password, cfg.Redis.DB)
+	limiterRedis := rdb.Client
 
 	// Test Redis connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := rdb.Ping(ctx); err != nil {
-		log.Printf("⚠️  Redis connection failed: %v (continuing without Redis)", err)
+		mainLog.Warn("Redis connection failed, continuing without Redis", logging.Err(err))
 		rdb = nil
 	} else {
-		log.Println("✅ Redis connected")
+		mainLog.Info("Redis connected")
 	}
 
+	// Rate limiting shares the platform Redis client and falls back to an
+	// in-process limiter while Redis is unavailable, so it keeps the client
+	// even when Redis is down at startup.
 	limiter := middleware.NewRateLimiter(
-		cfg.Redis.Host+":"+cfg.Redis.Port,
+		limiterRedis,
 		cfg.RateLimit.Limit,
 		time.Duration(cfg.RateLimit.Window)*time.Second,
-	)
+	).
+		WithSignedInTier(cfg.RateLimit.UserLimit, 0).
+		WithRouteCosts(cfg.RateLimit.Costs).
+		WithFailurePolicy(middleware.FailurePolicy(cfg.RateLimit.FailurePolicy))
+	if err := limiter.RegisterMetrics(metrics.Registry); err != nil {
+		mainLog.Warn("failed to register rate limiter metrics", logging.Err(err))
+	}
+	metrics.Serve(cfg.Metrics.Addr)
 
 	// FX client (provider defaults to exchangerate.host if not configured)
 	fxInner := gateway.NewFXHTTPGateway("", "", nil)
 	var fxClient domain.IFXClient = fxInner
 	// Wrap with Redis cache if available
+	var cache domain.ICachePort
+	var redisCache *gateway.RedisCache
 	if rdb != nil {
-		redisCache := gateway.NewRedisCache(rdb.Client, "sa:")
+		redisCache = gateway.NewRedisCache(rdb.Client, "sa:")
+		cache = redisCache
 		fxClient = gateway.NewCachedFXClient(fxInner, redisCache, 12*time.Hour)
 	}
 
@@ -74,9 +118,48 @@ func main() {
 	// the following line with: ag := gateway.NewMockAlibabaGateway()
 	ag := gateway.NewAlibabaHTTPGateway(cfg)
 
+	// Price history: every search and product lookup records what it saw
+	phCollName := cfg.Mongo.PriceHistoryCollection
+	if phCollName == "" {
+		phCollName = "price_history"
+	}
+	priceHistoryRepo := repo.NewMongoPriceHistoryRepository(db.Collection(phCollName))
+	if err := priceHistoryRepo.EnsureIndexes(context.Background()); err != nil {
+		mainLog.Error("failed to create price history indexes", logging.Err(err))
+	}
+	priceHistoryUC := usecase.NewPriceHistoryUseCase(priceHistoryRepo, time.Duration(cfg.Search.PriceDedupMinutes)*time.Minute)
+	dealAnalyzer := usecase.NewDealAnalyzer(priceHistoryRepo)
+
 	// Construct usecase and handler for search
-	uc := usecase.NewSearchProductsUseCase(ag, lg, nil)
-	searchHandler := handler.NewSearchHandler(uc)
+	uc := usecase.NewSearchProductsUseCase(ag, lg, nil).
+		WithFXClient(fxClient).
+		WithPriceHistory(priceHistoryUC).
+		WithDealAnalyzer(dealAnalyzer).
+		WithPriceRelaxMargin(cfg.Search.PriceRelaxMargin).
+		WithSnapshotCache(cache, usecase.DefaultSearchSnapshotTTL)
+
+	// Landed cost: built-in Ethiopian tariff table unless a JSON table is configured
+	tariffs := usecase.DefaultTariffTable
+	if cfg.LandedCost.TariffFile != "" {
+		t, err := config.LoadTariffTable(cfg.LandedCost.TariffFile)
+		if err != nil {
+			fatal("failed to load tariff table", err)
+		}
+		tariffs = t
+	}
+	landedCost := usecase.NewLandedCostCalculator(tariffs, fxClient)
+
+	// Product detail: cached upstream lookups priced in ETB
+	productUC := usecase.NewGetProductUseCase(ag, fxClient, cache, usecase.DefaultProductCacheTTL).
+		WithPriceHistory(priceHistoryUC).
+		WithDealAnalyzer(dealAnalyzer).
+		WithLandedCost(landedCost)
+	productHandler := handler.NewProductHandler(productUC, priceHistoryUC)
+
+	// Pasted links resolve straight to products, both via /search and /links/resolve
+	linkUC := usecase.NewResolveLinkUseCase(gateway.NewAliExpressLinkResolver(nil), productUC)
+	linkHandler := handler.NewLinkHandler(linkUC)
+	searchHandler := handler.NewSearchHandler(uc).WithLinkResolver(linkUC)
 
 	// Alerts: set up Mongo repository and handler
 	collName := cfg.Mongo.AlertCollection
@@ -88,14 +171,143 @@ func main() {
 	alertMgr := usecase.NewAlertManager(alertRepo)
 
 	alertHandler := handler.NewAlertHandler(alertMgr)
-	compareHandler := handler.NewCompareHandler(usecase.NewCompareProductsUseCase(lg))
+	// Compare by IDs resolves fresh product data, falling back to recent search results
+	productsResolver := usecase.NewResolveProductsUseCase(productUC, cache)
+	compareUC := usecase.NewCompareProductsUseCase(lg).
+		WithLandedCost(landedCost).
+		WithResultCache(cache, time.Duration(cfg.Compare.ResultTTLMinutes)*time.Minute)
+	compareHandler := handler.NewCompareHandler(compareUC).
+		WithProductResolver(productsResolver)
+
+	cartHandler := handler.NewCartHandler(usecase.NewCartQuoteUseCase(productsResolver, landedCost))
+
+	// Saved items, keyed by user or device
+	savedColl := cfg.Mongo.SavedItemCollection
+	if savedColl == "" {
+		savedColl = "saved_items"
+	}
+	savedRepo := repo.NewMongoSavedItemRepository(db.Collection(savedColl))
+	if err := savedRepo.EnsureIndexes(context.Background()); err != nil {
+		mainLog.Error("failed to create saved item indexes", logging.Err(err))
+	}
+	savedItemHandler := handler.NewSavedItemHandler(usecase.NewSavedItemsUseCase(savedRepo, productUC, alertMgr))
+
+	// Preferences, keyed by user or device and applied to every request
+	prefsColl := cfg.Mongo.PreferenceCollection
+	if prefsColl == "" {
+		prefsColl = "preferences"
+	}
+	prefsUC := usecase.NewPreferencesUseCase(repo.NewMongoPreferencesRepository(db.Collection(prefsColl))).
+		WithCache(cache, usecase.DefaultPreferencesCacheTTL)
+	preferencesHandler := handler.NewPreferencesHandler(prefsUC)
+
+	// Accounts: users, refresh-token sessions and JWT issuing
+	userColl := cfg.Mongo.UserCollection
+	if userColl == "" {
+		userColl = "users"
+	}
+	sessionColl := cfg.Mongo.SessionCollection
+	if sessionColl == "" {
+		sessionColl = "sessions"
+	}
+	userRepo := repo.NewMongoUserRepository(db.Collection(userColl))
+	if err := userRepo.EnsureIndexes(context.Background()); err != nil {
+		mainLog.Error("failed to create user indexes", logging.Err(err))
+	}
+	sessionRepo := repo.NewMongoSessionRepository(db.Collection(sessionColl))
+	if err := sessionRepo.EnsureIndexes(context.Background()); err != nil {
+		mainLog.Error("failed to create session indexes", logging.Err(err))
+	}
+	jwtSecret := cfg.Auth.JWTSecret
+	if jwtSecret == "" {
+		if !cfg.Server.DevMode {
+			fatal("auth.jwt_secret is required", errors.New("set auth.jwt_secret, or server.dev_mode for local development"))
+		}
+		mainLog.Warn("auth.jwt_secret is not set; dev mode uses a random secret, sessions will not survive a restart")
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			fatal("failed to generate jwt secret", err)
+		}
+		jwtSecret = hex.EncodeToString(b)
+	}
+	tokens := usecase.NewTokenService(jwtSecret, cfg.Auth.Issuer,
+		time.Duration(cfg.Auth.AccessTTLMinutes)*time.Minute,
+		time.Duration(cfg.Auth.RefreshTTLHours)*time.Hour)
+	authUC := usecase.NewAuthUseCase(userRepo, sessionRepo, tokens)
+	authHandler := handler.NewAuthHandler(authUC).
+		WithDeviceClaim(usecase.NewDeviceClaimUseCase(alertMgr, alertRepo, savedRepo))
+
+	// Google sign-in is enabled once a client is configured.
+	var googleHandler *handler.OAuthHandler
+	if g := cfg.OAuth.Google; g.ClientID != "" {
+		google := gateway.NewGoogleOAuthGateway(g.ClientID, g.ClientSecret, g.RedirectURI, nil).
+			WithEndpoints(g.AuthURL, g.TokenURL, g.UserInfoURL)
+		googleLogin := usecase.NewOAuthLoginUseCase(google, userRepo, authUC)
+		googleHandler = handler.NewOAuthHandler(googleLogin, domain.IdentityProviderGoogle)
+		switch {
+		case g.FrontendURL == "":
+		case redisCache == nil:
+			mainLog.Warn("oauth.google.frontend_url needs Redis; the callback returns tokens as JSON")
+		default:
+			googleLogin.WithHandoff(redisCache, 0)
+			googleHandler.WithFrontendRedirect(g.FrontendURL)
+		}
+	} else {
+		mainLog.Info("oauth.google.client_id is not set; Google sign-in is disabled")
+	}
+
+	// AliExpress account linking; tokens are encrypted at rest.
+	var affiliateHandler *handler.AffiliateHandler
+	var affiliates middleware.AffiliateSource
+	if ae := cfg.OAuth.Aliexpress; ae.ClientID != "" {
+		key, err := encryptionKey(cfg.Auth.EncryptionKey)
+		if err != nil {
+			fatal("AliExpress account linking needs auth.encryption_key", err)
+		}
+		box, err := util.NewSecretBox(key)
+		if err != nil {
+			fatal("invalid auth.encryption_key", err)
+		}
+		affiliateColl := cfg.Mongo.AffiliateCollection
+		if affiliateColl == "" {
+			affiliateColl = "affiliate_accounts"
+		}
+		aliAuth := gateway.NewAliExpressOAuthGateway(ae.ClientID, ae.ClientSecret, ae.RedirectURI, nil).
+			WithEndpoints(ae.AuthURL, ae.RestURL)
+		affiliateUC := usecase.NewAffiliateAccountUseCase(aliAuth,
+			repo.NewMongoAffiliateAccountRepository(db.Collection(affiliateColl), box), tokens)
+		affiliateHandler = handler.NewAffiliateHandler(affiliateUC)
+		affiliates = affiliateUC
+	} else {
+		mainLog.Info("oauth.aliexpress.client_id is not set; AliExpress account linking is disabled")
+	}
 
 	// Initialize router
-	router := router.SetupRouter(cfg, limiter, searchHandler, compareHandler, alertHandler)
+	router := router.SetupRouter(cfg, limiter, searchHandler, compareHandler, alertHandler, productHandler, linkHandler, cartHandler, savedItemHandler, authHandler, googleHandler, affiliateHandler, preferencesHandler, authUC, affiliates, prefsUC)
 
 	// Start the server
-	log.Println("Starting server on port", cfg.Server.Port)
+	mainLog.Info("starting server", "port", cfg.Server.Port)
 	if err := router.Run(":" + cfg.Server.Port); err != nil {
-		log.Fatalf("could not start server: %v", err)
+		fatal("could not start server", err)
 	}
 }
+
+// encryptionKey decodes the configured base64 key. There is deliberately no
+// fallback: a key that changes between restarts or replicas would make every
+// stored token undecryptable.
+func encryptionKey(configured string) ([]byte, error) {
+	if configured == "" {
+		return nil, errors.New("auth.encryption_key is not set")
+	}
+	key, err := base64.StdEncoding.DecodeString(configured)
+	if err != nil {
+		return nil, fmt.Errorf("auth.encryption_key is not valid base64: %w", err)
+	}
+	return key, nil
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	mainLog.Error(msg, logging.Err(err))
+	os.Exit(1)
+}
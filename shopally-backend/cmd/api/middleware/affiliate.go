@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// AffiliateSource returns the affiliate credentials of a user's linked
+// account, or nil when none is linked.
+type AffiliateSource interface {
+	Credentials(ctx context.Context, userID string) (*domain.AffiliateCredentials, error)
+}
+
+// AffiliateCredentials stores the signed-in caller's linked AliExpress
+// credentials under contextkeys.Affiliate. It must run after OptionalAuth.
+// Failures are logged and the request continues with the app credentials.
+func AffiliateCredentials(src AffiliateSource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Request.Context().Value(contextkeys.User).(*domain.User)
+		if !ok || user == nil {
+			c.Next()
+			return
+		}
+		creds, err := src.Credentials(c.Request.Context(), user.ID)
+		if err != nil {
+			middlewareLog.WarnContext(c.Request.Context(), "affiliate credentials lookup failed", "user_id", user.ID, logging.Err(err))
+		}
+		if creds != nil {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextkeys.Affiliate, creds))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// Authenticator verifies an access token and returns its user.
+type Authenticator interface {
+	Authenticate(accessToken string) (*domain.User, error)
+}
+
+// OptionalAuth stores the caller in the request context under
+// contextkeys.User when a valid bearer token is sent. Requests without a
+// valid token continue anonymously, so an expired token does not stand in
+// the way of refreshing it; RequireAuth rejects it where a user is needed.
+func OptionalAuth(auth Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token, ok := bearerToken(c); ok {
+			if user, err := auth.Authenticate(token); err == nil {
+				setUser(c, user)
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireAuth rejects requests without a valid bearer token.
+func RequireAuth(auth Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Request.Context().Value(contextkeys.User).(*domain.User); ok {
+			c.Next()
+			return
+		}
+		token, ok := bearerToken(c)
+		if !ok {
+			abortUnauthorized(c, "Missing bearer token")
+			return
+		}
+		if !authenticate(c, auth, token) {
+			return
+		}
+		c.Next()
+	}
+}
+
+func authenticate(c *gin.Context, auth Authenticator, token string) bool {
+	user, err := auth.Authenticate(token)
+	if err != nil {
+		abortUnauthorized(c, "Invalid or expired access token")
+		return false
+	}
+	setUser(c, user)
+	return true
+}
+
+func setUser(c *gin.Context, user *domain.User) {
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextkeys.User, user))
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	h := c.GetHeader("Authorization")
+	if len(h) < 7 || !strings.EqualFold(h[:7], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(h[7:])
+	return token, token != ""
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	apierror.Respond(c, domain.Unauthorized(message))
+}
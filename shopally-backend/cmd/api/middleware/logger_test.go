@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	var buf bytes.Buffer
+	slog.SetDefault(logging.New(&buf, logging.Config{}))
+
+	r := gin.New()
+	r.Use(RequestID(), RequestLogger())
+	r.GET("/api/v1/search", func(c *gin.Context) { c.Status(http.StatusTeapot) })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?q=red+shoes", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line), buf.String())
+	assert.Equal(t, "WARN", line["level"])
+	assert.Equal(t, "http", line["component"])
+	assert.Equal(t, "/api/v1/search", line["route"])
+	assert.Equal(t, "/api/v1/search", line["path"])
+	assert.Equal(t, float64(http.StatusTeapot), line["status"])
+	assert.Equal(t, "req-42", line["request_id"])
+	assert.NotContains(t, buf.String(), "red")
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopally-ai/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/api/v1/products/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/api/v1/products/:id", "404")
+	before := testutil.ToFloat64(counter)
+	for _, id := range []string{"1", "2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/products/"+id, nil))
+	}
+	assert.Equal(t, before+2, testutil.ToFloat64(counter))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `shopally_http_request_duration_seconds_count{method="GET",route="/api/v1/products/:id",status="404"}`)
+	assert.False(t, strings.Contains(w.Body.String(), `route="/api/v1/products/1"`), "raw paths must not become labels")
+
+	other := metrics.HTTPRequests.WithLabelValues("other", "unmatched", "404")
+	before = testutil.ToFloat64(other)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("FOOBAR", "/nowhere", nil))
+	assert.Equal(t, before+1, testutil.ToFloat64(other))
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// PreferencesSource returns the stored preferences that apply to a caller,
+// or nil when none are stored.
+type PreferencesSource interface {
+	Resolve(ctx context.Context, owner domain.Owner) (*domain.Preferences, error)
+}
+
+// Preferences stores the caller's preferences under contextkeys.Preferences.
+// The caller is the signed-in user, else the X-Device-ID, so it must run
+// after OptionalAuth. Failures are logged and the request continues with
+// the defaults.
+func Preferences(src PreferencesSource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		owner := domain.Owner{DeviceID: c.GetHeader("X-Device-ID")}
+		if user, ok := c.Request.Context().Value(contextkeys.User).(*domain.User); ok && user != nil {
+			owner.UserID = user.ID
+		}
+		if owner.Key() == "" {
+			c.Next()
+			return
+		}
+		prefs, err := src.Resolve(c.Request.Context(), owner)
+		if err != nil {
+			middlewareLog.WarnContext(c.Request.Context(), "preferences lookup failed", "owner", owner.Key(), logging.Err(err))
+		}
+		if prefs != nil {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextkeys.Preferences, prefs))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,379 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/metrics"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+var rateLimitLog = logging.Component("rate_limit")
+
+// DefaultRouteCosts are the tokens a request to a route takes from the
+// caller's bucket, keyed by route pattern. Unlisted routes cost 1. Routes
+// that call the LLM or fan out upstream cost more.
+var DefaultRouteCosts = map[string]int{
+	"/api/v1/search":        5,
+	"/api/v1/compare":       5,
+	"/api/v1/links/resolve": 2,
+	"/api/v1/cart/quote":    2,
+	"/api/v1/auth/login":    3,
+	"/api/v1/auth/register": 3,
+}
+
+// tokenBucketScript refills the bucket in KEYS[1] for the time elapsed since
+// the last call, then takes the cost if enough tokens are left. It runs
+// atomically, so concurrent requests cannot overdraw the bucket, and always
+// leaves a TTL on the key.
+//
+// ARGV: capacity, refill rate in tokens per millisecond, now in unix
+// milliseconds, cost. Returns {allowed, remaining tokens, ms until full,
+// ms until the cost is available}.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = capacity
+  ts = now
+end
+if now > ts then
+  tokens = math.min(capacity, tokens + (now - ts) * rate)
+  ts = now
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= cost then
+  tokens = tokens - cost
+  allowed = 1
+else
+  retry = math.ceil((cost - tokens) / rate)
+end
+
+local reset = math.ceil((capacity - tokens) / rate)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('PEXPIRE', KEYS[1], reset + 1000)
+return {allowed, math.floor(tokens), reset, retry}
+`)
+
+// RateLimitTier is a token bucket of Limit tokens that refills completely
+// over Window.
+type RateLimitTier struct {
+	Limit  int
+	Window time.Duration
+}
+
+// FailurePolicy decides how requests are limited while Redis is unavailable.
+type FailurePolicy string
+
+const (
+	// FailOpen limits requests with the in-process limiter, per instance.
+	FailOpen FailurePolicy = "open"
+	// FailClosed rejects requests with 503 until Redis is back.
+	FailClosed FailurePolicy = "closed"
+)
+
+// RateLimiter limits requests per caller with a token bucket in Redis.
+// Signed-in callers are limited per user under the SignedIn tier; anonymous
+// callers per X-Device-ID under the Anonymous tier. While Redis is
+// unavailable, requests are handled according to Policy.
+type RateLimiter struct {
+	RedisClient *redis.Client
+	Anonymous   RateLimitTier
+	SignedIn    RateLimitTier
+	Policy      FailurePolicy
+
+	costs map[string]int
+	now   func() time.Time
+
+	local *localLimiter
+	// redisDownUntil (unix nanoseconds) skips Redis for a cooldown after a
+	// failure so requests do not each wait for it to time out.
+	redisDownUntil atomic.Int64
+	degraded       atomic.Bool
+	stats          rateLimiterCounters
+}
+
+// Defaults used when the configured limit or window is not positive.
+const (
+	DefaultRateLimit       = 60
+	DefaultRateLimitWindow = time.Minute
+)
+
+const (
+	// redisLimitTimeout bounds a single Redis check.
+	redisLimitTimeout = 250 * time.Millisecond
+	// redisRetryCooldown is how long Redis is skipped after it failed.
+	redisRetryCooldown = 5 * time.Second
+)
+
+// NewRateLimiter creates a limiter allowing limit requests per window in
+// both tiers, with DefaultRouteCosts and the FailOpen policy. rdb is the
+// platform Redis client; when nil, only the in-process limiter is used.
+func NewRateLimiter(rdb *redis.Client, limit int, window time.Duration) *RateLimiter {
+	if limit <= 0 {
+		limit = DefaultRateLimit
+	}
+	if window <= 0 {
+		window = DefaultRateLimitWindow
+	}
+
+	costs := make(map[string]int, len(DefaultRouteCosts))
+	for route, cost := range DefaultRouteCosts {
+		costs[route] = cost
+	}
+	tier := RateLimitTier{Limit: limit, Window: window}
+	return &RateLimiter{
+		RedisClient: rdb,
+		Anonymous:   tier,
+		SignedIn:    tier,
+		Policy:      FailOpen,
+		costs:       costs,
+		now:         time.Now,
+		local:       newLocalLimiter(),
+	}
+}
+
+// WithFailurePolicy sets the policy used while Redis is unavailable. Unknown
+// values keep FailOpen.
+func (rl *RateLimiter) WithFailurePolicy(policy FailurePolicy) *RateLimiter {
+	if policy == FailClosed {
+		rl.Policy = FailClosed
+	}
+	return rl
+}
+
+// WithSignedInTier sets the limit for signed-in users. Non-positive values
+// keep the anonymous tier's.
+func (rl *RateLimiter) WithSignedInTier(limit int, window time.Duration) *RateLimiter {
+	if limit > 0 {
+		rl.SignedIn.Limit = limit
+	}
+	if window > 0 {
+		rl.SignedIn.Window = window
+	}
+	return rl
+}
+
+// WithRouteCosts overrides route costs, keyed by route pattern such as
+// "/api/v1/search". Costs below 1 are ignored.
+func (rl *RateLimiter) WithRouteCosts(costs map[string]int) *RateLimiter {
+	for route, cost := range costs {
+		if cost >= 1 {
+			rl.costs[route] = cost
+		}
+	}
+	return rl
+}
+
+type bucketResult struct {
+	allowed    bool
+	remaining  int
+	reset      time.Duration
+	retryAfter time.Duration
+}
+
+// errRedisUnavailable is returned by take while Redis is skipped.
+var errRedisUnavailable = errors.New("redis unavailable")
+
+// take checks the Redis bucket, falling back to the in-process limiter under
+// FailOpen. It returns errRedisUnavailable under FailClosed.
+func (rl *RateLimiter) take(ctx context.Context, key string, tier RateLimitTier, cost int) (bucketResult, error) {
+	now := rl.now()
+	if rl.RedisClient != nil && now.UnixNano() >= rl.redisDownUntil.Load() {
+		res, err := rl.takeRedis(ctx, key, tier, cost, now)
+		if err == nil {
+			rl.recovered()
+			return res, nil
+		}
+		rl.stats.redisErrors.Add(1)
+		rl.redisDownUntil.Store(now.Add(redisRetryCooldown).UnixNano())
+		rl.degrade(err)
+	}
+
+	if rl.Policy == FailClosed {
+		rl.stats.failedClosed.Add(1)
+		return bucketResult{}, errRedisUnavailable
+	}
+	rl.stats.fallbackRequests.Add(1)
+	return rl.local.take(key, tier, cost, now), nil
+}
+
+// takeRedis runs the bucket script. The check is detached from the caller's
+// cancellation so a client hanging up is not mistaken for a Redis failure;
+// redisLimitTimeout still bounds it.
+func (rl *RateLimiter) takeRedis(ctx context.Context, key string, tier RateLimitTier, cost int, now time.Time) (bucketResult, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), redisLimitTimeout)
+	defer cancel()
+	rate := float64(tier.Limit) / float64(tier.Window.Milliseconds())
+	vals, err := tokenBucketScript.Run(ctx, rl.RedisClient, []string{key},
+		tier.Limit, strconv.FormatFloat(rate, 'f', -1, 64), now.UnixMilli(), cost).Int64Slice()
+	if err != nil {
+		return bucketResult{}, err
+	}
+	if len(vals) != 4 {
+		return bucketResult{}, fmt.Errorf("unexpected rate limit reply %v", vals)
+	}
+	return bucketResult{
+		allowed:    vals[0] == 1,
+		remaining:  int(vals[1]),
+		reset:      time.Duration(vals[2]) * time.Millisecond,
+		retryAfter: time.Duration(vals[3]) * time.Millisecond,
+	}, nil
+}
+
+// cost returns the tokens a request to route takes, at most the tier's limit
+// so every route stays reachable.
+func (rl *RateLimiter) cost(route string, tier RateLimitTier) int {
+	cost, ok := rl.costs[route]
+	if !ok {
+		cost = 1
+	}
+	if cost > tier.Limit {
+		cost = tier.Limit
+	}
+	return cost
+}
+
+// Middleware enforces the limit and reports it in X-RateLimit-Limit,
+// X-RateLimit-Remaining and X-RateLimit-Reset (seconds until the bucket is
+// full again). It must run after OptionalAuth.
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user, ok := c.Request.Context().Value(contextkeys.User).(*domain.User); ok && user != nil {
+			rl.limit(c, "rate:user:"+user.ID, rl.SignedIn)
+		} else if deviceID := c.GetHeader("X-Device-ID"); deviceID != "" {
+			rl.limit(c, "rate:device:"+deviceID, rl.Anonymous)
+		} else {
+			apierror.Respond(c, domain.Validation("X-Device-ID header is required"))
+		}
+	}
+}
+
+// PerClientIP limits by client IP under the Anonymous tier. It is meant for
+// routes browsers reach by navigation, such as OAuth redirects, which
+// cannot carry X-Device-ID.
+func (rl *RateLimiter) PerClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rl.limit(c, "rate:ip:"+c.ClientIP(), rl.Anonymous)
+	}
+}
+
+// limit takes the route's cost from the bucket in key and aborts the
+// request when it cannot be covered.
+func (rl *RateLimiter) limit(c *gin.Context, key string, tier RateLimitTier) {
+	ctx := c.Request.Context()
+	res, err := rl.take(ctx, key, tier, rl.cost(c.FullPath(), tier))
+	if err != nil {
+		metrics.RateLimitRejections.WithLabelValues(c.FullPath(), "unavailable").Inc()
+		unavailable := domain.UpstreamUnavailable("Rate limiter unavailable", err)
+		unavailable.RetryAfter = redisRetryCooldown
+		apierror.Respond(c, unavailable)
+		return
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(tier.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(res.remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.reset)))
+
+	// Block if the bucket cannot cover the cost
+	if !res.allowed {
+		metrics.RateLimitRejections.WithLabelValues(c.FullPath(), "limited").Inc()
+		apierror.Respond(c, domain.RateLimited("Rate limit exceeded", res.retryAfter))
+		return
+	}
+
+	c.Next()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// rateLimiterCounters count requests the limiter could not check in Redis.
+type rateLimiterCounters struct {
+	redisErrors      atomic.Uint64
+	fallbackRequests atomic.Uint64
+	failedClosed     atomic.Uint64
+}
+
+// RateLimiterStats is a snapshot of the limiter's degradation metrics.
+type RateLimiterStats struct {
+	// Degraded is true while requests are not checked in Redis.
+	Degraded bool
+	// RedisErrors counts failed Redis checks.
+	RedisErrors uint64
+	// FallbackRequests counts requests checked by the in-process limiter.
+	FallbackRequests uint64
+	// FailedClosed counts requests rejected because Redis was unavailable.
+	FailedClosed uint64
+}
+
+// Stats returns the limiter's degradation metrics.
+func (rl *RateLimiter) Stats() RateLimiterStats {
+	return RateLimiterStats{
+		Degraded:         rl.degraded.Load() || rl.RedisClient == nil,
+		RedisErrors:      rl.stats.redisErrors.Load(),
+		FallbackRequests: rl.stats.fallbackRequests.Load(),
+		FailedClosed:     rl.stats.failedClosed.Load(),
+	}
+}
+
+// RegisterMetrics exports Stats to reg.
+func (rl *RateLimiter) RegisterMetrics(reg prometheus.Registerer) error {
+	opts := func(name, help string) prometheus.Opts {
+		return prometheus.Opts{Namespace: "shopally", Subsystem: "rate_limit", Name: name, Help: help}
+	}
+	for _, c := range []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts(opts("degraded", "1 while requests are not checked in Redis.")), func() float64 {
+			if rl.Stats().Degraded {
+				return 1
+			}
+			return 0
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts(opts("redis_errors_total", "Failed Redis checks.")), func() float64 {
+			return float64(rl.stats.redisErrors.Load())
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts(opts("fallback_requests_total", "Requests checked by the in-process limiter.")), func() float64 {
+			return float64(rl.stats.fallbackRequests.Load())
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts(opts("failed_closed_total", "Requests rejected because Redis was unavailable under the closed policy.")), func() float64 {
+			return float64(rl.stats.failedClosed.Load())
+		}),
+	} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rl *RateLimiter) degrade(err error) {
+	if rl.degraded.CompareAndSwap(false, true) {
+		rateLimitLog.Warn("rate limiter degraded", "policy", rl.Policy, logging.Err(err))
+	}
+}
+
+func (rl *RateLimiter) recovered() {
+	if rl.degraded.CompareAndSwap(true, false) {
+		rateLimitLog.Info("rate limiter recovered, using Redis again")
+	}
+}
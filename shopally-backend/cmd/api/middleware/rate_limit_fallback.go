@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// localSweepInterval is how often idle buckets are dropped from memory.
+const localSweepInterval = time.Minute
+
+// localLimiter is the in-process token bucket used while Redis is
+// unavailable. It follows tokenBucketScript, but each instance counts only
+// its own requests.
+type localLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*localBucket
+	lastSweep time.Time
+}
+
+type localBucket struct {
+	tokens float64
+	ts     time.Time
+	full   time.Time // when the bucket will have refilled completely
+}
+
+func newLocalLimiter() *localLimiter {
+	return &localLimiter{buckets: map[string]*localBucket{}}
+}
+
+func (l *localLimiter) take(key string, tier RateLimitTier, cost int, now time.Time) bucketResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	capacity := float64(tier.Limit)
+	rate := capacity / float64(tier.Window.Milliseconds()) // tokens per millisecond
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &localBucket{tokens: capacity, ts: now}
+		l.buckets[key] = b
+	}
+	if now.After(b.ts) {
+		elapsed := float64(now.Sub(b.ts).Milliseconds())
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.ts = now
+	}
+
+	var res bucketResult
+	if b.tokens >= float64(cost) {
+		b.tokens -= float64(cost)
+		res.allowed = true
+	} else {
+		res.retryAfter = time.Duration(math.Ceil((float64(cost)-b.tokens)/rate)) * time.Millisecond
+	}
+	res.remaining = int(math.Floor(b.tokens))
+	res.reset = time.Duration(math.Ceil((capacity-b.tokens)/rate)) * time.Millisecond
+	b.full = now.Add(res.reset)
+	return res
+}
+
+// sweep drops buckets that have refilled, as they equal a fresh bucket.
+func (l *localLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < localSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/metrics"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(t *testing.T, limit int) (*RateLimiter, *time.Time) {
+	rl, _, now := newTestLimiterWithRedis(t, limit)
+	return rl, now
+}
+
+func newTestLimiterWithRedis(t *testing.T, limit int) (*RateLimiter, *miniredis.Miniredis, *time.Time) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(rdb, limit, time.Minute)
+	rl.now = func() time.Time { return now }
+	return rl, mr, &now
+}
+
+func limitedEngine(rl *RateLimiter, user *domain.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if user != nil {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextkeys.User, user))
+		}
+	}, rl.Middleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/v1/limited", ok)
+	r.GET("/api/v1/search", ok)
+	return r
+}
+
+func get(r http.Handler, path, device string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if device != "" {
+		req.Header.Set("X-Device-ID", device)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var res struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res), w.Body.String())
+	return res.Error.Code
+}
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	rl, now := newTestLimiter(t, 10)
+	r := limitedEngine(rl, nil)
+
+	w := get(r, "/api/v1/limited", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "INVALID_INPUT", errorCode(t, w))
+
+	w = get(r, "/api/v1/search", "dev-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "10", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "5", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("X-RateLimit-Reset"))
+
+	for i := 0; i < 5; i++ {
+		require.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-1").Code)
+	}
+	rejected := metrics.RateLimitRejections.WithLabelValues("/api/v1/limited", "limited")
+	before := testutil.ToFloat64(rejected)
+	w = get(r, "/api/v1/limited", "dev-1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "RATE_LIMITED", errorCode(t, w))
+	assert.Equal(t, before+1, testutil.ToFloat64(rejected))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "6", w.Header().Get("Retry-After"))
+
+	// Other devices have their own bucket.
+	assert.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-2").Code)
+
+	// Tokens refill continuously rather than at a window edge.
+	*now = now.Add(6 * time.Second)
+	assert.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get(r, "/api/v1/search", "dev-1").Code)
+	*now = now.Add(time.Hour)
+	w = get(r, "/api/v1/search", "dev-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5", w.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestRateLimiter_SignedInTierAndCosts(t *testing.T) {
+	rl, _ := newTestLimiter(t, 10)
+	rl.WithSignedInTier(100, 0).WithRouteCosts(map[string]int{"/api/v1/search": 20, "/api/v1/limited": 0})
+	r := limitedEngine(rl, &domain.User{ID: "u1"})
+
+	// Signed-in callers need no device ID and get their own tier.
+	w := get(r, "/api/v1/search", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "100", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "80", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = get(r, "/api/v1/limited", "")
+	assert.Equal(t, "79", w.Header().Get("X-RateLimit-Remaining"))
+
+	// A cost above the anonymous limit is capped so the route stays reachable.
+	anon := limitedEngine(rl, nil)
+	w = get(anon, "/api/v1/search", "dev-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestRateLimiter_RedisUnavailable(t *testing.T) {
+	t.Run("fails open to the in-process limiter and recovers", func(t *testing.T) {
+		rl, mr, now := newTestLimiterWithRedis(t, 2)
+		r := limitedEngine(rl, nil)
+		require.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-1").Code)
+
+		mr.SetError("LOADING")
+		assert.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-1").Code)
+		assert.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-1").Code)
+		w := get(r, "/api/v1/limited", "dev-1")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+
+		stats := rl.Stats()
+		assert.True(t, stats.Degraded)
+		assert.Equal(t, uint64(1), stats.RedisErrors, "Redis is skipped during the cooldown")
+		assert.Equal(t, uint64(3), stats.FallbackRequests)
+
+		mr.SetError("")
+		*now = now.Add(redisRetryCooldown)
+		assert.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-1").Code)
+		assert.False(t, rl.Stats().Degraded)
+	})
+
+	t.Run("fails closed", func(t *testing.T) {
+		rl, mr, _ := newTestLimiterWithRedis(t, 2)
+		rl.WithFailurePolicy(FailClosed)
+		r := limitedEngine(rl, nil)
+
+		mr.SetError("LOADING")
+		w := get(r, "/api/v1/limited", "dev-1")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "UPSTREAM_UNAVAILABLE", errorCode(t, w))
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		assert.Equal(t, uint64(1), rl.Stats().FailedClosed)
+
+		reg := prometheus.NewRegistry()
+		require.NoError(t, rl.RegisterMetrics(reg))
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP shopally_rate_limit_failed_closed_total Requests rejected because Redis was unavailable under the closed policy.
+# TYPE shopally_rate_limit_failed_closed_total counter
+shopally_rate_limit_failed_closed_total 1
+`), "shopally_rate_limit_failed_closed_total"))
+	})
+
+	t.Run("a cancelled request is not a Redis failure", func(t *testing.T) {
+		rl, _, _ := newTestLimiterWithRedis(t, 2)
+		rl.WithFailurePolicy(FailClosed)
+		r := limitedEngine(rl, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/limited", nil).WithContext(ctx)
+		req.Header.Set("X-Device-ID", "dev-1")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, rl.Stats().Degraded)
+		assert.Zero(t, rl.Stats().RedisErrors)
+		assert.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-2").Code)
+	})
+
+	t.Run("runs in-process without a Redis client", func(t *testing.T) {
+		rl := NewRateLimiter(nil, 1, time.Minute)
+		r := limitedEngine(rl, nil)
+		assert.Equal(t, http.StatusOK, get(r, "/api/v1/limited", "dev-1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(r, "/api/v1/limited", "dev-1").Code)
+		assert.True(t, rl.Stats().Degraded)
+	})
+}
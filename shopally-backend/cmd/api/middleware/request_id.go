@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopally-ai/internal/contextkeys"
+)
+
+// RequestIDHeader carries the correlation ID of a request.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds a client-supplied request ID.
+const maxRequestIDLength = 128
+
+// RequestID stores the caller's X-Request-ID, or a generated one when it is
+// missing or malformed, under contextkeys.RequestID and echoes it in the
+// response.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextkeys.RequestID, id))
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID accepts IDs that are safe to log and forward: letters,
+// digits and . _ : - up to maxRequestIDLength.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '.', r == '_', r == ':', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
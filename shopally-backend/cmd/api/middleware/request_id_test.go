@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var seen string
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/", func(c *gin.Context) {
+		seen, _ = c.Request.Context().Value(contextkeys.RequestID).(string)
+	})
+
+	do := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if id != "" {
+			req.Header.Set(RequestIDHeader, id)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("abc-123")
+	assert.Equal(t, "abc-123", seen)
+	assert.Equal(t, "abc-123", w.Header().Get(RequestIDHeader))
+
+	for _, bad := range []string{"", "has space", "line\nbreak", strings.Repeat("a", 129)} {
+		w = do(bad)
+		assert.NotEqual(t, bad, seen)
+		assert.Len(t, seen, 36, "a UUID is generated for %q", bad)
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+	}
+}
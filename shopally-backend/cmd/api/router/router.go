@@ -5,19 +5,47 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopally-ai/cmd/api/middleware"
+	"github.com/shopally-ai/internal/adapter/apierror"
 	"github.com/shopally-ai/internal/adapter/handler"
 	"github.com/shopally-ai/internal/config"
 	"github.com/shopally-ai/pkg/domain"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func SetupRouter(cfg *config.Config, limiter *middleware.RateLimiter, searchHandler *handler.SearchHandler, compareHandler *handler.CompareHandler, alertHandler *handler.AlertHandler) *gin.Engine {
-	router := gin.Default()
+func SetupRouter(cfg *config.Config, limiter *middleware.RateLimiter, searchHandler *handler.SearchHandler, compareHandler *handler.CompareHandler, alertHandler *handler.AlertHandler, productHandler *handler.ProductHandler, linkHandler *handler.LinkHandler, cartHandler *handler.CartHandler, savedItemHandler *handler.SavedItemHandler, authHandler *handler.AuthHandler, googleHandler *handler.OAuthHandler, affiliateHandler *handler.AffiliateHandler, preferencesHandler *handler.PreferencesHandler, auth middleware.Authenticator, affiliates middleware.AffiliateSource, preferences middleware.PreferencesSource) *gin.Engine {
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.Use(
+		middleware.RequestID(),
+		otelgin.Middleware("shopally-api"),
+		middleware.RequestLogger(),
+		middleware.Metrics(),
+		gin.CustomRecovery(apierror.Recover),
+	)
+	router.NoRoute(apierror.NoRoute)
+	router.NoMethod(apierror.NoMethod)
 
 	version1 := router.Group("/api/v1")
+	version1.Use(middleware.OptionalAuth(auth))
+	if affiliates != nil {
+		version1.Use(middleware.AffiliateCredentials(affiliates))
+	}
+	if preferences != nil {
+		version1.Use(middleware.Preferences(preferences))
+	}
 
 	// Health checker
 	version1.GET("/health", handler.Health)
 
+	// Browser redirects cannot carry X-Device-ID, so the OAuth routes are
+	// limited per client IP instead.
+	if googleHandler != nil {
+		google := version1.Group("/auth/google", limiter.PerClientIP())
+		google.GET("/start", googleHandler.Start)
+		google.GET("/callback", googleHandler.Callback)
+		google.POST("/exchange", googleHandler.Exchange)
+	}
+
 	// private
 	limitedRouter := version1.Group("")
 	limitedRouter.Use(limiter.Middleware())
@@ -25,10 +53,41 @@ func SetupRouter(cfg *config.Config, limiter *middleware.RateLimiter, searchHand
 		limitedRouter.GET("/limited", func(c *gin.Context) {
 			c.JSON(http.StatusOK, domain.Response{Data: map[string]interface{}{"message": "limited message"}})
 		})
-		limitedRouter.POST("/compare", func(c *gin.Context) {
-			c.JSON(http.StatusOK, domain.Response{Data: map[string]interface{}{"message": "limited message"}})
-		})
+		limitedRouter.POST("/compare", compareHandler.CompareProducts)
+		limitedRouter.GET("/comparisons/:id", compareHandler.GetComparison)
 		limitedRouter.GET("/search", searchHandler.Search)
+		limitedRouter.GET("/products/:id", productHandler.GetProduct)
+		limitedRouter.GET("/products/:id/price-history", productHandler.GetPriceHistory)
+		limitedRouter.GET("/links/resolve", linkHandler.ResolveLink)
+		limitedRouter.POST("/cart/quote", cartHandler.QuoteCart)
+
+		// Saved items
+		limitedRouter.POST("/saved-items", savedItemHandler.Save)
+		limitedRouter.GET("/saved-items", savedItemHandler.List)
+		limitedRouter.GET("/saved-items/lists", savedItemHandler.Lists)
+		limitedRouter.PATCH("/saved-items/:id", savedItemHandler.Move)
+		limitedRouter.DELETE("/saved-items/:id", savedItemHandler.Remove)
+		limitedRouter.POST("/saved-items/:id/alert", savedItemHandler.CreateAlert)
+
+		// Accounts
+		limitedRouter.POST("/auth/register", authHandler.Register)
+		limitedRouter.POST("/auth/login", authHandler.Login)
+		limitedRouter.POST("/auth/refresh", authHandler.Refresh)
+		limitedRouter.POST("/auth/logout", authHandler.Logout)
+		limitedRouter.GET("/auth/me", middleware.RequireAuth(auth), authHandler.Me)
+		limitedRouter.POST("/me/device/claim", middleware.RequireAuth(auth), authHandler.ClaimDevice)
+		limitedRouter.GET("/me/preferences", preferencesHandler.Get)
+		limitedRouter.PUT("/me/preferences", preferencesHandler.Update)
+
+		// Linked AliExpress account
+		if affiliateHandler != nil {
+			me := limitedRouter.Group("/me/aliexpress", middleware.RequireAuth(auth))
+			me.GET("", affiliateHandler.Get)
+			me.PUT("", affiliateHandler.SetTrackingID)
+			me.DELETE("", affiliateHandler.Unlink)
+			me.POST("/link/start", affiliateHandler.StartLink)
+			me.POST("/link", affiliateHandler.CompleteLink)
+		}
 
 		// Alerts endpoints
 		limitedRouter.POST("/alerts", alertHandler.CreateAlertHandler)
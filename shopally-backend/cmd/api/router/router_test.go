@@ -0,0 +1,146 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/shopally-ai/cmd/api/middleware"
+	"github.com/shopally-ai/internal/adapter/handler"
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/internal/config"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider sends the browser to a consent page carrying the state.
+type fakeProvider struct{}
+
+func (fakeProvider) AuthCodeURL(state, verifier string) string {
+	return "https://provider.test/consent?state=" + url.QueryEscape(state)
+}
+
+func (fakeProvider) Exchange(ctx context.Context, code, verifier string) (*domain.OAuthIdentity, error) {
+	return nil, domain.ErrOAuthCodeRejected
+}
+
+func TestSetupRouter_GoogleRoutesLimitedPerClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := usecase.NewAuthUseCase(
+		repository.NewMockUserRepository(),
+		repository.NewMockSessionRepository(),
+		usecase.NewTokenService("test-secret", "shopally", time.Minute, time.Hour),
+	)
+	google := handler.NewOAuthHandler(usecase.NewOAuthLoginUseCase(fakeProvider{}, repository.NewMockUserRepository(), auth), domain.IdentityProviderGoogle)
+	limiter := middleware.NewRateLimiter(nil, 2, time.Minute)
+	r := SetupRouter(&config.Config{}, limiter, nil, nil, nil, nil, nil, nil, nil, handler.NewAuthHandler(auth), google, nil, nil, auth, nil, nil)
+
+	start := func(ip string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/start", nil)
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// A browser navigation carries no X-Device-ID.
+	w := start("203.0.113.1")
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, http.StatusFound, start("203.0.113.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, start("203.0.113.1").Code)
+	assert.Equal(t, http.StatusFound, start("203.0.113.2").Code, "limits are per client IP")
+
+	// Device-keyed routes still need the header.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSetupRouter_ExpiredAccessTokenCanRefresh(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := usecase.NewAuthUseCase(
+		repository.NewMockUserRepository(),
+		repository.NewMockSessionRepository(),
+		usecase.NewTokenService("test-secret", "shopally", time.Minute, time.Hour),
+	)
+	limiter := middleware.NewRateLimiter(nil, 100, time.Minute)
+	r := SetupRouter(&config.Config{}, limiter, nil, nil, nil, nil, nil, nil, nil, handler.NewAuthHandler(auth), nil, nil, nil, auth, nil, nil)
+
+	user, tokens, err := auth.Register(context.Background(), "a@example.com", "correct horse", "")
+	require.NoError(t, err)
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, usecase.TokenClaims{
+		Kind: usecase.TokenKindAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID,
+			Issuer:    "shopally",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+expired)
+		req.Header.Set("X-Device-ID", "device-1")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/v1/auth/refresh", `{"refreshToken":"`+tokens.RefreshToken+`"}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"accessToken"`)
+
+	w = send(http.MethodGet, "/api/v1/auth/me", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSetupRouter_ErrorEnvelopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := usecase.NewAuthUseCase(
+		repository.NewMockUserRepository(),
+		repository.NewMockSessionRepository(),
+		usecase.NewTokenService("test-secret", "shopally", time.Minute, time.Hour),
+	)
+	limiter := middleware.NewRateLimiter(nil, 100, time.Minute)
+	// No search handler: the route panics.
+	r := SetupRouter(&config.Config{}, limiter, nil, nil, nil, nil, nil, nil, nil, handler.NewAuthHandler(auth), nil, nil, nil, auth, nil, nil)
+
+	cases := []struct {
+		method, path string
+		status       int
+		code         string
+	}{
+		{http.MethodGet, "/api/v1/nope", http.StatusNotFound, "NOT_FOUND"},
+		{http.MethodGet, "/api/v1/auth/login", http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED"},
+		{http.MethodGet, "/api/v1/search?q=phone", http.StatusInternalServerError, "INTERNAL_SERVER_ERROR"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("X-Device-ID", "device-1")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tc.status, w.Code)
+			var res struct {
+				Data  interface{}       `json:"data"`
+				Error map[string]string `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res), w.Body.String())
+			assert.Nil(t, res.Data)
+			assert.Equal(t, tc.code, res.Error["code"])
+			assert.NotEmpty(t, res.Error["message"])
+		})
+	}
+}
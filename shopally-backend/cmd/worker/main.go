@@ -2,24 +2,35 @@ package main
 
 import (
 	"context"
-	"log"
 	"os"
 	"time"
 
 	"github.com/shopally-ai/internal/adapter/gateway"
 	"github.com/shopally-ai/internal/config"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/metrics"
 	"github.com/shopally-ai/internal/platform"
 )
 
+var workerLog = logging.Component("worker")
+
 func main() {
 	cfg, err := config.LoadConfig(".")
 	if err != nil {
-		log.Fatalf("config: %v", err)
+		workerLog.Error("config", logging.Err(err))
+		os.Exit(1)
 	}
+	logging.Setup(logging.Config{
+		Level:         cfg.Logging.Level,
+		Format:        cfg.Logging.Format,
+		RedactQueries: cfg.Logging.RedactQueries,
+	})
+	metrics.Serve(cfg.Metrics.WorkerAddr)
 
 	rc := platform.NewRedisClient(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password, cfg.Redis.DB)
 	if err := rc.Ping(context.Background()); err != nil {
-		log.Fatalf("redis ping: %v", err)
+		workerLog.Error("redis ping", logging.Err(err))
+		os.Exit(1)
 	}
 	cache := gateway.NewRedisCache(rc.Client, cfg.Redis.KeyPrefix)
 
@@ -32,30 +43,29 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		if rate, err := fx.GetRate(ctx, "USD", "ETB"); err != nil {
-			log.Printf("worker warm fx error: %v", err)
+			workerLog.Warn("warming FX rate failed", logging.Err(err))
 		} else {
-			log.Printf("worker warm fx USD->ETB: %.6f", rate)
+			workerLog.Info("warmed FX rate", "pair", "USD/ETB", "rate", rate)
 		}
 	}
 
-	warm()
-	ticker := time.NewTicker(30 * time.Minute)
-	defer ticker.Stop()
-	for range ticker.C {
-		warm()
-	}
-
 	ctx := context.Background()
 
 	fcm, err := gateway.NewFCMGateway(ctx, gateway.FCMGatewayConfig{})
 	if err != nil {
-		log.Printf("FCM init failed (alerts disabled): %v", err)
+		workerLog.Warn("FCM init failed, alerts disabled", logging.Err(err))
 	} else if t := os.Getenv("FCM_TEST_TOKEN"); t != "" {
 		if _, err := fcm.Send(ctx, t, "ShopAlly Alerts Ready", "Worker can send push notifications.", nil); err != nil {
-			log.Printf("FCM test send failed: %v", err)
+			workerLog.Warn("FCM test send failed", logging.Err(err))
 		}
 	}
 
 	// TODO: Pass `fcm` into the alerts worker when B2.4 is ready
 
+	warm()
+	ticker := time.NewTicker(30 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		warm()
+	}
 }
@@ -0,0 +1,151 @@
+// Package apierror renders domain errors as HTTP responses. Handlers and
+// middleware share it so every error reaches clients in one envelope:
+//
+//	{"data": null, "error": {"code": "NOT_FOUND", "message": "alert not found"}}
+package apierror
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+var log = logging.Component("apierror")
+
+// Body is the error member of the response envelope.
+type Body struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type response struct {
+	Data  interface{} `json:"data"`
+	Error Body        `json:"error"`
+}
+
+// kinds maps each error kind to its status and default code.
+var kinds = map[domain.ErrorKind]struct {
+	status int
+	code   string
+}{
+	domain.KindValidation:          {http.StatusBadRequest, "INVALID_INPUT"},
+	domain.KindUnauthorized:        {http.StatusUnauthorized, "UNAUTHORIZED"},
+	domain.KindForbidden:           {http.StatusForbidden, "FORBIDDEN"},
+	domain.KindNotFound:            {http.StatusNotFound, "NOT_FOUND"},
+	domain.KindConflict:            {http.StatusConflict, "CONFLICT"},
+	domain.KindRateLimited:         {http.StatusTooManyRequests, "RATE_LIMITED"},
+	domain.KindUpstreamUnavailable: {http.StatusServiceUnavailable, "UPSTREAM_UNAVAILABLE"},
+	domain.KindInternal:            {http.StatusInternalServerError, "INTERNAL_SERVER_ERROR"},
+}
+
+// internalMessage replaces the details of internal errors.
+const internalMessage = "An unexpected error occurred."
+
+// messages holds the localized messages by code. English responses use the
+// error's own message, as do other languages for codes without a
+// translation.
+var messages = map[string]map[string]string{
+	"am": {
+		"INVALID_INPUT":         "ጥያቄው ትክክል አይደለም።",
+		"UNAUTHORIZED":          "እባክዎ እንደገና ይግቡ።",
+		"INVALID_CREDENTIALS":   "ኢሜይል ወይም የይለፍ ቃል ትክክል አይደለም።",
+		"INVALID_TOKEN":         "ክፍለ ጊዜው አልፏል። እባክዎ እንደገና ይግቡ።",
+		"OAUTH_FAILED":          "መግባት አልተሳካም።",
+		"FORBIDDEN":             "ይህን ለማድረግ ፈቃድ የለዎትም።",
+		"NOT_FOUND":             "የተጠየቀው አልተገኘም።",
+		"METHOD_NOT_ALLOWED":    "ይህ ጥያቄ በዚህ አድራሻ አይፈቀድም።",
+		"CONFLICT":              "ይህ አስቀድሞ አለ።",
+		"RATE_LIMITED":          "በጣም ብዙ ጥያቄዎች። እባክዎ ቆየት ብለው ይሞክሩ።",
+		"UPSTREAM_UNAVAILABLE":  "አገልግሎቱ ለጊዜው አይገኝም። እባክዎ ቆየት ብለው ይሞክሩ።",
+		"INTERNAL_SERVER_ERROR": "ያልተጠበቀ ስህተት ተፈጥሯል።",
+	},
+}
+
+// Respond writes err as the error envelope and aborts the request. The
+// status and code follow the error's domain.ErrorKind; errors of no known
+// kind are logged and reported as internal errors without their details.
+func Respond(c *gin.Context, err error) {
+	status, body, retryAfter := Render(Language(c), err)
+	if status >= http.StatusInternalServerError {
+		log.ErrorContext(c.Request.Context(), "request failed", "status", status, "route", c.FullPath(), logging.Err(err))
+	}
+	if retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+	}
+	c.AbortWithStatusJSON(status, response{Data: nil, Error: body})
+}
+
+// NoRoute answers requests that match no route.
+func NoRoute(c *gin.Context) {
+	Respond(c, domain.NotFound("route not found"))
+}
+
+// NoMethod answers requests to a route that does not accept their method.
+func NoMethod(c *gin.Context) {
+	body := Body{Code: "METHOD_NOT_ALLOWED", Message: "method not allowed"}
+	if msg, ok := messages[Language(c)][body.Code]; ok {
+		body.Message = msg
+	}
+	c.AbortWithStatusJSON(http.StatusMethodNotAllowed, response{Data: nil, Error: body})
+}
+
+// Recover answers a request whose handler panicked, for gin.CustomRecovery.
+func Recover(c *gin.Context, recovered any) {
+	Respond(c, fmt.Errorf("panic: %v", recovered))
+}
+
+// Render returns the status, envelope error and Retry-After seconds for err
+// in lang.
+func Render(lang string, err error) (int, Body, int) {
+	kind := domain.KindOf(err)
+	k, ok := kinds[kind]
+	if !ok {
+		k = kinds[domain.KindInternal]
+	}
+	body := Body{Code: k.code, Message: internalMessage}
+
+	// Only the message and detail of a domain error reach clients; wrapped
+	// causes and context added with fmt.Errorf stay in the logs.
+	var retryAfter int
+	if e, ok := domain.AsError(err); ok {
+		if kind != domain.KindInternal {
+			body.Message = e.PublicMessage()
+		}
+		if e.Code != "" {
+			body.Code = e.Code
+		}
+		if e.RetryAfter > 0 {
+			retryAfter = int(math.Ceil(e.RetryAfter.Seconds()))
+		}
+	}
+	if msg, ok := messages[lang][body.Code]; ok {
+		body.Message = msg
+	}
+	return k.status, body, retryAfter
+}
+
+// Language is the language of response messages: the stored preference,
+// else Accept-Language ("am" or English).
+func Language(c *gin.Context) string {
+	lang := strings.ToLower(strings.TrimSpace(c.GetHeader("Accept-Language")))
+	if prefs := storedPreferences(c.Request.Context()); prefs != nil && prefs.Language != "" {
+		lang = prefs.Language
+	}
+	if lang != "am" {
+		return "en"
+	}
+	return lang
+}
+
+func storedPreferences(ctx context.Context) *domain.Preferences {
+	prefs, _ := ctx.Value(contextkeys.Preferences).(*domain.Preferences)
+	return prefs
+}
@@ -0,0 +1,77 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Kinds(t *testing.T) {
+	cases := []struct {
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{domain.Validation("query is required"), http.StatusBadRequest, "INVALID_INPUT", "query is required"},
+		{domain.Unauthorized("bad token").WithCode("INVALID_TOKEN"), http.StatusUnauthorized, "INVALID_TOKEN", "bad token"},
+		{domain.Forbidden("email is not verified"), http.StatusForbidden, "FORBIDDEN", "email is not verified"},
+		{fmt.Errorf("get alert: %w", domain.ErrAlertNotFound), http.StatusNotFound, "NOT_FOUND", "alert not found"},
+		{domain.ErrProductNotFound.WithDetail("404"), http.StatusNotFound, "NOT_FOUND", "product not found: 404"},
+		{fmt.Errorf("%w: token has invalid claims: token is expired", domain.Unauthorized("invalid or expired token").WithCode("INVALID_TOKEN")), http.StatusUnauthorized, "INVALID_TOKEN", "invalid or expired token"},
+		{domain.ErrAlreadySaved, http.StatusConflict, "CONFLICT", "product is already saved"},
+		{domain.RateLimited("Rate limit exceeded", time.Second), http.StatusTooManyRequests, "RATE_LIMITED", "Rate limit exceeded"},
+		{domain.UpstreamUnavailable("AliExpress is unavailable", errors.New(`status 500: {"secret":"x"}`)), http.StatusServiceUnavailable, "UPSTREAM_UNAVAILABLE", "AliExpress is unavailable"},
+		{errors.New("mongo: connection refused"), http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", internalMessage},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code+"/"+tc.message, func(t *testing.T) {
+			status, body, _ := Render("en", tc.err)
+			assert.Equal(t, tc.status, status)
+			assert.Equal(t, Body{Code: tc.code, Message: tc.message}, body)
+		})
+	}
+}
+
+func TestRender_Localized(t *testing.T) {
+	_, body, _ := Render("am", domain.ErrProductNotFound)
+	assert.Equal(t, "NOT_FOUND", body.Code)
+	assert.Equal(t, messages["am"]["NOT_FOUND"], body.Message)
+
+	// Codes without a translation keep the error's message.
+	_, body, _ = Render("am", domain.Validation("bad").WithCode("INVALID_STATE"))
+	assert.Equal(t, "bad", body.Message)
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept-Language", "en")
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextkeys.Preferences, &domain.Preferences{Language: "am"}))
+
+	Respond(c, domain.RateLimited("Rate limit exceeded", 1500*time.Millisecond))
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Nil(t, res["data"])
+	assert.Equal(t, map[string]interface{}{
+		"code":    "RATE_LIMITED",
+		"message": messages["am"]["RATE_LIMITED"],
+	}, res["error"])
+}
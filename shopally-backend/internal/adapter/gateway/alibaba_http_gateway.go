@@ -8,7 +8,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"sort"
@@ -17,65 +16,76 @@ import (
 	"time"
 
 	"github.com/shopally-ai/internal/config"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/metrics"
 	"github.com/shopally-ai/pkg/domain"
 )
 
+var aliLog = logging.Component("aliexpress")
+
+// aliProduct mirrors a single product entry in the AliExpress affiliate API
+// responses. The product query and product detail endpoints share this shape.
+type aliProduct struct {
+	AppSalePrice        string `json:"app_sale_price"`
+	OriginalPrice       string `json:"original_price"`
+	ProductDetailURL    string `json:"product_detail_url"`
+	Discount            string `json:"discount"`
+	ProductMainImageURL string `json:"product_main_image_url"`
+	TaxRate             string `json:"tax_rate"`
+	ProductID           int64  `json:"product_id"`
+	ShipToDays          string `json:"ship_to_days"`
+	EvaluateRate        string `json:"evaluate_rate"`
+	SalePrice           string `json:"sale_price"`
+	ProductTitle        string `json:"product_title"`
+
+	TargetSalePrice            string `json:"target_sale_price"`
+	TargetAppSalePrice         string `json:"target_app_sale_price"`
+	ShopName                   string `json:"shop_name"`
+	TargetSalePriceCurrency    string `json:"target_sale_price_currency"`
+	TargetAppSalePriceCurrency string `json:"target_app_sale_price_currency"`
+
+	ProductSmallImageURLs struct {
+		String []string `json:"string"`
+	} `json:"product_small_image_urls"`
+	SecondLevelCategoryName     string `json:"second_level_category_name"`
+	SecondLevelCategoryID       int64  `json:"second_level_category_id"`
+	FirstLevelCategoryID        int64  `json:"first_level_category_id"`
+	FirstLevelCategoryName      string `json:"first_level_category_name"`
+	OriginalPriceCurrency       string `json:"original_price_currency"`
+	ShopURL                     string `json:"shop_url"`
+	TargetOriginalPriceCurrency string `json:"target_original_price_currency"`
+	TargetOriginalPrice         string `json:"target_original_price"`
+	ProductVideoURL             string `json:"product_video_url"`
+	PromotionLink               string `json:"promotion_link"`
+	SKUId                       int64  `json:"sku_id"`
+	HotProductCommissionRate    string `json:"hot_product_commission_rate"`
+	ShopID                      int64  `json:"shop_id"`
+	LastestVolume               int    `json:"lastest_volume"`
+	SalePriceCurrency           string `json:"sale_price_currency"`
+	CommissionRate              string `json:"commission_rate"`
+}
+
+// aliProductsResult is the result payload common to the product query and
+// product detail endpoints.
+type aliProductsResult struct {
+	CurrentRecordCount int `json:"current_record_count"`
+	TotalRecordCount   int `json:"total_record_count"`
+	CurrentPageNo      int `json:"current_page_no"`
+	Products           struct {
+		Product []aliProduct `json:"product"`
+	} `json:"products"`
+}
+
 // MapAliExpressResponseToProducts transforms the raw AliExpress API response JSON
 // into a slice of internal `domain.Product` pointers. It is resilient to missing
 // fields and uses sensible defaults/placeholders where mapping data is not
 // available from the upstream response.
-func MapAliExpressResponseToProducts(data []byte) ([]*domain.Product, error) {
-	type aliProduct struct {
-		AppSalePrice        string `json:"app_sale_price"`
-		OriginalPrice       string `json:"original_price"`
-		ProductDetailURL    string `json:"product_detail_url"`
-		Discount            string `json:"discount"`
-		ProductMainImageURL string `json:"product_main_image_url"`
-		TaxRate             string `json:"tax_rate"`
-		ProductID           int64  `json:"product_id"`
-		ShipToDays          string `json:"ship_to_days"`
-		EvaluateRate        string `json:"evaluate_rate"`
-		SalePrice           string `json:"sale_price"`
-		ProductTitle        string `json:"product_title"`
-
-		TargetSalePrice            string `json:"target_sale_price"`
-		TargetAppSalePrice         string `json:"target_app_sale_price"`
-		ShopName                   string `json:"shop_name"`
-		TargetSalePriceCurrency    string `json:"target_sale_price_currency"`
-		TargetAppSalePriceCurrency string `json:"target_app_sale_price_currency"`
-
-		ProductSmallImageURLs struct {
-			String []string `json:"string"`
-		} `json:"product_small_image_urls"`
-		SecondLevelCategoryName     string `json:"second_level_category_name"`
-		SecondLevelCategoryID       int64  `json:"second_level_category_id"`
-		FirstLevelCategoryID        int64  `json:"first_level_category_id"`
-		FirstLevelCategoryName      string `json:"first_level_category_name"`
-		OriginalPriceCurrency       string `json:"original_price_currency"`
-		ShopURL                     string `json:"shop_url"`
-		TargetOriginalPriceCurrency string `json:"target_original_price_currency"`
-		TargetOriginalPrice         string `json:"target_original_price"`
-		ProductVideoURL             string `json:"product_video_url"`
-		PromotionLink               string `json:"promotion_link"`
-		SKUId                       int64  `json:"sku_id"`
-		HotProductCommissionRate    string `json:"hot_product_commission_rate"`
-		ShopID                      int64  `json:"shop_id"`
-		LastestVolume               int    `json:"lastest_volume"`
-		SalePriceCurrency           string `json:"sale_price_currency"`
-		CommissionRate              string `json:"commission_rate"`
-	}
-
+func MapAliExpressResponseToProducts(ctx context.Context, data []byte) ([]*domain.Product, error) {
 	type sgResp struct {
 		AliexpressResp struct {
 			RespResult struct {
-				Result struct {
-					CurrentRecordCount int `json:"current_record_count"`
-					TotalRecordCount   int `json:"total_record_count"`
-					CurrentPageNo      int `json:"current_page_no"`
-					Products           struct {
-						Product []aliProduct `json:"product"`
-					} `json:"products"`
-				} `json:"result"`
+				Result aliProductsResult `json:"result"`
 			} `json:"resp_result"`
 		} `json:"aliexpress_affiliate_product_query_response"`
 	}
@@ -83,64 +93,116 @@ func MapAliExpressResponseToProducts(data []byte) ([]*domain.Product, error) {
 	var sg sgResp
 	err := json.Unmarshal(data, &sg)
 	if err == nil {
-		log.Printf("[AlibabaGateway] Unmarshal to sgResp succeeded. Raw product count in struct: %d", len(sg.AliexpressResp.RespResult.Result.Products.Product))
+		aliLog.DebugContext(ctx, "decoded product query response", "raw_products", len(sg.AliexpressResp.RespResult.Result.Products.Product))
 		if len(sg.AliexpressResp.RespResult.Result.Products.Product) > 0 {
-			log.Println("[AlibabaGateway] Successfully unmarshaled with SG response structure and found products.")
 			out := make([]*domain.Product, 0, len(sg.AliexpressResp.RespResult.Result.Products.Product))
 			for _, p := range sg.AliexpressResp.RespResult.Result.Products.Product {
-				usd := parseFloatOrZero(p.TargetSalePrice)
-				if usd == 0 {
-					usd = parseFloatOrZero(p.TargetAppSalePrice)
-				}
-				if usd == 0 {
-					log.Printf("[AlibabaGateway] Warning: No explicit target USD price found for product ID %d. Falling back to SalePrice/AppSalePrice which might be in CNY.", p.ProductID)
-					usd = parseFloatOrZero(p.SalePrice)
-					if usd == 0 {
-						usd = parseFloatOrZero(p.AppSalePrice)
-					}
-				}
-
-				tax := parseFloatOrZero(p.TaxRate)
-				discount := parsePercentOrZero(p.Discount)
-				rating := parsePercentOrZero(p.EvaluateRate)
-
-				prod := &domain.Product{
-					ID:                strconv.FormatInt(p.ProductID, 10),
-					Title:             strings.TrimSpace(p.ProductTitle),
-					ImageURL:          strings.TrimSpace(p.ProductMainImageURL),
-					AIMatchPercentage: 0, // Placeholder
-					Price: domain.Price{
-						ETB:         0,
-						USD:         usd,
-						FXTimestamp: time.Now().UTC(),
-					},
-					ProductRating:      rating,
-					SellerScore:        0, // Placeholder
-					DeliveryEstimate:   strings.TrimSpace(p.ShipToDays),
-					Description:        "", // Not available in current API response snippet
-					CustomerHighlights: "", // Not available in current API response snippet
-					CustomerReview:     "", // Not available in current API response snippet
-					NumberSold:         p.LastestVolume,
-					SummaryBullets:     []string{},
-					DeeplinkURL:        strings.TrimSpace(p.ProductDetailURL),
-					TaxRate:            tax,
-					Discount:           discount,
-				}
-				out = append(out, prod)
+				out = append(out, mapAliProduct(ctx, p))
 			}
-			log.Println("Mapped", len(out), "products from AliExpress SG response")
+			aliLog.DebugContext(ctx, "mapped products", "api", "product.query", "count", len(out))
 			return out, nil
 		} else {
-			log.Println("[AlibabaGateway] Unmarshal to SG response structure succeeded, but found an empty 'product' array. This might indicate no products matched the query or a deeper API issue for this specific response.")
+			aliLog.InfoContext(ctx, "product query returned no products")
 			return []*domain.Product{}, nil
 		}
 	} else {
-		log.Printf("[AlibabaGateway] SG response structure unmarshaling failed: %v. This is unexpected for the current API response format. Returning an empty product list.", err)
+		aliLog.WarnContext(ctx, "product query response could not be decoded", logging.Err(err))
 		return []*domain.Product{}, fmt.Errorf("failed to unmarshal AliExpress response with SG structure: %v", err)
 	}
 }
 
-func parseFloatOrZero(s string) float64 {
+// MapAliExpressDetailResponseToProducts transforms the raw response of
+// aliexpress.affiliate.productdetail.get into domain products using the same
+// field rules as MapAliExpressResponseToProducts.
+func MapAliExpressDetailResponseToProducts(ctx context.Context, data []byte) ([]*domain.Product, error) {
+	var resp struct {
+		DetailResp struct {
+			RespResult struct {
+				RespCode int               `json:"resp_code"`
+				RespMsg  string            `json:"resp_msg"`
+				Result   aliProductsResult `json:"result"`
+			} `json:"resp_result"`
+		} `json:"aliexpress_affiliate_productdetail_get_response"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal AliExpress product detail response: %v", err)
+	}
+
+	rr := resp.DetailResp.RespResult
+	if rr.RespCode != 0 && rr.RespCode != 200 {
+		return nil, fmt.Errorf("aliexpress product detail error %d: %s", rr.RespCode, rr.RespMsg)
+	}
+
+	out := make([]*domain.Product, 0, len(rr.Result.Products.Product))
+	for _, p := range rr.Result.Products.Product {
+		out = append(out, mapAliProduct(ctx, p))
+	}
+	aliLog.DebugContext(ctx, "mapped products", "api", "productdetail.get", "count", len(out))
+	return out, nil
+}
+
+// mapAliProduct converts a single upstream product into a domain.Product.
+func mapAliProduct(ctx context.Context, p aliProduct) *domain.Product {
+	usd := parseFloatOrZero(ctx, p.TargetSalePrice)
+	if usd == 0 {
+		usd = parseFloatOrZero(ctx, p.TargetAppSalePrice)
+	}
+	if usd == 0 {
+		aliLog.WarnContext(ctx, "no target USD price, falling back to sale price which may be in CNY", "product_id", p.ProductID)
+		usd = parseFloatOrZero(ctx, p.SalePrice)
+		if usd == 0 {
+			usd = parseFloatOrZero(ctx, p.AppSalePrice)
+		}
+	}
+
+	// original_price is in original_price_currency (often CNY); a non-USD
+	// original is left out rather than compared against USD prices.
+	original := parseFloatOrZero(ctx, p.TargetOriginalPrice)
+	if original == 0 && strings.EqualFold(p.OriginalPriceCurrency, "USD") {
+		original = parseFloatOrZero(ctx, p.OriginalPrice)
+	}
+
+	tax := parseFloatOrZero(ctx, p.TaxRate)
+	discount := parsePercentOrZero(ctx, p.Discount)
+	rating := parsePercentOrZero(ctx, p.EvaluateRate)
+
+	return &domain.Product{
+		ID:                strconv.FormatInt(p.ProductID, 10),
+		Title:             strings.TrimSpace(p.ProductTitle),
+		ImageURL:          strings.TrimSpace(p.ProductMainImageURL),
+		AIMatchPercentage: 0, // Placeholder
+		Price: domain.Price{
+			ETB:         0,
+			USD:         usd,
+			FXTimestamp: time.Now().UTC(),
+			OriginalUSD: original,
+		},
+		ProductRating:      rating,
+		SellerScore:        0, // Placeholder
+		DeliveryEstimate:   strings.TrimSpace(p.ShipToDays),
+		Description:        "", // Not available in current API response snippet
+		CustomerHighlights: "", // Not available in current API response snippet
+		CustomerReview:     "", // Not available in current API response snippet
+		NumberSold:         p.LastestVolume,
+		SummaryBullets:     []string{},
+		DeeplinkURL:        strings.TrimSpace(p.ProductDetailURL),
+		TaxRate:            tax,
+		Discount:           discount,
+		Category:           mapCategory(p.FirstLevelCategoryID, p.FirstLevelCategoryName),
+		SubCategory:        mapCategory(p.SecondLevelCategoryID, p.SecondLevelCategoryName),
+	}
+}
+
+// mapCategory builds a domain.Category, leaving the ID empty when upstream sent none.
+func mapCategory(id int64, name string) domain.Category {
+	c := domain.Category{Name: strings.TrimSpace(name)}
+	if id != 0 {
+		c.ID = strconv.FormatInt(id, 10)
+	}
+	return c
+}
+
+func parseFloatOrZero(ctx context.Context, s string) float64 {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return 0
@@ -148,19 +210,19 @@ func parseFloatOrZero(s string) float64 {
 	s = strings.ReplaceAll(s, ",", "")
 	f, err := strconv.ParseFloat(s, 64)
 	if err != nil {
-		log.Printf("[AlibabaGateway] parseFloatOrZero: failed to parse '%s' as float: %v", s, err)
+		aliLog.DebugContext(ctx, "unparsable number", "value", s, logging.Err(err))
 		return 0
 	}
 	return f
 }
 
-func parsePercentOrZero(s string) float64 {
+func parsePercentOrZero(ctx context.Context, s string) float64 {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return 0
 	}
 	s = strings.TrimSuffix(s, "%")
-	return parseFloatOrZero(s)
+	return parseFloatOrZero(ctx, s)
 }
 
 type AlibabaHTTPGateway struct {
@@ -170,7 +232,7 @@ type AlibabaHTTPGateway struct {
 
 func NewAlibabaHTTPGateway(cfg *config.Config) domain.AlibabaGateway {
 	return &AlibabaHTTPGateway{
-		client: &http.Client{Timeout: 10 * time.Second},
+		client: instrumentClient(&http.Client{Timeout: 10 * time.Second}),
 		cfg:    cfg,
 	}
 }
@@ -218,28 +280,16 @@ const mockAliExpressResponse = `{
 
 // FetchProducts implements usecase.AlibabaGateway.
 func (a *AlibabaHTTPGateway) FetchProducts(ctx context.Context, Keywords string, filters map[string]interface{}) ([]*domain.Product, error) {
-	ts := time.Now().UTC().UnixNano() / 1e6
-	tsStr := strconv.FormatInt(ts, 10)
-
-	log.Printf("[AlibabaGateway] FetchProducts called with query: '%s' and filters: %+v", Keywords, filters)
+	aliLog.DebugContext(ctx, "fetching products", "keywords", Keywords, "filters", filters)
 
 	// Initialize params with required fields and **default values**
-	params := map[string]string{
-		"method":          "aliexpress.affiliate.product.query",
-		"app_key":         a.cfg.Aliexpress.AppKey,
-		"timestamp":       tsStr,
-		"sign_method":     "sha256",
-		"keywords":        Keywords,
-		"page_no":         "1",         // Default page number
-		"page_size":       "10",        // Default page size
-		"target_currency": "USD",       // Default currency
-		"target_language": "en",        // Default language
-		"sort":            "relevancy", // Default sort order
-
-		// Define all fields we want to receive from the API.
-		// This list should reflect all fields in `aliProduct` that you want populated.
-		"fields": "product_id,product_title,product_main_image_url,product_detail_url,sale_price,app_sale_price,original_price,discount,evaluate_rate,tax_rate,target_sale_price,target_app_sale_price,shop_name,lastest_volume,ship_to_days,first_level_category_name,second_level_category_name",
-	}
+	params := a.baseParams("aliexpress.affiliate.product.query")
+	params["keywords"] = Keywords
+	params["page_no"] = "1"           // Default page number
+	params["page_size"] = "10"        // Default page size
+	params["target_currency"] = "USD" // Default currency
+	params["target_language"] = "en"  // Default language
+	params["sort"] = "relevancy"      // Default sort order
 
 	// Apply overrides from the filters map
 	// For fields like min_sale_price, max_sale_price, category_ids, etc.,
@@ -303,13 +353,50 @@ func (a *AlibabaHTTPGateway) FetchProducts(ctx context.Context, Keywords string,
 	setNumberParam("delivery_days")
 
 	// Log final params for debugging
-	log.Printf("[AlibabaGateway] Final API params: %+v", params)
+	aliLog.DebugContext(ctx, "product query params", "params", params)
 
 	// The 'fields' parameter is critical for our mapper. It's best to control
 	// it internally to ensure all expected fields for `aliProduct` are always requested.
 	// If the user *must* override it, a more complex merge/validation logic would be needed.
 	// For now, we prioritize our hardcoded list for reliability.
 
+	respBody, err := a.signedGet(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	prods, err := MapAliExpressResponseToProducts(ctx, respBody)
+	if err != nil {
+		aliLog.WarnContext(ctx, "mapping the API response failed, using mock products", logging.Err(err))
+		return MapAliExpressResponseToProducts(ctx, []byte(mockAliExpressResponse))
+	}
+	return prods, nil
+}
+
+// aliProductFields lists every field we want the API to return. It should
+// reflect all fields in `aliProduct` that the mapper reads.
+const aliProductFields = "product_id,product_title,product_main_image_url,product_detail_url,sale_price,app_sale_price,original_price,original_price_currency,target_original_price,discount,evaluate_rate,tax_rate,target_sale_price,target_app_sale_price,shop_name,lastest_volume,ship_to_days,first_level_category_id,first_level_category_name,second_level_category_id,second_level_category_name"
+
+// baseParams returns the system parameters shared by every affiliate API call.
+func (a *AlibabaHTTPGateway) baseParams(method string) map[string]string {
+	ts := time.Now().UTC().UnixNano() / 1e6
+	return map[string]string{
+		"method":      method,
+		"app_key":     a.cfg.Aliexpress.AppKey,
+		"timestamp":   strconv.FormatInt(ts, 10),
+		"sign_method": "sha256",
+		"fields":      aliProductFields,
+	}
+}
+
+// aliUnavailableMsg is the caller-facing message of failed API calls.
+const aliUnavailableMsg = "AliExpress is unavailable"
+
+// signedGet signs params with computeAliSign, issues the GET request against
+// the configured base URL and returns the raw response body on HTTP 200.
+func (a *AlibabaHTTPGateway) signedGet(ctx context.Context, params map[string]string) (_ []byte, err error) {
+	defer func(start time.Time) { metrics.ObserveUpstream("aliexpress", params["method"], start, err) }(time.Now())
+	applyAffiliateCredentials(ctx, params)
 	sign := computeAliSign(params, a.cfg.Aliexpress.AppSecret)
 	params["sign"] = sign
 
@@ -320,7 +407,7 @@ func (a *AlibabaHTTPGateway) FetchProducts(ctx context.Context, Keywords string,
 
 	u, err := url.Parse(base)
 	if err != nil {
-		log.Printf("[AlibabaGateway] invalid base url %s: %v", base, err)
+		aliLog.ErrorContext(ctx, "invalid base url", "url", base, logging.Err(err))
 		return nil, err
 	}
 
@@ -330,48 +417,101 @@ func (a *AlibabaHTTPGateway) FetchProducts(ctx context.Context, Keywords string,
 	}
 	u.RawQuery = qv.Encode()
 
-	log.Printf("[AlibabaGateway] Final request URL: %s", u.String())
+	// Keywords are logged on their own so query redaction applies to them.
+	aliLog.DebugContext(ctx, "calling affiliate API", "method", params["method"], "keywords", params["keywords"])
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		log.Printf("[AlibabaGateway] new request error: %v", err)
+		aliLog.ErrorContext(ctx, "building request failed", logging.Err(err))
 		return nil, err
 	}
 
 	resp, err := a.client.Do(req)
 	if err != nil {
-		log.Printf("[AlibabaGateway] http request error: %v", err)
-		return nil, err
+		aliLog.WarnContext(ctx, "affiliate API request failed", "method", params["method"], logging.Err(err))
+		return nil, domain.UpstreamUnavailable(aliUnavailableMsg, err)
 	}
 	defer resp.Body.Close()
 
 	respBody := new(bytes.Buffer)
 	_, _ = respBody.ReadFrom(resp.Body)
-	log.Printf("[AlibabaGateway] response status=%d body_preview=%s", resp.StatusCode, preview(respBody.Bytes(), 800))
+	aliLog.DebugContext(ctx, "affiliate API response", "status", resp.StatusCode, "body_preview", preview(respBody.Bytes(), 800))
 
 	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
 		loc := resp.Header.Get("Location")
-		log.Printf("[AlibabaGateway] redirect detected: status=%d location=%s", resp.StatusCode, loc)
-		return nil, fmt.Errorf("aliexpress API redirected: status=%d location=%s", resp.StatusCode, loc)
+		aliLog.WarnContext(ctx, "affiliate API redirected", "status", resp.StatusCode, "location", loc)
+		return nil, domain.UpstreamUnavailable(aliUnavailableMsg,
+			fmt.Errorf("aliexpress API redirected: status=%d location=%s", resp.StatusCode, loc))
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[AlibabaGateway] non-200 response: %d body: %s", resp.StatusCode, preview(respBody.Bytes(), 1000))
-		return nil, fmt.Errorf("aliexpress API returned status %d: %s", resp.StatusCode, preview(respBody.Bytes(), 1000))
+		aliLog.WarnContext(ctx, "affiliate API error response", "status", resp.StatusCode, "body_preview", preview(respBody.Bytes(), 1000))
+		return nil, domain.UpstreamUnavailable(aliUnavailableMsg,
+			fmt.Errorf("aliexpress API returned status %d: %s", resp.StatusCode, preview(respBody.Bytes(), 1000)))
+	}
+
+	return respBody.Bytes(), nil
+}
+
+// applyAffiliateCredentials adds the linked account of the signed-in user, if
+// any, to params: its session, and its tracking ID unless the caller set one.
+// The mapped product fields do not depend on either, so cached products stay
+// safe to share between users.
+func applyAffiliateCredentials(ctx context.Context, params map[string]string) {
+	creds, ok := ctx.Value(contextkeys.Affiliate).(*domain.AffiliateCredentials)
+	if !ok || creds == nil {
+		return
+	}
+	if creds.Session != "" {
+		params["session"] = creds.Session
+	}
+	if creds.TrackingID != "" && params["tracking_id"] == "" {
+		params["tracking_id"] = creds.TrackingID
+	}
+}
+
+// FetchProductDetail implements domain.AlibabaGateway using
+// aliexpress.affiliate.productdetail.get. It returns (nil, nil) when the
+// product does not exist upstream.
+func (a *AlibabaHTTPGateway) FetchProductDetail(ctx context.Context, productID string) (*domain.Product, error) {
+	aliLog.DebugContext(ctx, "fetching product detail", "product_id", productID)
+
+	params := a.baseParams("aliexpress.affiliate.productdetail.get")
+	params["product_ids"] = productID
+	params["target_currency"] = "USD"
+	params["target_language"] = "en"
+	prefs, _ := ctx.Value(contextkeys.Preferences).(*domain.Preferences)
+	params["country"] = prefs.ShipTo()
+
+	respBody, err := a.signedGet(ctx, params)
+	if err != nil {
+		return nil, err
 	}
 
-	prods, err := MapAliExpressResponseToProducts(respBody.Bytes())
+	prods, err := MapAliExpressDetailResponseToProducts(ctx, respBody)
 	if err != nil {
-		log.Printf("[AlibabaGateway] mapping error from real API response: %v. Attempting mock fallback for development.", err)
-		return MapAliExpressResponseToProducts([]byte(mockAliExpressResponse))
+		return nil, err
 	}
-	return prods, nil
+	for _, p := range prods {
+		if p.ID == productID {
+			return p, nil
+		}
+	}
+	return nil, nil
 }
 
 // computeAliSign computes the signature expected by the AliExpress affiliate API.
 // Algorithm: sort keys, concatenate key+value (skip empty), signBase = appSecret + concatenated + appSecret,
 // SHA256 and return uppercase hex.
 func computeAliSign(params map[string]string, appSecret string) string {
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	_, _ = mac.Write([]byte(aliSignBase(params)))
+	return strings.ToUpper(hex.EncodeToString(mac.Sum(nil)))
+}
+
+// aliSignBase concatenates the sorted non-empty params as key+value, the
+// string both signature variants are computed over.
+func aliSignBase(params map[string]string) string {
 	keys := make([]string, 0, len(params))
 	for k := range params {
 		keys = append(keys, k)
@@ -387,19 +527,15 @@ func computeAliSign(params map[string]string, appSecret string) string {
 		b.WriteString(k)
 		b.WriteString(v)
 	}
+	return b.String()
+}
 
-	unsigned := b.String()
-	if len(unsigned) > 200 {
-		log.Printf("[AlibabaGateway] sign unsigned preview: %s...", unsigned[:200])
-	} else {
-		log.Printf("[AlibabaGateway] sign unsigned preview: %s", unsigned)
-	}
-
+// computeAliRestSign signs a call to a /rest system API such as
+// /auth/token/create, where the API path is prepended to the signed string.
+func computeAliRestSign(apiPath string, params map[string]string, appSecret string) string {
 	mac := hmac.New(sha256.New, []byte(appSecret))
-	_, _ = mac.Write([]byte(unsigned))
-	signature := strings.ToUpper(hex.EncodeToString(mac.Sum(nil)))
-	log.Printf("[AlibabaGateway] computed sign (HMAC-SHA256) preview: %s", signature)
-	return signature
+	_, _ = mac.Write([]byte(apiPath + aliSignBase(params)))
+	return strings.ToUpper(hex.EncodeToString(mac.Sum(nil)))
 }
 
 // preview returns a safe string preview of bytes up to n chars
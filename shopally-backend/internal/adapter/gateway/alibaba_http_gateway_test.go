@@ -1,8 +1,15 @@
 package gateway
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
+	"github.com/shopally-ai/internal/config"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,7 +40,9 @@ const mockAliExpressResponseValid = `{
                             "shop_name": "Mock Shop",
                             "target_sale_price_currency": "USD",
                             "first_level_category_id": 1,
+                            "first_level_category_name": "Women's Clothing",
                             "second_level_category_id": 2,
+                            "second_level_category_name": "Dresses",
                             "sku_id": 12345,
                             "shop_id": 67890,
                             "lastest_volume": 5,
@@ -65,7 +74,7 @@ const mockAliExpressResponseEmpty = `{
 
 func TestMapAliExpressResponseToProducts(t *testing.T) {
 	t.Run("valid response with 1 product", func(t *testing.T) {
-		products, err := MapAliExpressResponseToProducts([]byte(mockAliExpressResponseValid))
+		products, err := MapAliExpressResponseToProducts(context.Background(), []byte(mockAliExpressResponseValid))
 		require.NoError(t, err)
 		require.Len(t, products, 1)
 
@@ -78,11 +87,134 @@ func TestMapAliExpressResponseToProducts(t *testing.T) {
 		assert.InDelta(t, 50.0, p.Discount, 0.0001)
 		assert.InDelta(t, 92.1, p.ProductRating, 0.0001)
 		assert.Equal(t, 5, p.NumberSold)
+		assert.Equal(t, domain.Category{ID: "1", Name: "Women's Clothing"}, p.Category)
+		assert.Equal(t, domain.Category{ID: "2", Name: "Dresses"}, p.SubCategory)
 	})
 
 	t.Run("valid response with empty product list", func(t *testing.T) {
-		products, err := MapAliExpressResponseToProducts([]byte(mockAliExpressResponseEmpty))
+		products, err := MapAliExpressResponseToProducts(context.Background(), []byte(mockAliExpressResponseEmpty))
 		require.NoError(t, err)
 		assert.Empty(t, products)
 	})
 }
+
+func TestMapAliProduct_OriginalPrice(t *testing.T) {
+	cases := []struct {
+		name string
+		p    aliProduct
+		want float64
+	}{
+		{"target original price", aliProduct{TargetOriginalPrice: "20", OriginalPrice: "140", OriginalPriceCurrency: "CNY"}, 20},
+		{"USD original price", aliProduct{OriginalPrice: "20", OriginalPriceCurrency: "USD"}, 20},
+		{"CNY original price is skipped", aliProduct{OriginalPrice: "140", OriginalPriceCurrency: "CNY"}, 0},
+		{"unknown currency is skipped", aliProduct{OriginalPrice: "140"}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.p.TargetSalePrice = "15.9"
+			p := mapAliProduct(context.Background(), tc.p)
+			assert.InDelta(t, tc.want, p.Price.OriginalUSD, 0.0001)
+		})
+	}
+}
+
+const mockAliExpressDetailResponse = `{
+    "aliexpress_affiliate_productdetail_get_response": {
+        "resp_result": {
+            "resp_code": 200,
+            "resp_msg": "Call succeeds",
+            "result": {
+                "current_record_count": 1,
+                "products": {
+                    "product": [
+                        {
+                            "product_id": 1005001234567890,
+                            "product_title": "Wireless Earbuds",
+                            "target_sale_price": "12.50",
+                            "discount": "30%",
+                            "evaluate_rate": "95.0%",
+                            "ship_to_days": "10-20 days",
+                            "first_level_category_id": 44,
+                            "first_level_category_name": "Consumer Electronics"
+                        }
+                    ]
+                }
+            }
+        }
+    }
+}`
+
+func TestMapAliExpressDetailResponseToProducts(t *testing.T) {
+	products, err := MapAliExpressDetailResponseToProducts(context.Background(), []byte(mockAliExpressDetailResponse))
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, "1005001234567890", products[0].ID)
+	assert.InDelta(t, 12.5, products[0].Price.USD, 0.0001)
+	assert.InDelta(t, 30.0, products[0].Discount, 0.0001)
+	assert.Equal(t, "Consumer Electronics", products[0].Category.Name)
+
+	_, err = MapAliExpressDetailResponseToProducts(context.Background(), []byte(`{"aliexpress_affiliate_productdetail_get_response":{"resp_result":{"resp_code":405,"resp_msg":"bad"}}}`))
+	assert.Error(t, err)
+}
+
+func TestAlibabaHTTPGateway_FetchProductDetail(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		_, _ = w.Write([]byte(mockAliExpressDetailResponse))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{}
+	cfg.Aliexpress.BaseURL = srv.URL
+	cfg.Aliexpress.AppKey = "key"
+	cfg.Aliexpress.AppSecret = "secret"
+	g := NewAlibabaHTTPGateway(cfg)
+
+	p, err := g.FetchProductDetail(context.Background(), "1005001234567890")
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	assert.Equal(t, "Wireless Earbuds", p.Title)
+	assert.Equal(t, "aliexpress.affiliate.productdetail.get", got.Get("method"))
+	assert.Equal(t, "1005001234567890", got.Get("product_ids"))
+	assert.NotEmpty(t, got.Get("sign"))
+
+	p, err = g.FetchProductDetail(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestAlibabaHTTPGateway_UsesLinkedAccount(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		_, _ = w.Write([]byte(mockAliExpressDetailResponse))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{}
+	cfg.Aliexpress.BaseURL = srv.URL
+	cfg.Aliexpress.AppKey = "key"
+	cfg.Aliexpress.AppSecret = "secret"
+	g := NewAlibabaHTTPGateway(cfg)
+
+	_, err := g.FetchProductDetail(context.Background(), "1005001234567890")
+	require.NoError(t, err)
+	assert.Empty(t, got.Get("session"))
+	assert.Empty(t, got.Get("tracking_id"))
+
+	ctx := context.WithValue(context.Background(), contextkeys.Affiliate,
+		&domain.AffiliateCredentials{Session: "user-session", TrackingID: "user-tracking"})
+	_, err = g.FetchProductDetail(ctx, "1005001234567890")
+	require.NoError(t, err)
+	assert.Equal(t, "user-session", got.Get("session"))
+	assert.Equal(t, "user-tracking", got.Get("tracking_id"))
+
+	params := map[string]string{}
+	for k := range got {
+		if k != "sign" {
+			params[k] = got.Get(k)
+		}
+	}
+	assert.Equal(t, computeAliSign(params, "secret"), got.Get("sign"), "the session must be signed")
+}
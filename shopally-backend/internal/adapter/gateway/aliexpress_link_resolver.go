@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/metrics"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/util"
+)
+
+var linkLog = logging.Component("link_resolver")
+
+// maxLinkRedirects bounds how many hops a short link may take.
+const maxLinkRedirects = 10
+
+// errForeignRedirect stops a short link that redirects off AliExpress.
+var errForeignRedirect = errors.New("redirect leaves AliExpress")
+
+// AliExpressLinkResolver implements domain.LinkResolver. Item and mobile URLs
+// are parsed locally; short links are expanded by following their redirects
+// until a URL carrying a product ID is seen.
+type AliExpressLinkResolver struct {
+	client *http.Client
+}
+
+var _ domain.LinkResolver = (*AliExpressLinkResolver)(nil)
+
+// NewAliExpressLinkResolver creates a resolver. If httpClient is nil, a default client is used.
+func NewAliExpressLinkResolver(httpClient *http.Client) *AliExpressLinkResolver {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &AliExpressLinkResolver{client: instrumentClient(httpClient)}
+}
+
+// ResolveProductID implements domain.LinkResolver.
+func (r *AliExpressLinkResolver) ResolveProductID(ctx context.Context, rawURL string) (string, error) {
+	raw := strings.TrimSpace(rawURL)
+	if raw != "" && !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !util.IsAliExpressHost(u.Hostname()) {
+		return "", domain.ErrUnsupportedLink
+	}
+
+	if id, ok := util.ExtractAliExpressProductID(u); ok {
+		return id, nil
+	}
+	if !util.IsAliExpressShortLink(u) {
+		return "", domain.ErrUnsupportedLink
+	}
+	return r.expand(ctx, u)
+}
+
+// expand follows the redirect chain of a short link and stops at the first
+// hop that names a product, without downloading the item page itself.
+func (r *AliExpressLinkResolver) expand(ctx context.Context, u *url.URL) (_ string, err error) {
+	defer func(start time.Time) { metrics.ObserveUpstream("aliexpress", "expand_link", start, err) }(time.Now())
+	var found string
+	client := *r.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		// An open redirect must not point the server at other hosts.
+		if !util.IsAliExpressHost(req.URL.Hostname()) {
+			return errForeignRedirect
+		}
+		if id, ok := util.ExtractAliExpressProductID(req.URL); ok {
+			found = id
+			return http.ErrUseLastResponse
+		}
+		if len(via) >= maxLinkRedirects {
+			return errors.New("too many redirects")
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if errors.Is(err, errForeignRedirect) {
+		linkLog.WarnContext(ctx, "short link redirects off AliExpress", "host", u.Host)
+		return "", domain.ErrUnsupportedLink
+	}
+	if err != nil {
+		linkLog.WarnContext(ctx, "short link expansion failed", "host", u.Host, logging.Err(err))
+		return "", domain.UpstreamUnavailable("could not expand the short link", err)
+	}
+	_ = resp.Body.Close()
+
+	if found != "" {
+		return found, nil
+	}
+	if loc, err := resp.Location(); err == nil {
+		if id, ok := util.ExtractAliExpressProductID(loc); ok {
+			return id, nil
+		}
+	}
+	if id, ok := util.ExtractAliExpressProductID(resp.Request.URL); ok {
+		return id, nil
+	}
+	return "", domain.ErrUnsupportedLink
+}
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rewriteTransport sends every request to target while keeping the original
+// URL visible to redirect handling.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestAliExpressLinkResolver(t *testing.T) {
+	itemHits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_mKshort":
+			http.Redirect(w, r, "https://s.click.aliexpress.com/e/_hop", http.StatusFound)
+		case "/e/_hop":
+			http.Redirect(w, r, "https://m.aliexpress.com/item/1005001234567890.html?sk=x", http.StatusFound)
+		case "/_mKopen":
+			http.Redirect(w, r, "http://169.254.169.254/item/1005001234567890.html", http.StatusFound)
+		case "/_mKstore":
+			http.Redirect(w, r, "https://www.aliexpress.com/store/912345", http.StatusFound)
+		default:
+			itemHits++
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	target, _ := url.Parse(srv.URL)
+	r := NewAliExpressLinkResolver(&http.Client{Transport: rewriteTransport{target: target}})
+	ctx := context.Background()
+
+	id, err := r.ResolveProductID(ctx, "https://www.aliexpress.com/item/33006951782.html")
+	require.NoError(t, err)
+	assert.Equal(t, "33006951782", id)
+
+	id, err = r.ResolveProductID(ctx, "https://a.aliexpress.com/_mKshort")
+	require.NoError(t, err)
+	assert.Equal(t, "1005001234567890", id)
+	assert.Equal(t, 0, itemHits, "item page should not be downloaded")
+
+	_, err = r.ResolveProductID(ctx, "https://a.aliexpress.com/_mKstore")
+	assert.ErrorIs(t, err, domain.ErrUnsupportedLink)
+
+	itemHits = 0
+	_, err = r.ResolveProductID(ctx, "https://a.aliexpress.com/_mKopen")
+	assert.ErrorIs(t, err, domain.ErrUnsupportedLink)
+	assert.Equal(t, 0, itemHits, "redirects off AliExpress are not followed")
+
+	_, err = r.ResolveProductID(ctx, "https://example.com/item/33006951782.html")
+	assert.ErrorIs(t, err, domain.ErrUnsupportedLink)
+}
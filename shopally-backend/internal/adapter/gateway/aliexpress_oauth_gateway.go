@@ -0,0 +1,156 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// AliExpress Open Platform OAuth endpoints.
+const (
+	AliExpressAuthURL = "https://api-sg.aliexpress.com/oauth/authorize"
+	AliExpressRestURL = "https://api-sg.aliexpress.com/rest"
+)
+
+// AliExpressOAuthGateway links AliExpress accounts through the Open
+// Platform authorization-code flow. It implements domain.AliExpressAuthGateway.
+type AliExpressOAuthGateway struct {
+	appKey      string
+	appSecret   string
+	redirectURI string
+	authURL     string
+	restURL     string
+	client      *http.Client
+	now         func() time.Time
+}
+
+var _ domain.AliExpressAuthGateway = (*AliExpressOAuthGateway)(nil)
+
+// NewAliExpressOAuthGateway creates a gateway for the given app. If
+// httpClient is nil, a default client is used.
+func NewAliExpressOAuthGateway(appKey, appSecret, redirectURI string, httpClient *http.Client) *AliExpressOAuthGateway {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &AliExpressOAuthGateway{
+		appKey:      appKey,
+		appSecret:   appSecret,
+		redirectURI: redirectURI,
+		authURL:     AliExpressAuthURL,
+		restURL:     AliExpressRestURL,
+		client:      instrumentClient(httpClient),
+		now:         time.Now,
+	}
+}
+
+// WithEndpoints overrides the consent page and /rest base URLs, e.g. to run
+// against a local fake server. Empty values keep the current endpoint.
+func (g *AliExpressOAuthGateway) WithEndpoints(authURL, restURL string) *AliExpressOAuthGateway {
+	if authURL != "" {
+		g.authURL = authURL
+	}
+	if restURL != "" {
+		g.restURL = strings.TrimRight(restURL, "/")
+	}
+	return g
+}
+
+// AuthCodeURL returns the AliExpress consent page URL.
+func (g *AliExpressOAuthGateway) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("force_auth", "true")
+	q.Set("client_id", g.appKey)
+	q.Set("redirect_uri", g.redirectURI)
+	q.Set("state", state)
+	return g.authURL + "?" + q.Encode()
+}
+
+// ExchangeCode redeems an authorization code via /auth/token/create.
+func (g *AliExpressOAuthGateway) ExchangeCode(ctx context.Context, code string) (*domain.AffiliateToken, error) {
+	return g.tokenCall(ctx, "/auth/token/create", "code", code)
+}
+
+// Refresh renews the access token via /auth/token/refresh.
+func (g *AliExpressOAuthGateway) Refresh(ctx context.Context, refreshToken string) (*domain.AffiliateToken, error) {
+	return g.tokenCall(ctx, "/auth/token/refresh", "refresh_token", refreshToken)
+}
+
+// aliTokenResponse is the response of the token create and refresh APIs.
+// A non-"0" code reports an error.
+type aliTokenResponse struct {
+	Code             string      `json:"code"`
+	Message          string      `json:"message"`
+	AccessToken      string      `json:"access_token"`
+	RefreshToken     string      `json:"refresh_token"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	RefreshExpiresIn json.Number `json:"refresh_expires_in"`
+	UserID           string      `json:"user_id"`
+	Account          string      `json:"account"`
+}
+
+func (g *AliExpressOAuthGateway) tokenCall(ctx context.Context, apiPath, key, value string) (*domain.AffiliateToken, error) {
+	params := map[string]string{
+		"app_key":     g.appKey,
+		"timestamp":   strconv.FormatInt(g.now().UnixMilli(), 10),
+		"sign_method": "sha256",
+		key:           value,
+	}
+	params["sign"] = computeAliRestSign(apiPath, params, g.appSecret)
+
+	form := url.Values{}
+	for k, v := range params {
+		form.Set(k, v)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.restURL+apiPath, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("aliexpress %s: %w", apiPath, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aliexpress %s returned status %d", apiPath, resp.StatusCode)
+	}
+
+	var tr aliTokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("aliexpress %s: %w", apiPath, err)
+	}
+	if tr.Code != "" && tr.Code != "0" {
+		return nil, fmt.Errorf("%w: %s %s", domain.ErrOAuthCodeRejected, tr.Code, tr.Message)
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("aliexpress %s: response has no access token", apiPath)
+	}
+
+	now := g.now().UTC()
+	expiresIn, _ := tr.ExpiresIn.Int64()
+	refreshExpiresIn, _ := tr.RefreshExpiresIn.Int64()
+	return &domain.AffiliateToken{
+		AccountID:        tr.UserID,
+		Account:          tr.Account,
+		AccessToken:      tr.AccessToken,
+		RefreshToken:     tr.RefreshToken,
+		AccessExpiresAt:  now.Add(time.Duration(expiresIn) * time.Second),
+		RefreshExpiresAt: now.Add(time.Duration(refreshExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAliExpressAuth accepts "good-code" and "good-refresh" on correctly
+// signed token calls.
+func fakeAliExpressAuth(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		params := map[string]string{}
+		for k := range r.PostForm {
+			if k != "sign" {
+				params[k] = r.PostForm.Get(k)
+			}
+		}
+		path := r.URL.Path[len("/rest"):]
+		if r.PostForm.Get("sign") != computeAliRestSign(path, params, "app-secret") {
+			_, _ = w.Write([]byte(`{"code":"IncompleteSignature","message":"bad sign"}`))
+			return
+		}
+		switch {
+		case path == "/auth/token/create" && params["code"] == "good-code",
+			path == "/auth/token/refresh" && params["refresh_token"] == "good-refresh":
+			_, _ = w.Write([]byte(`{"code":"0","access_token":"at-1","refresh_token":"rt-1","expires_in":3600,"refresh_expires_in":86400,"user_id":"2001","account":"buyer@example.com"}`))
+		default:
+			_, _ = w.Write([]byte(`{"code":"InvalidCode","message":"code is invalid"}`))
+		}
+	}))
+}
+
+func TestAliExpressOAuthGateway(t *testing.T) {
+	srv := fakeAliExpressAuth(t)
+	defer srv.Close()
+	g := NewAliExpressOAuthGateway("app-key", "app-secret", "https://app.test/link", srv.Client()).
+		WithEndpoints(srv.URL+"/oauth/authorize", srv.URL+"/rest")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+
+	consent, err := url.Parse(g.AuthCodeURL("state-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "app-key", consent.Query().Get("client_id"))
+	assert.Equal(t, "state-1", consent.Query().Get("state"))
+	assert.Equal(t, "https://app.test/link", consent.Query().Get("redirect_uri"))
+
+	tok, err := g.ExchangeCode(context.Background(), "good-code")
+	require.NoError(t, err)
+	assert.Equal(t, &domain.AffiliateToken{
+		AccountID: "2001", Account: "buyer@example.com",
+		AccessToken: "at-1", RefreshToken: "rt-1",
+		AccessExpiresAt: now.Add(time.Hour), RefreshExpiresAt: now.Add(24 * time.Hour),
+	}, tok)
+
+	_, err = g.Refresh(context.Background(), "good-refresh")
+	require.NoError(t, err)
+
+	_, err = g.ExchangeCode(context.Background(), "bad-code")
+	assert.ErrorIs(t, err, domain.ErrOAuthCodeRejected)
+	_, err = g.Refresh(context.Background(), "stale-refresh")
+	assert.ErrorIs(t, err, domain.ErrOAuthCodeRejected)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/messaging"
+	"github.com/shopally-ai/internal/metrics"
 	"google.golang.org/api/option"
 )
 
@@ -57,7 +59,8 @@ func NewFCMGatewayWithClient(client FCMClient) *FCMGateway {
 	return &FCMGateway{client: client}
 }
 
-func (g *FCMGateway) Send(ctx context.Context, token, title, body string, data map[string]string) (string, error) {
+func (g *FCMGateway) Send(ctx context.Context, token, title, body string, data map[string]string) (_ string, err error) {
+	defer func(start time.Time) { metrics.ObserveUpstream("fcm", "send", start, err) }(time.Now())
 	msg := &messaging.Message{
 		Token: token,
 		Notification: &messaging.Notification{
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/shopally-ai/internal/metrics"
 	"github.com/shopally-ai/pkg/domain"
 )
 
@@ -43,12 +44,15 @@ func (c *CachedFXClient) GetRate(ctx context.Context, from, to string) (float64,
 
 	// 1) Try cache
 	if c.Cache != nil {
-		if val, ok, err := c.Cache.Get(ctx, key); err == nil && ok {
+		val, ok, err := c.Cache.Get(ctx, key)
+		if err == nil && ok {
 			if rate, perr := strconv.ParseFloat(val, 64); perr == nil {
+				metrics.ObserveCache("fx", true, nil)
 				return rate, nil
 			}
 			// fall through on parse error
 		}
+		metrics.ObserveCache("fx", false, err)
 	}
 
 	// 2) Cache miss -> fetch from provider
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopally-ai/internal/metrics"
 	"github.com/shopally-ai/internal/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -32,6 +34,9 @@ func (s *CachedFXClientSuite) TestMissThenHit() {
 	s.fx.On("GetRate", s.ctx, "USD", "ETB").Return(56.123456, nil).Once()
 	s.cache.On("Set", s.ctx, key, "56.123456", time.Minute).Return(nil).Once()
 
+	hits := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("fx", metrics.CacheHit))
+	misses := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("fx", metrics.CacheMiss))
+
 	rate1, err1 := s.c.GetRate(s.ctx, "usd", "etb")
 	s.Require().NoError(err1)
 	s.InDelta(56.123456, rate1, 1e-6)
@@ -40,6 +45,9 @@ func (s *CachedFXClientSuite) TestMissThenHit() {
 	rate2, err2 := s.c.GetRate(s.ctx, "USD", "ETB")
 	s.Require().NoError(err2)
 	s.InDelta(56.123456, rate2, 1e-6)
+
+	s.Equal(hits+1, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("fx", metrics.CacheHit)))
+	s.Equal(misses+1, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("fx", metrics.CacheMiss)))
 }
 
 func (s *CachedFXClientSuite) TestCacheHitParseErrorFallsThrough() {
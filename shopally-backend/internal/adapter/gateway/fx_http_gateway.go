@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/shopally-ai/internal/metrics"
 	"github.com/shopally-ai/pkg/domain"
 )
 
@@ -30,15 +31,16 @@ func NewFXHTTPGateway(apiURL, apiKey string, httpClient *http.Client) *FXHTTPGat
 	if httpClient == nil {
 		httpClient = &http.Client{Timeout: 15 * time.Second}
 	}
-	return &FXHTTPGateway{APIURL: apiURL, APIKey: apiKey, HTTPClient: httpClient}
+	return &FXHTTPGateway{APIURL: apiURL, APIKey: apiKey, HTTPClient: instrumentClient(httpClient)}
 }
 
 // GetRate fetches the conversion rate from -> to using the configured provider URL/key.
 // It supports multiple common provider response shapes.
-func (g *FXHTTPGateway) GetRate(ctx context.Context, from, to string) (float64, error) {
+func (g *FXHTTPGateway) GetRate(ctx context.Context, from, to string) (_ float64, err error) {
 	if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
 		return 0, errors.New("from/to required")
 	}
+	defer func(start time.Time) { metrics.ObserveUpstream("fx", "rate", start, err) }(time.Now())
 
 	reqURL := g.buildRequestURL(strings.ToUpper(from), strings.ToUpper(to))
 
@@ -48,7 +50,7 @@ func (g *FXHTTPGateway) GetRate(ctx context.Context, from, to string) (float64,
 	}
 	resp, err := g.HTTPClient.Do(req)
 	if err != nil {
-		return 0, err
+		return 0, domain.UpstreamUnavailable("exchange rate provider is unavailable", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -59,7 +61,8 @@ func (g *FXHTTPGateway) GetRate(ctx context.Context, from, to string) (float64,
 		return 0, err
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return 0, fmt.Errorf("fx api non-ok: %d - %s", resp.StatusCode, string(body))
+		return 0, domain.UpstreamUnavailable("exchange rate provider is unavailable",
+			fmt.Errorf("fx api non-ok: %d - %s", resp.StatusCode, string(body)))
 	}
 
 	// Try multiple known shapes
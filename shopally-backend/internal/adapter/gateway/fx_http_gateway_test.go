@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/shopally-ai/internal/contextkeys"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -37,6 +38,20 @@ func (s *FXHTTPGatewaySuite) TestGetRate_XHostResult() {
 	s.InDelta(56.78, rate, 1e-9)
 }
 
+func (s *FXHTTPGatewaySuite) TestGetRate_ForwardsRequestID() {
+	var got string
+	g, srv := s.newGatewayWithServer(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Request-ID")
+		_, _ = w.Write([]byte(`{"result": 56.78}`))
+	})
+	defer srv.Close()
+
+	ctx := context.WithValue(s.ctx, contextkeys.RequestID, "req-123")
+	_, err := g.GetRate(ctx, "USD", "ETB")
+	s.Require().NoError(err)
+	s.Equal("req-123", got)
+}
+
 func (s *FXHTTPGatewaySuite) TestGetRate_CurrencyFreaksStringRate() {
 	g, srv := s.newGatewayWithServer(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,127 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"golang.org/x/oauth2"
+)
+
+// Google's OAuth 2.0 and OpenID Connect endpoints.
+const (
+	GoogleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	GoogleTokenURL    = "https://oauth2.googleapis.com/token"
+	GoogleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+// GoogleOAuthGateway signs users in with Google using the authorization-code
+// flow with PKCE. It implements domain.OAuthProvider.
+type GoogleOAuthGateway struct {
+	conf        *oauth2.Config
+	userInfoURL string
+	httpClient  *http.Client
+}
+
+var _ domain.OAuthProvider = (*GoogleOAuthGateway)(nil)
+
+// NewGoogleOAuthGateway creates a gateway against Google's endpoints. If
+// httpClient is nil, a default client is used.
+func NewGoogleOAuthGateway(clientID, clientSecret, redirectURI string, httpClient *http.Client) *GoogleOAuthGateway {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &GoogleOAuthGateway{
+		conf: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURI,
+			Scopes:       []string{"openid", "email", "profile"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   GoogleAuthURL,
+				TokenURL:  GoogleTokenURL,
+				AuthStyle: oauth2.AuthStyleInParams,
+			},
+		},
+		userInfoURL: GoogleUserInfoURL,
+		httpClient:  instrumentClient(httpClient),
+	}
+}
+
+// WithEndpoints overrides the provider endpoints, e.g. to run against a local
+// fake OAuth server. Empty values keep the current endpoint.
+func (g *GoogleOAuthGateway) WithEndpoints(authURL, tokenURL, userInfoURL string) *GoogleOAuthGateway {
+	if authURL != "" {
+		g.conf.Endpoint.AuthURL = authURL
+	}
+	if tokenURL != "" {
+		g.conf.Endpoint.TokenURL = tokenURL
+	}
+	if userInfoURL != "" {
+		g.userInfoURL = userInfoURL
+	}
+	return g
+}
+
+// AuthCodeURL returns the consent page URL with the S256 challenge of verifier.
+func (g *GoogleOAuthGateway) AuthCodeURL(state, verifier string) string {
+	return g.conf.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange redeems code with the PKCE verifier and fetches the signed-in
+// account from the userinfo endpoint.
+func (g *GoogleOAuthGateway) Exchange(ctx context.Context, code, verifier string) (*domain.OAuthIdentity, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, g.httpClient)
+	tok, err := g.conf.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		var re *oauth2.RetrieveError
+		if errors.As(err, &re) && re.Response != nil && re.Response.StatusCode >= 400 && re.Response.StatusCode < 500 {
+			return nil, fmt.Errorf("%w: %s", domain.ErrOAuthCodeRejected, re.ErrorCode)
+		}
+		return nil, fmt.Errorf("google token exchange: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.conf.Client(ctx, tok).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("google userinfo: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("google userinfo non-ok: %d", resp.StatusCode)
+	}
+
+	var info struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("google userinfo: %w", err)
+	}
+	if info.Sub == "" {
+		return nil, errors.New("google userinfo: missing subject")
+	}
+	return &domain.OAuthIdentity{
+		Provider:      domain.IdentityProviderGoogle,
+		Subject:       info.Sub,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Name:          info.Name,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// fakeGoogle is a minimal OAuth server that only accepts the code it issued
+// together with the verifier matching the challenge of the consent request.
+func fakeGoogle(t *testing.T, challenge *string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("code") != "good-code" || oauth2.S256ChallengeFromVerifier(r.PostForm.Get("code_verifier")) != *challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "at-1", "token_type": "Bearer", "expires_in": 3600})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer at-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"sub":"g-123","email":"Abebe@Example.com","email_verified":true,"name":"Abebe"}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGoogleOAuthGateway_PKCEFlow(t *testing.T) {
+	var challenge string
+	srv := fakeGoogle(t, &challenge)
+	defer srv.Close()
+	g := NewGoogleOAuthGateway("client-id", "client-secret", "http://localhost/cb", srv.Client()).
+		WithEndpoints(srv.URL+"/authorize", srv.URL+"/token", srv.URL+"/userinfo")
+
+	verifier := oauth2.GenerateVerifier()
+	consent, err := url.Parse(g.AuthCodeURL("state-1", verifier))
+	require.NoError(t, err)
+	q := consent.Query()
+	assert.Equal(t, "state-1", q.Get("state"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, "http://localhost/cb", q.Get("redirect_uri"))
+	challenge = q.Get("code_challenge")
+
+	identity, err := g.Exchange(context.Background(), "good-code", verifier)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.OAuthIdentity{
+		Provider: domain.IdentityProviderGoogle, Subject: "g-123",
+		Email: "Abebe@Example.com", EmailVerified: true, Name: "Abebe",
+	}, identity)
+
+	_, err = g.Exchange(context.Background(), "good-code", oauth2.GenerateVerifier())
+	assert.ErrorIs(t, err, domain.ErrOAuthCodeRejected, "a different verifier must be refused")
+	_, err = g.Exchange(context.Background(), "bad-code", verifier)
+	assert.ErrorIs(t, err, domain.ErrOAuthCodeRejected)
+}
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("gateway")
+
+// tracingTransport wraps each outgoing request in a client span. Every
+// upstream is a third party (Google, AliExpress, Gemini, FX), so no trace
+// context is sent to them. The span URL has secrets redacted, since
+// AliExpress and FX calls carry keys and sessions in the query string.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracer.Start(req.Context(), req.Method+" "+req.URL.Host,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.full", logging.RedactURL(req.URL.String())),
+		),
+	)
+	req = req.Clone(ctx)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
+
+// instrumentClient returns a copy of client whose requests are traced and
+// carry X-Request-ID.
+func instrumentClient(client *http.Client) *http.Client {
+	c := *client
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.Transport = tracingTransport{base: requestIDTransport{base: base}}
+	return &c
+}
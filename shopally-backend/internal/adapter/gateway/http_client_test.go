@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInstrumentClient(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	ctx = context.WithValue(ctx, contextkeys.RequestID, "req-7")
+	member, err := baggage.NewMember("user", "forged")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx = baggage.ContextWithBaggage(ctx, bag)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/rates?apikey=s3cr3t&to=ETB", nil)
+	require.NoError(t, err)
+	resp, err := instrumentClient(&http.Client{}).Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	parent.End()
+
+	assert.Equal(t, "req-7", header.Get("X-Request-ID"))
+	assert.Empty(t, header.Get("traceparent"), "third parties get no trace context")
+	assert.Empty(t, header.Get("baggage"))
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	client := spans[0]
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), client.Parent().SpanID())
+	assert.Contains(t, client.Attributes(), attribute.String("url.full", srv.URL+"/rates?apikey=[REDACTED]&to=ETB"))
+	assert.Contains(t, client.Attributes(), attribute.Int("http.response.status_code", http.StatusBadGateway))
+	assert.Equal(t, "Error", client.Status().Code.String())
+}
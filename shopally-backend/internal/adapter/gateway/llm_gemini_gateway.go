@@ -6,16 +6,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/metrics"
 	"github.com/shopally-ai/pkg/domain"
 )
 
+var geminiLog = logging.Component("gemini")
+
 // GeminiLLMGateway implements domain.LLMGateway using Google Generative Language API (Gemini).
 type GeminiLLMGateway struct {
 	apiKey   string
@@ -24,8 +27,10 @@ type GeminiLLMGateway struct {
 	fx       domain.IFXClient
 }
 
-// CompareProducts implements domain.LLMGateway.
-func (g *GeminiLLMGateway) CompareProducts(ctx context.Context, productDetails []*domain.Product) (map[string]interface{}, error) {
+// CompareProducts implements domain.LLMGateway. The model only returns a
+// synthesis per product ID; product data is attached from the input so the
+// LLM cannot alter prices or other fields.
+func (g *GeminiLLMGateway) CompareProducts(ctx context.Context, productDetails []*domain.Product) (*domain.ComparisonResult, error) {
 	if len(productDetails) == 0 {
 		return nil, fmt.Errorf("at least one product is required")
 	}
@@ -48,7 +53,8 @@ func (g *GeminiLLMGateway) CompareProducts(ctx context.Context, productDetails [
 		}
 	}
 
-	prompt := "You are an assistant that compares e-commerce products. Return STRICT JSON only, no prose, with this shape: {\n  \"comparison\": [ { \"product\": <original product>, \"synthesis\": { \"pros\": [..], \"cons\": [..], \"isBestValue\": <bool>, \"features\": { <k>: <v> } } } ]\n}."
+	prompt := "You are an assistant that compares e-commerce products. Return STRICT JSON only, no prose, with this shape: {\n  \"products\": [ { \"productId\": <input product id>, \"synthesis\": { \"pros\": [..], \"cons\": [..], \"isBestValue\": <bool>, \"features\": { <k>: <v> } } } ]\n}." +
+		" Include every input product exactly once, in input order, and mark exactly one product with isBestValue true."
 	if lang == "am" {
 		prompt += " Respond in Amharic (am)."
 	} else {
@@ -57,17 +63,50 @@ func (g *GeminiLLMGateway) CompareProducts(ctx context.Context, productDetails [
 	prompt += "\nProducts JSON: " + string(b)
 
 	// Call LLM
-	text, err := g.call(ctx, prompt)
+	text, err := g.call(ctx, "compare", prompt)
 	if err != nil {
 		return nil, fmt.Errorf("LLM API call failed: %w", err)
 	}
 
-	clean := extractJSON(text)
-	var out map[string]interface{}
-	if err := json.Unmarshal([]byte(clean), &out); err != nil {
+	return parseComparison(extractJSON(text), productDetails)
+}
+
+// parseComparison decodes the LLM comparison JSON into a domain.ComparisonResult.
+// Entries are matched to input products by ID; unknown IDs are kept with only
+// the ID set so validation can reject them.
+func parseComparison(text string, products []*domain.Product) (*domain.ComparisonResult, error) {
+	var raw struct {
+		Products []struct {
+			ProductID string `json:"productId"`
+			Product   *struct {
+				ID string `json:"id"`
+			} `json:"product"`
+			Synthesis domain.Synthesis `json:"synthesis"`
+		} `json:"products"`
+	}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
 		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
 	}
 
+	byID := make(map[string]*domain.Product, len(products))
+	for _, p := range products {
+		if p != nil {
+			byID[p.ID] = p
+		}
+	}
+
+	out := &domain.ComparisonResult{Products: make([]domain.ProductComparison, 0, len(raw.Products))}
+	for _, entry := range raw.Products {
+		id := entry.ProductID
+		if id == "" && entry.Product != nil {
+			id = entry.Product.ID
+		}
+		pc := domain.ProductComparison{Product: domain.Product{ID: id}, Synthesis: entry.Synthesis}
+		if p, ok := byID[id]; ok {
+			pc.Product = *p
+		}
+		out.Products = append(out.Products, pc)
+	}
 	return out, nil
 }
 
@@ -80,7 +119,7 @@ func NewGeminiLLMGateway(apiKey string, fx domain.IFXClient) domain.LLMGateway {
 	return &GeminiLLMGateway{
 		apiKey:   apiKey,
 		modelURL: "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent",
-		client:   &http.Client{Timeout: 12 * time.Second},
+		client:   instrumentClient(&http.Client{Timeout: 12 * time.Second}),
 		fx:       fx,
 	}
 }
@@ -103,7 +142,13 @@ type geminiResponse struct {
 	} `json:"candidates"`
 }
 
-func (g *GeminiLLMGateway) call(ctx context.Context, prompt string) (string, error) {
+// geminiUnavailableMsg is the caller-facing message of failed model calls.
+const geminiUnavailableMsg = "the AI service is unavailable"
+
+// call sends prompt to the model. operation (intent, summarize, compare)
+// labels the call's metrics.
+func (g *GeminiLLMGateway) call(ctx context.Context, operation, prompt string) (text string, err error) {
+	defer func(start time.Time) { metrics.ObserveUpstream("gemini", operation, start, err) }(time.Now())
 	if g.apiKey == "" {
 		return "", errors.New("missing GEMINI_API_KEY")
 	}
@@ -117,24 +162,25 @@ func (g *GeminiLLMGateway) call(ctx context.Context, prompt string) (string, err
 		}{{Text: prompt}}},
 	}}
 	b, _ := json.Marshal(reqBody)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.modelURL+"?key="+g.apiKey, bytes.NewReader(b))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.modelURL, bytes.NewReader(b))
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	// The key goes in a header so it never appears in URLs or their errors.
+	req.Header.Set("x-goog-api-key", g.apiKey)
 	resp, err := g.client.Do(req)
 
-	log.Println("[GeminiLLMGateway] Request to Gemini API:", prompt)
-	log.Println("[GeminiLLMGateway] Response from Gemini API:", resp)
-
 	if err != nil {
-		return "", err
+		geminiLog.WarnContext(ctx, "Gemini request failed", logging.Err(err))
+		return "", domain.UpstreamUnavailable(geminiUnavailableMsg, err)
 	}
+	geminiLog.DebugContext(ctx, "Gemini call", "prompt", prompt, "status", resp.StatusCode)
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", errors.New("gemini http status: " + resp.Status)
+		return "", domain.UpstreamUnavailable(geminiUnavailableMsg, errors.New("gemini http status: "+resp.Status))
 	}
 	var gr geminiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
@@ -156,7 +202,7 @@ func (g *GeminiLLMGateway) call(ctx context.Context, prompt string) (string, err
 		}
 	}
 
-	log.Println("[GeminiLLMGateway] Warning: empty response from Gemini API")
+	geminiLog.WarnContext(ctx, "empty response from Gemini")
 
 	return "", errors.New("gemini empty response")
 }
@@ -165,16 +211,12 @@ func (g *GeminiLLMGateway) call(ctx context.Context, prompt string) (string, err
 // ParseIntent asks the model to extract a structured JSON of constraints.
 // ParseIntent asks the model to extract a structured JSON of constraints.
 func (g *GeminiLLMGateway) ParseIntent(ctx context.Context, query string) (map[string]interface{}, error) {
-	requestID := ""
-	if requestID == "" {
-		requestID = "unknown"
-	}
 
 	normalizedQuery := strings.TrimSpace(query)
 
 	// 2) Content moderation: Check for potentially harmful content
 	if isPotentiallyHarmful(normalizedQuery) {
-		log.Printf("[%s] Blocked query due to potentially harmful content: %s", requestID, normalizedQuery)
+		geminiLog.WarnContext(ctx, "blocked potentially harmful query", "query", normalizedQuery)
 		return nil, errors.New("query contains potentially harmful or prohibited content")
 	}
 
@@ -241,21 +283,21 @@ EXAMPLES (User Query in any language -> English JSON Output):
 INPUT QUERY: "%s"
 OUTPUT:`, normalizedQuery)
 
-	log.Printf("[%s] Sending multi-language JSON prompt to LLM", requestID)
+	geminiLog.DebugContext(ctx, "parsing intent")
 
-	text, err := g.call(ctx, prompt)
+	text, err := g.call(ctx, "intent", prompt)
 	if err != nil {
 		return nil, err
 	}
 
 	// Extract and clean JSON
 	clean := extractStrictJSON(text)
-	log.Printf("[%s] Extracted JSON: %s", requestID, clean)
+	geminiLog.DebugContext(ctx, "intent response", "response", clean)
 
 	// Parse the JSON response directly into map
 	var m map[string]interface{}
 	if err := json.Unmarshal([]byte(clean), &m); err != nil {
-		log.Printf("[%s] Failed to parse LLM JSON response: %v. Raw: %s", requestID, err, clean)
+		geminiLog.WarnContext(ctx, "intent response is not valid JSON, using defaults", "response", clean, logging.Err(err))
 		// Fallback to minimal response with default is_etb = true
 		m = map[string]interface{}{
 			"keywords":        normalizedQuery,
@@ -270,8 +312,9 @@ OUTPUT:`, normalizedQuery)
 		}
 	}
 
-	// Enforce required fields
-	m["ship_to_country"] = "ET"
+	// Enforce required fields; the delivery country comes from the caller's preferences
+	prefs, _ := ctx.Value(contextkeys.Preferences).(*domain.Preferences)
+	m["ship_to_country"] = prefs.ShipTo()
 	m["target_currency"] = "USD"
 	m["target_language"] = "en"
 
@@ -452,7 +495,7 @@ func (g *GeminiLLMGateway) SummarizeProduct(ctx context.Context, p *domain.Produ
 	enhancedProduct, err := g.enhanceProductContent(ctx, p, userPrompt, lang, aiMatchPercentage)
 	if err != nil {
 		// If enhancement fails, return the original product with basic enhancements
-		log.Printf("Product enhancement failed, returning original product: %v", err)
+		geminiLog.WarnContext(ctx, "product enhancement failed, returning original product", "product_id", p.ID, logging.Err(err))
 		return g.createBasicEnhancedProduct(p, userPrompt, lang, aiMatchPercentage), nil
 	}
 
@@ -493,20 +536,20 @@ The complete product JSON with enhanced text fields in %s language.
 
 OUTPUT:`, userPrompt, lang, lang, getProductJSONString(p), strings.ToUpper(lang), lang)
 
-	log.Printf("Enhancing product content for language: %s", lang)
+	geminiLog.DebugContext(ctx, "enhancing product content", "product_id", p.ID, "lang", lang)
 
-	text, err := g.call(ctx, prompt)
+	text, err := g.call(ctx, "summarize", prompt)
 	if err != nil {
 		return nil, err
 	}
 
 	clean := extractStrictJSON(text)
-	log.Printf("Extracted enhanced product JSON: %s", clean)
+	geminiLog.DebugContext(ctx, "enhanced product response", "response", clean)
 
 	// Parse the enhanced product
 	var enhancedProduct domain.Product
 	if err := json.Unmarshal([]byte(clean), &enhancedProduct); err != nil {
-		log.Printf("Failed to parse enhanced product JSON: %v", err)
+		geminiLog.WarnContext(ctx, "enhanced product response is not valid JSON", logging.Err(err))
 		return nil, err
 	}
 
@@ -522,6 +565,9 @@ OUTPUT:`, userPrompt, lang, lang, getProductJSONString(p), strings.ToUpper(lang)
 	enhancedProduct.DeeplinkURL = p.DeeplinkURL
 	enhancedProduct.TaxRate = p.TaxRate
 	enhancedProduct.Discount = p.Discount
+	enhancedProduct.Category = p.Category
+	enhancedProduct.SubCategory = p.SubCategory
+	enhancedProduct.Deal = p.Deal
 
 	return &enhancedProduct, nil
 }
@@ -570,6 +616,9 @@ func (g *GeminiLLMGateway) createBasicEnhancedProduct(p *domain.Product, userPro
 		DeeplinkURL:        p.DeeplinkURL,
 		TaxRate:            p.TaxRate,
 		Discount:           p.Discount,
+		Category:           p.Category,
+		SubCategory:        p.SubCategory,
+		Deal:               p.Deal,
 	}
 	return enhanced
 }
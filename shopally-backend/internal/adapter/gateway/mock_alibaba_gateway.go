@@ -14,6 +14,17 @@ func NewMockAlibabaGateway() domain.AlibabaGateway {
 	return &MockAlibabaGateway{}
 }
 
+// FetchProductDetail returns the mocked product with the given ID, if any.
+func (m *MockAlibabaGateway) FetchProductDetail(ctx context.Context, productID string) (*domain.Product, error) {
+	products, _ := m.FetchProducts(ctx, "", nil)
+	for _, p := range products {
+		if p.ID == productID {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *MockAlibabaGateway) FetchProducts(ctx context.Context, query string, filters map[string]interface{}) ([]*domain.Product, error) {
 	fxTs, _ := time.Parse(time.RFC3339, "2025-08-22T10:00:00Z")
 
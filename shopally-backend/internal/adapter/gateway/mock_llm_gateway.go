@@ -11,7 +11,7 @@ import (
 type MockLLMGateway struct{}
 
 // CompareProducts implements domain.LLMGateway.
-func (m *MockLLMGateway) CompareProducts(ctx context.Context, productDetails []*domain.Product) (map[string]interface{}, error) {
+func (m *MockLLMGateway) CompareProducts(ctx context.Context, productDetails []*domain.Product) (*domain.ComparisonResult, error) {
 	// Simple mock: best value = lowest USD price
 	bestIdx := 0
 	if len(productDetails) > 0 {
@@ -30,7 +30,7 @@ func (m *MockLLMGateway) CompareProducts(ctx context.Context, productDetails []*
 	prosLabelAm := []string{"መልካም ዋጋ", "ጥሩ እውቅና"}
 	consLabelAm := []string{"አንዳንድ ንብረቶች ሊጎዱ ይችላሉ"}
 
-	comparisons := make([]map[string]interface{}, 0, len(productDetails))
+	result := &domain.ComparisonResult{Products: make([]domain.ProductComparison, 0, len(productDetails))}
 	for i, p := range productDetails {
 		var pros, cons []string
 		if lang == "am" {
@@ -41,13 +41,13 @@ func (m *MockLLMGateway) CompareProducts(ctx context.Context, productDetails []*
 			cons = append([]string{}, consLabelEn...)
 		}
 
-		comparisons = append(comparisons, map[string]interface{}{
-			"product": p,
-			"synthesis": map[string]interface{}{
-				"pros":        pros,
-				"cons":        cons,
-				"isBestValue": i == bestIdx,
-				"features": map[string]string{
+		result.Products = append(result.Products, domain.ProductComparison{
+			Product: *p,
+			Synthesis: domain.Synthesis{
+				Pros:        pros,
+				Cons:        cons,
+				IsBestValue: i == bestIdx,
+				Features: map[string]string{
 					"Screen Type": "Unknown",
 					"Processor":   "Unknown",
 				},
@@ -55,9 +55,7 @@ func (m *MockLLMGateway) CompareProducts(ctx context.Context, productDetails []*
 		})
 	}
 
-	return map[string]interface{}{
-		"comparison": comparisons,
-	}, nil
+	return result, nil
 }
 
 func NewMockLLMGateway() domain.LLMGateway {
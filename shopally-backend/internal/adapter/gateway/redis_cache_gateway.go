@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/shopally-ai/internal/metrics"
 	"github.com/shopally-ai/pkg/domain"
 )
 
@@ -31,8 +32,10 @@ func (c *RedisCache) key(k string) string {
 func (c *RedisCache) Get(ctx context.Context, key string) (string, bool, error) {
 	val, err := c.client.Get(ctx, c.key(key)).Result()
 	if err == redis.Nil {
+		metrics.ObserveCache("redis", false, nil)
 		return "", false, nil
 	}
+	metrics.ObserveCache("redis", err == nil, err)
 	if err != nil {
 		return "", false, err
 	}
@@ -47,5 +50,20 @@ func (c *RedisCache) Set(ctx context.Context, key, val string, ttl time.Duration
 	return c.client.Set(ctx, c.key(key), val, ttl).Err()
 }
 
+// Take returns the value and deletes it atomically.
+func (c *RedisCache) Take(ctx context.Context, key string) (string, bool, error) {
+	val, err := c.client.GetDel(ctx, c.key(key)).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return val, true, nil
+}
+
 // Ensure interface compliance at compile time
-var _ domain.ICachePort = (*RedisCache)(nil)
+var (
+	_ domain.ICachePort   = (*RedisCache)(nil)
+	_ domain.OneTimeStore = (*RedisCache)(nil)
+)
@@ -51,4 +51,17 @@ func (s *RedisCacheSuite) TestTTLExpiry() {
 	s.True(s.mr.TTL("sa:temp") > 0)
 }
 
+func (s *RedisCacheSuite) TestTakeReadsOnce() {
+	s.Require().NoError(s.cache.Set(s.ctx, "code", "tokens", time.Minute))
+
+	val, ok, err := s.cache.Take(s.ctx, "code")
+	s.NoError(err)
+	s.True(ok)
+	s.Equal("tokens", val)
+
+	_, ok, err = s.cache.Take(s.ctx, "code")
+	s.NoError(err)
+	s.False(ok)
+}
+
 func TestRedisCacheSuite(t *testing.T) { suite.Run(t, new(RedisCacheSuite)) }
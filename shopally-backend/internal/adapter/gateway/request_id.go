@@ -0,0 +1,23 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/shopally-ai/internal/contextkeys"
+)
+
+// requestIDHeader forwards the caller's correlation ID to upstream APIs.
+const requestIDHeader = "X-Request-ID"
+
+// requestIDTransport sets X-Request-ID on outgoing requests from their context.
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if id, ok := req.Context().Value(contextkeys.RequestID).(string); ok && id != "" && req.Header.Get(requestIDHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(requestIDHeader, id)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// AffiliateHandler manages the caller's linked AliExpress account. The
+// AliExpress redirect lands in the client, which posts the code and state
+// back with its bearer token, so the state is checked against the caller.
+type AffiliateHandler struct {
+	accounts *usecase.AffiliateAccountUseCase
+}
+
+// NewAffiliateHandler creates a new AffiliateHandler.
+func NewAffiliateHandler(accounts *usecase.AffiliateAccountUseCase) *AffiliateHandler {
+	return &AffiliateHandler{accounts: accounts}
+}
+
+type linkPayload struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+type trackingPayload struct {
+	TrackingID string `json:"trackingId"`
+}
+
+// StartLink handles POST /me/aliexpress/link/start and returns the consent URL.
+func (h *AffiliateHandler) StartLink(c *gin.Context) {
+	user, ok := currentUser(c.Request.Context())
+	if !ok {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	authURL, err := h.accounts.StartLink(user.ID)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"url": authURL}, Error: nil})
+}
+
+// CompleteLink handles POST /me/aliexpress/link with the code and state
+// AliExpress redirected back with.
+func (h *AffiliateHandler) CompleteLink(c *gin.Context) {
+	user, ok := currentUser(c.Request.Context())
+	if !ok {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	var p linkPayload
+	if err := c.ShouldBindJSON(&p); err != nil || p.Code == "" {
+		apierror.Respond(c, errInvalidBody)
+		return
+	}
+	account, err := h.accounts.CompleteLink(c.Request.Context(), user.ID, p.Code, p.State)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"account": account}, Error: nil})
+}
+
+// Get handles GET /me/aliexpress.
+func (h *AffiliateHandler) Get(c *gin.Context) {
+	user, ok := currentUser(c.Request.Context())
+	if !ok {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	account, err := h.accounts.Get(c.Request.Context(), user.ID)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"account": account}, Error: nil})
+}
+
+// SetTrackingID handles PUT /me/aliexpress.
+func (h *AffiliateHandler) SetTrackingID(c *gin.Context) {
+	user, ok := currentUser(c.Request.Context())
+	if !ok {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	var p trackingPayload
+	if err := c.ShouldBindJSON(&p); err != nil {
+		apierror.Respond(c, errInvalidBody)
+		return
+	}
+	account, err := h.accounts.SetTrackingID(c.Request.Context(), user.ID, p.TrackingID)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"account": account}, Error: nil})
+}
+
+// Unlink handles DELETE /me/aliexpress.
+func (h *AffiliateHandler) Unlink(c *gin.Context) {
+	user, ok := currentUser(c.Request.Context())
+	if !ok {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	if err := h.accounts.Unlink(c.Request.Context(), user.ID); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"unlinked": true}, Error: nil})
+}
@@ -2,10 +2,10 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
 	"github.com/shopally-ai/pkg/domain"
 	"github.com/shopally-ai/pkg/usecase"
 )
@@ -22,12 +22,6 @@ func NewAlertHandler(am *usecase.AlertManager) *AlertHandler {
 	}
 }
 
-// successResponse represents a standard API response structure.
-type successResponse struct {
-	Data  interface{} `json:"data"`
-	Error interface{} `json:"error"`
-}
-
 // createAlertPayload represents the expected payload for creating an alert.
 type createAlertPayload struct {
 	ProductID    string  `json:"productId"`
@@ -39,7 +33,7 @@ type createAlertPayload struct {
 func (h *AlertHandler) CreateAlertHandler(c *gin.Context) {
 	var payload createAlertPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		apierror.Respond(c, errInvalidBody)
 		return
 	}
 
@@ -49,13 +43,16 @@ func (h *AlertHandler) CreateAlertHandler(c *gin.Context) {
 		CurrentPrice: payload.CurrentPrice,
 		IsActive:     true,
 	}
+	if user, ok := currentUser(c.Request.Context()); ok {
+		newAlert.UserID = user.ID
+	}
 
 	if err := h.alertManager.CreateAlert(newAlert); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create alert: %v", err)})
+		apierror.Respond(c, err)
 		return
 	}
 
-	response := successResponse{
+	response := envelope{
 		Data: map[string]string{
 			"status":  "Alert created successfully",
 			"alertId": newAlert.ID,
@@ -70,11 +67,11 @@ func (h *AlertHandler) GetAlertHandler(c *gin.Context) {
 	alertID := c.Param("id")
 	alert, err := h.alertManager.GetAlert(alertID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Failed to retrieve alert: %v", err)})
+		apierror.Respond(c, err)
 		return
 	}
 
-	response := successResponse{
+	response := envelope{
 		Data:  alert,
 		Error: nil,
 	}
@@ -85,11 +82,11 @@ func (h *AlertHandler) GetAlertHandler(c *gin.Context) {
 func (h *AlertHandler) DeleteAlertHandler(c *gin.Context) {
 	alertID := c.Param("id")
 	if err := h.alertManager.DeleteAlert(alertID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Failed to delete alert: %v", err)})
+		apierror.Respond(c, err)
 		return
 	}
 
-	response := successResponse{
+	response := envelope{
 		Data: map[string]string{
 			"status": "Alert deleted successfully",
 		},
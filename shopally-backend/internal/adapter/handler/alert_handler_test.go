@@ -3,12 +3,14 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
 	"github.com/shopally-ai/pkg/usecase"
 )
 
@@ -34,7 +36,7 @@ func TestAlertHandlers(t *testing.T) {
 			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
 		}
 
-		var res successResponse
+		var res envelope
 		if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
 			t.Fatalf("could not decode response body: %v", err)
 		}
@@ -70,7 +72,7 @@ func TestAlertHandlers(t *testing.T) {
 			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
 		}
 
-		var res successResponse
+		var res envelope
 		if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
 			t.Fatalf("could not decode response body: %v", err)
 		}
@@ -100,7 +102,7 @@ func TestAlertHandlers(t *testing.T) {
 			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
 		}
 
-		var res successResponse
+		var res envelope
 		if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
 			t.Fatalf("could not decode response body: %v", err)
 		}
@@ -130,7 +132,7 @@ func TestAlertHandlers(t *testing.T) {
 			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
 		}
 
-		var res successResponse
+		var res envelope
 		if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
 			t.Fatalf("could not decode response body: %v", err)
 		}
@@ -148,3 +150,51 @@ func TestAlertHandlers(t *testing.T) {
 		}
 	})
 }
+
+// failingAlertRepository fails every lookup with a storage error.
+type failingAlertRepository struct{ domain.AlertRepository }
+
+func (failingAlertRepository) GetAlert(string) (*domain.Alert, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestGetAlertHandler_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name   string
+		repo   domain.AlertRepository
+		status int
+		code   string
+	}{
+		{"unknown id", repository.NewMockAlertRepository(), http.StatusNotFound, "NOT_FOUND"},
+		{"storage failure", failingAlertRepository{}, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewAlertHandler(usecase.NewAlertManager(tc.repo))
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request = httptest.NewRequest("GET", "/alerts/missing", nil)
+			c.Params = gin.Params{{Key: "id", Value: "missing"}}
+
+			h.GetAlertHandler(c)
+
+			if rr.Code != tc.status {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tc.status)
+			}
+			var res struct {
+				Error struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+				t.Fatalf("could not decode response body: %v", err)
+			}
+			if res.Error.Code != tc.code {
+				t.Errorf("unexpected error code: got %v want %v", res.Error.Code, tc.code)
+			}
+		})
+	}
+}
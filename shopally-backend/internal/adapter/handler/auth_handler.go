@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// AuthHandler handles registration, login and session endpoints.
+type AuthHandler struct {
+	auth   *usecase.AuthUseCase
+	claims *usecase.DeviceClaimUseCase
+}
+
+// NewAuthHandler creates a new AuthHandler.
+func NewAuthHandler(auth *usecase.AuthUseCase) *AuthHandler {
+	return &AuthHandler{auth: auth}
+}
+
+// WithDeviceClaim enables ClaimDevice. Signing in never claims a device by
+// itself; the app asks for it once the user agrees to bring the device's
+// data along.
+func (h *AuthHandler) WithDeviceClaim(claims *usecase.DeviceClaimUseCase) *AuthHandler {
+	h.claims = claims
+	return h
+}
+
+// errInvalidBody is reported when a request body cannot be decoded.
+var errInvalidBody = domain.Validation("invalid request body")
+
+type credentialsPayload struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+}
+
+type refreshPayload struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Register handles POST /auth/register.
+func (h *AuthHandler) Register(c *gin.Context) {
+	var p credentialsPayload
+	if err := c.ShouldBindJSON(&p); err != nil {
+		apierror.Respond(c, errInvalidBody)
+		return
+	}
+	user, tokens, err := h.auth.Register(c.Request.Context(), p.Email, p.Password, p.Name)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, envelope{Data: map[string]interface{}{"user": user, "tokens": tokens}, Error: nil})
+}
+
+// Login handles POST /auth/login.
+func (h *AuthHandler) Login(c *gin.Context) {
+	var p credentialsPayload
+	if err := c.ShouldBindJSON(&p); err != nil {
+		apierror.Respond(c, errInvalidBody)
+		return
+	}
+	user, tokens, err := h.auth.Login(c.Request.Context(), p.Email, p.Password)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"user": user, "tokens": tokens}, Error: nil})
+}
+
+// Refresh handles POST /auth/refresh.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var p refreshPayload
+	if err := c.ShouldBindJSON(&p); err != nil || p.RefreshToken == "" {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	tokens, err := h.auth.Refresh(c.Request.Context(), p.RefreshToken)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"tokens": tokens}, Error: nil})
+}
+
+// Logout handles POST /auth/logout and revokes the given refresh token.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var p refreshPayload
+	if err := c.ShouldBindJSON(&p); err != nil || p.RefreshToken == "" {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	if err := h.auth.Logout(c.Request.Context(), p.RefreshToken); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"loggedOut": true}, Error: nil})
+}
+
+// Me handles GET /auth/me for an authenticated caller.
+func (h *AuthHandler) Me(c *gin.Context) {
+	current, ok := currentUser(c.Request.Context())
+	if !ok {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	user, err := h.auth.Me(c.Request.Context(), current.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			apierror.Respond(c, usecase.ErrInvalidToken)
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"user": user}, Error: nil})
+}
+
+// ClaimDevice handles POST /me/device/claim and merges the data of the
+// X-Device-ID device into the caller's account. Repeating it is harmless.
+//
+// X-Device-ID is taken as is: anonymous devices have no credential to
+// prove, so a signed-in user who learns another device's ID can take over
+// that device's alerts and saved items. The risk is accepted because device
+// IDs are random per install and the data is what the device itself could
+// already read anonymously.
+func (h *AuthHandler) ClaimDevice(c *gin.Context) {
+	user, ok := currentUser(c.Request.Context())
+	if !ok {
+		apierror.Respond(c, usecase.ErrInvalidToken)
+		return
+	}
+	if h.claims == nil {
+		apierror.Respond(c, errors.New("device claims are not configured"))
+		return
+	}
+	res, err := h.claims.Claim(c.Request.Context(), user.ID, c.GetHeader("X-Device-ID"))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"claimed": res}, Error: nil})
+}
+
+// currentUser returns the authenticated user placed in ctx by the auth middleware.
+func currentUser(ctx context.Context) (*domain.User, bool) {
+	u, ok := ctx.Value(contextkeys.User).(*domain.User)
+	return u, ok && u != nil
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/cmd/api/middleware"
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	auth := usecase.NewAuthUseCase(
+		repository.NewMockUserRepository(),
+		repository.NewMockSessionRepository(),
+		usecase.NewTokenService("test-secret", "shopally", time.Minute, time.Hour),
+	)
+	h := NewAuthHandler(auth)
+
+	r := gin.New()
+	r.Use(middleware.OptionalAuth(auth))
+	r.POST("/auth/register", h.Register)
+	r.POST("/auth/login", h.Login)
+	r.POST("/auth/refresh", h.Refresh)
+	r.GET("/auth/me", middleware.RequireAuth(auth), h.Me)
+	return r
+}
+
+func postJSON(r http.Handler, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthHandler(t *testing.T) {
+	r := newAuthRouter()
+
+	w := postJSON(r, "/auth/register", `{"email":"a@example.com","password":"correct horse","name":"Abebe"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp struct {
+		Data struct {
+			User   map[string]interface{} `json:"user"`
+			Tokens struct {
+				AccessToken  string `json:"accessToken"`
+				RefreshToken string `json:"refreshToken"`
+			} `json:"tokens"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotContains(t, resp.Data.User, "passwordHash")
+
+	t.Run("me requires a valid access token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/me", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer "+resp.Data.Tokens.AccessToken)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"email":"a@example.com"`)
+
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer "+resp.Data.Tokens.RefreshToken)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("maps errors to status codes", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, postJSON(r, "/auth/register", `{"email":"a@example.com","password":"correct horse"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, postJSON(r, "/auth/register", `{"email":"b@example.com","password":"short"}`).Code)
+		assert.Equal(t, http.StatusUnauthorized, postJSON(r, "/auth/login", `{"email":"a@example.com","password":"nope"}`).Code)
+		assert.Equal(t, http.StatusUnauthorized, postJSON(r, "/auth/refresh", `{"refreshToken":"garbage"}`).Code)
+	})
+
+	t.Run("refresh returns a new pair", func(t *testing.T) {
+		w := postJSON(r, "/auth/refresh", `{"refreshToken":"`+resp.Data.Tokens.RefreshToken+`"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "accessToken")
+	})
+}
+
+func TestAuthHandler_ClaimsDeviceOnlyOnRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := usecase.NewAuthUseCase(
+		repository.NewMockUserRepository(),
+		repository.NewMockSessionRepository(),
+		usecase.NewTokenService("test-secret", "shopally", time.Minute, time.Hour),
+	)
+	alertRepo := repository.NewMockAlertRepository()
+	alerts := usecase.NewAlertManager(alertRepo)
+	require.NoError(t, alerts.CreateAlert(&domain.Alert{DeviceID: "phone-b", ProductID: "1", CurrentPrice: 10}))
+	h := NewAuthHandler(auth).WithDeviceClaim(usecase.NewDeviceClaimUseCase(alerts, alertRepo, repository.NewMockSavedItemRepository()))
+
+	r := gin.New()
+	r.Use(middleware.OptionalAuth(auth))
+	r.POST("/auth/register", h.Register)
+	r.POST("/me/device/claim", middleware.RequireAuth(auth), h.ClaimDevice)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBufferString(`{"email":"a@example.com","password":"correct horse"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Device-ID", "phone-b")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "claimed", "signing in does not claim the device")
+	var resp struct {
+		Data struct {
+			Tokens struct {
+				AccessToken string `json:"accessToken"`
+			} `json:"tokens"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	claim := func(device string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/me/device/claim", nil)
+		req.Header.Set("Authorization", "Bearer "+resp.Data.Tokens.AccessToken)
+		if device != "" {
+			req.Header.Set("X-Device-ID", device)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+	w = claim("phone-b")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"alertsClaimed":1`)
+	assert.Contains(t, claim("phone-b").Body.String(), `"alertsClaimed":0`)
+	assert.Equal(t, http.StatusBadRequest, claim("").Code)
+}
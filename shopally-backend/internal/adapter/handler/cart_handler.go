@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// CartHandler handles HTTP requests for cart quotes.
+type CartHandler struct {
+	uc *usecase.CartQuoteUseCase
+}
+
+// NewCartHandler creates a new CartHandler.
+func NewCartHandler(uc *usecase.CartQuoteUseCase) *CartHandler {
+	return &CartHandler{uc: uc}
+}
+
+// QuoteCart handles POST /cart/quote. The body lists products by ID with a
+// quantity; the response itemizes the landed cost in USD and ETB.
+func (h *CartHandler) QuoteCart(c *gin.Context) {
+	var body struct {
+		Items []domain.CartItem `json:"items"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		invalidInput(c, "invalid request body")
+		return
+	}
+	for _, it := range body.Items {
+		if !isProductID(it.Product.ID) {
+			invalidInput(c, "product id must be numeric")
+			return
+		}
+	}
+
+	quote, err := h.uc.Quote(localizedContext(c), body.Items)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"cart": quote}, Error: nil})
+}
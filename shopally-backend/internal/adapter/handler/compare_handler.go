@@ -3,8 +3,10 @@ package handler
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
 	"github.com/shopally-ai/internal/contextkeys"
 	"github.com/shopally-ai/pkg/domain"
 	"github.com/shopally-ai/pkg/usecase"
@@ -13,6 +15,7 @@ import (
 // CompareHandler handles HTTP requests related to product comparison.
 type CompareHandler struct {
 	compareUseCase usecase.CompareProductsExecutor
+	resolver       usecase.ProductsResolver
 }
 
 // NewCompareHandler creates a new instance of CompareHandler.
@@ -22,73 +25,112 @@ func NewCompareHandler(uc usecase.CompareProductsExecutor) *CompareHandler {
 	}
 }
 
-// CompareProducts is the Gin handler for POST /compare.
+// WithProductResolver enables comparing by product IDs ("productIds").
+func (h *CompareHandler) WithProductResolver(r usecase.ProductsResolver) *CompareHandler {
+	h.resolver = r
+	return h
+}
+
+// CompareProducts is the Gin handler for POST /compare. Clients send either
+// "productIds" (IDs or {"id","provider"} objects, resolved server-side) or,
+// for older clients, full "products". The optional mode query parameter
+// selects the comparator: auto (default), llm or rules.
 func (h *CompareHandler) CompareProducts(c *gin.Context) {
 	// Require Accept-Language
 	lang := c.GetHeader("Accept-Language")
 	if lang == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"data": nil,
-			"error": gin.H{
-				"code":    "INVALID_INPUT",
-				"message": "Missing required header: Accept-Language",
-			},
-		})
+		invalidInput(c, "Missing required header: Accept-Language")
 		return
 	}
 
 	var requestBody struct {
-		Products []*domain.Product `json:"products"`
+		Products   []*domain.Product   `json:"products"`
+		ProductIDs []domain.ProductRef `json:"productIds"`
 	}
 
 	// Parse JSON body
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"data": nil,
-			"error": gin.H{
-				"code":    "INVALID_INPUT",
-				"message": "Invalid request body. Ensure it is valid JSON.",
-			},
-		})
+		invalidInput(c, "Invalid request body. Ensure it is valid JSON.")
 		return
 	}
 
-	// Validate number of products
-	if len(requestBody.Products) < 2 || len(requestBody.Products) > 4 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"data": nil,
-			"error": gin.H{
-				"code":    "INVALID_INPUT",
-				"message": "Request body must contain a 'products' array with 2 to 4 product objects.",
-			},
-		})
+	if len(requestBody.ProductIDs) > 0 && h.resolver == nil {
+		invalidInput(c, "Comparing by 'productIds' is not supported.")
+		return
+	}
+	if len(requestBody.ProductIDs) > 0 {
+		if msg := validateProductRefs(requestBody.ProductIDs); msg != "" {
+			invalidInput(c, msg)
+			return
+		}
+	} else if len(requestBody.Products) < 2 || len(requestBody.Products) > 4 {
+		// Validate number of products
+		invalidInput(c, "Request body must contain a 'products' array with 2 to 4 product objects.")
 		return
 	}
 
-	// Attach language to context (support 'am' for Amharic, else default to 'en')
+	// Attach language to context (support 'am' for Amharic, else default to 'en').
+	// A stored language preference overrides the header.
 	langCode := lang
 	if len(langCode) > 2 {
 		langCode = langCode[:2]
 	}
 	ctx := c.Request.Context()
+	if prefs := storedPreferences(ctx); prefs != nil && prefs.Language != "" {
+		langCode = prefs.Language
+	}
 	ctx = context.WithValue(ctx, contextkeys.RespLang, langCode)
 
+	// Resolve IDs to server-side product data so prices cannot be tampered with
+	if len(requestBody.ProductIDs) > 0 {
+		products, err := h.resolver.Resolve(ctx, requestBody.ProductIDs)
+		if err != nil {
+			apierror.Respond(c, err)
+			return
+		}
+		requestBody.Products = products
+		ctx = usecase.WithResolvedProducts(ctx)
+	}
+
 	// Execute use case
-	comparisonResult, err := h.compareUseCase.Execute(ctx, requestBody.Products)
+	comparisonResult, err := h.compareUseCase.Execute(ctx, requestBody.Products, c.Query("mode"))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, envelope{Data: comparisonResult, Error: nil})
+}
+
+// validateProductRefs trims and checks the productIds list and returns an
+// error message, or "" when it is valid.
+func validateProductRefs(refs []domain.ProductRef) string {
+	if len(refs) < 2 || len(refs) > 4 {
+		return "Request body must contain a 'productIds' array with 2 to 4 product IDs."
+	}
+	seen := make(map[string]bool, len(refs))
+	for i := range refs {
+		refs[i].ID = strings.TrimSpace(refs[i].ID)
+		if !isProductID(refs[i].ID) {
+			return "Every product ID must be numeric."
+		}
+		key := refs[i].NormalizedProvider() + ":" + refs[i].ID
+		if seen[key] {
+			return "Product IDs must be unique."
+		}
+		seen[key] = true
+	}
+	return ""
+}
+
+// GetComparison is the Gin handler for GET /comparisons/:id, used to reopen
+// a shared comparison until it expires.
+func (h *CompareHandler) GetComparison(c *gin.Context) {
+	result, err := h.compareUseCase.Get(c.Request.Context(), strings.TrimSpace(c.Param("id")))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"data": nil,
-			"error": gin.H{
-				"code":    "INTERNAL_SERVER_ERROR",
-				"message": "An error occurred while comparing products.",
-			},
-		})
+		apierror.Respond(c, err)
 		return
 	}
 
-	// Success
-	c.JSON(http.StatusOK, gin.H{
-		"data":  comparisonResult,
-		"error": nil,
-	})
+	c.JSON(http.StatusOK, envelope{Data: result, Error: nil})
 }
@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -40,10 +41,13 @@ func TestCompareProducts(t *testing.T) {
 		}
 		requestBody, _ := json.Marshal(gin.H{"products": productsToCompare})
 
-		expectedResult := map[string]interface{}{"comparison": "some comparison data"}
+		expectedResult := &domain.ComparisonResult{Products: []domain.ProductComparison{
+			{Product: *productsToCompare[0], Synthesis: domain.Synthesis{IsBestValue: true}},
+			{Product: *productsToCompare[1]},
+		}}
 
 		mockUseCase.
-			On("Execute", mock.Anything, productsToCompare).
+			On("Execute", mock.Anything, productsToCompare, "").
 			Return(expectedResult, nil).
 			Once()
 
@@ -57,12 +61,14 @@ func TestCompareProducts(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var responseBody map[string]interface{}
+		var responseBody struct {
+			Data  *domain.ComparisonResult `json:"data"`
+			Error interface{}              `json:"error"`
+		}
 		_ = json.Unmarshal(w.Body.Bytes(), &responseBody)
 
-		// Use EqualValues to ignore type alias differences
-		assert.EqualValues(t, expectedResult, responseBody["data"])
-		assert.Nil(t, responseBody["error"])
+		assert.Equal(t, expectedResult, responseBody.Data)
+		assert.Nil(t, responseBody.Error)
 
 		mockUseCase.AssertExpectations(t)
 	})
@@ -138,4 +144,106 @@ func TestCompareProducts(t *testing.T) {
 		// The use case should never be called
 		mockUseCase.AssertNotCalled(t, "Execute")
 	})
+
+	t.Run("Product IDs Case: Should resolve IDs server-side before comparing", func(t *testing.T) {
+		// Arrange
+		mockUseCase := new(usecase.MockCompareProductsUseCase)
+		mockResolver := new(usecase.MockProductsResolver)
+		handler := NewCompareHandler(mockUseCase).WithProductResolver(mockResolver)
+
+		router := gin.Default()
+		router.POST("/compare", handler.CompareProducts)
+
+		refs := []domain.ProductRef{{ID: "100"}, {ID: "200", Provider: "aliexpress"}}
+		resolved := []*domain.Product{{ID: "100"}, {ID: "200"}}
+		mockResolver.On("Resolve", mock.Anything, refs).Return(resolved, nil).Once()
+		mockUseCase.On("Execute", mock.Anything, resolved, "rules").
+			Return(&domain.ComparisonResult{Source: domain.ComparisonSourceRules}, nil).
+			Once()
+
+		// Act
+		w := httptest.NewRecorder()
+		body := `{"productIds": [" 100 ", {"id": "200", "provider": "aliexpress"}]}`
+		req, _ := http.NewRequest(http.MethodPost, "/compare?mode=rules", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "en")
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockResolver.AssertExpectations(t)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Product IDs Case: Should return 404 when a product cannot be resolved", func(t *testing.T) {
+		// Arrange
+		mockUseCase := new(usecase.MockCompareProductsUseCase)
+		mockResolver := new(usecase.MockProductsResolver)
+		handler := NewCompareHandler(mockUseCase).WithProductResolver(mockResolver)
+
+		router := gin.Default()
+		router.POST("/compare", handler.CompareProducts)
+
+		mockResolver.On("Resolve", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: 200", domain.ErrProductNotFound)).
+			Once()
+
+		// Act
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/compare", bytes.NewBufferString(`{"productIds": ["100", "200"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "en")
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUseCase.AssertNotCalled(t, "Execute")
+	})
+
+	t.Run("Product IDs Case: Should reject duplicate IDs", func(t *testing.T) {
+		// Arrange
+		mockUseCase := new(usecase.MockCompareProductsUseCase)
+		mockResolver := new(usecase.MockProductsResolver)
+		handler := NewCompareHandler(mockUseCase).WithProductResolver(mockResolver)
+
+		router := gin.Default()
+		router.POST("/compare", handler.CompareProducts)
+
+		// Act
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/compare", bytes.NewBufferString(`{"productIds": ["100", "100"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "en")
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockResolver.AssertNotCalled(t, "Resolve")
+	})
+}
+
+func TestGetComparison(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUseCase := new(usecase.MockCompareProductsUseCase)
+	handler := NewCompareHandler(mockUseCase)
+	router := gin.Default()
+	router.GET("/comparisons/:id", handler.GetComparison)
+
+	stored := &domain.ComparisonResult{ID: "abc", Source: domain.ComparisonSourceLLM}
+	mockUseCase.On("Get", mock.Anything, "abc").Return(stored, nil).Once()
+	mockUseCase.On("Get", mock.Anything, "missing").Return(nil, domain.ErrComparisonNotFound).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/comparisons/abc", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"abc"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/comparisons/missing", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockUseCase.AssertExpectations(t)
 }
@@ -0,0 +1,12 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// invalidInput is a shorthand for rejecting a request with message.
+func invalidInput(c *gin.Context, message string) {
+	apierror.Respond(c, domain.Validation(message))
+}
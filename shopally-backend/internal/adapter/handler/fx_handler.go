@@ -1,11 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
 	"github.com/shopally-ai/pkg/domain"
 )
 
@@ -25,11 +26,9 @@ type fxResponse struct {
 	Converted float64 `json:"converted"`
 }
 
-func (h *FXHandler) GetFX(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	from := strings.ToUpper(strings.Trim(strings.TrimSpace(r.URL.Query().Get("from")), "\"'"))
-	to := strings.ToUpper(strings.Trim(strings.TrimSpace(r.URL.Query().Get("to")), "\"'"))
+func (h *FXHandler) GetFX(c *gin.Context) {
+	from := strings.ToUpper(strings.Trim(strings.TrimSpace(c.Query("from")), "\"'"))
+	to := strings.ToUpper(strings.Trim(strings.TrimSpace(c.Query("to")), "\"'"))
 	if from == "" {
 		from = "USD"
 	}
@@ -38,18 +37,18 @@ func (h *FXHandler) GetFX(w http.ResponseWriter, r *http.Request) {
 	}
 
 	amount := 1.0
-	if s := strings.TrimSpace(r.URL.Query().Get("amount")); s != "" {
+	if s := strings.TrimSpace(c.Query("amount")); s != "" {
 		a, err := strconv.ParseFloat(s, 64)
 		if err != nil || a < 0 {
-			http.Error(w, "invalid amount", http.StatusBadRequest)
+			invalidInput(c, "amount must be a non-negative number")
 			return
 		}
 		amount = a
 	}
 
-	rate, err := h.FX.GetRate(ctx, from, to)
+	rate, err := h.FX.GetRate(c.Request.Context(), from, to)
 	if err != nil {
-		http.Error(w, "fx error: "+err.Error(), http.StatusBadGateway)
+		apierror.Respond(c, err)
 		return
 	}
 
@@ -60,6 +59,5 @@ func (h *FXHandler) GetFX(w http.ResponseWriter, r *http.Request) {
 		Amount:    amount,
 		Converted: rate * amount,
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(resp)
+	c.JSON(http.StatusOK, envelope{Data: resp, Error: nil})
 }
@@ -2,37 +2,58 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
 	"github.com/shopally-ai/internal/mocks"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type FXHandlerSuite struct {
 	suite.Suite
 	mockFX *mocks.IFXClient
-	h      *FXHandler
+	router *gin.Engine
+}
+
+type fxEnvelope struct {
+	Data  fxResponse `json:"data"`
+	Error *errorJSON `json:"error"`
+}
+
+type errorJSON struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 func (s *FXHandlerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
 	s.mockFX = mocks.NewIFXClient(s.T())
-	s.h = NewFXHandler(s.mockFX)
+	s.router = gin.New()
+	s.router.GET("/fx", NewFXHandler(s.mockFX).GetFX)
 }
 
-func (s *FXHandlerSuite) TestGetFX_HappyPath() {
-	req := httptest.NewRequest(http.MethodGet, "/fx?from=usd&to=etb&amount=2.5", nil)
+func (s *FXHandlerSuite) get(target string) (*httptest.ResponseRecorder, fxEnvelope) {
 	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+	var body fxEnvelope
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &body), rr.Body.String())
+	return rr, body
+}
 
-	s.mockFX.On("GetRate", req.Context(), "USD", "ETB").Return(56.0, nil).Once()
+func (s *FXHandlerSuite) TestGetFX_HappyPath() {
+	s.mockFX.On("GetRate", mock.Anything, "USD", "ETB").Return(56.0, nil).Once()
 
-	s.h.GetFX(rr, req)
-	s.Equal(http.StatusOK, rr.Result().StatusCode)
-	s.Equal("application/json", rr.Header().Get("Content-Type"))
+	rr, body := s.get("/fx?from=usd&to=etb&amount=2.5")
+	s.Equal(http.StatusOK, rr.Code)
+	s.Contains(rr.Header().Get("Content-Type"), "application/json")
 
-	var resp fxResponse
-	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Nil(body.Error)
+	resp := body.Data
 	s.Equal("USD", resp.From)
 	s.Equal("ETB", resp.To)
 	s.InDelta(56.0, resp.Rate, 1e-9)
@@ -41,16 +62,12 @@ func (s *FXHandlerSuite) TestGetFX_HappyPath() {
 }
 
 func (s *FXHandlerSuite) TestGetFX_DefaultsAndRounding() {
-	req := httptest.NewRequest(http.MethodGet, "/fx", nil)
-	rr := httptest.NewRecorder()
+	s.mockFX.On("GetRate", mock.Anything, "USD", "ETB").Return(1.23, nil).Once()
 
-	s.mockFX.On("GetRate", req.Context(), "USD", "ETB").Return(1.23, nil).Once()
+	rr, body := s.get("/fx")
+	s.Equal(http.StatusOK, rr.Code)
 
-	s.h.GetFX(rr, req)
-	s.Equal(http.StatusOK, rr.Result().StatusCode)
-
-	var resp fxResponse
-	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	resp := body.Data
 	s.Equal("USD", resp.From)
 	s.Equal("ETB", resp.To)
 	s.InDelta(1.23, resp.Rate, 1e-9)
@@ -59,26 +76,21 @@ func (s *FXHandlerSuite) TestGetFX_DefaultsAndRounding() {
 }
 
 func (s *FXHandlerSuite) TestGetFX_InvalidAmount() {
-	req := httptest.NewRequest(http.MethodGet, "/fx?amount=abc", nil)
-	rr := httptest.NewRecorder()
-
-	s.h.GetFX(rr, req)
-	s.Equal(http.StatusBadRequest, rr.Result().StatusCode)
+	rr, body := s.get("/fx?amount=abc")
+	s.Equal(http.StatusBadRequest, rr.Code)
+	s.Require().NotNil(body.Error)
+	s.Equal("INVALID_INPUT", body.Error.Code)
 }
 
 func (s *FXHandlerSuite) TestGetFX_UpstreamError() {
-	req := httptest.NewRequest(http.MethodGet, "/fx?from=USD&to=ETB", nil)
-	rr := httptest.NewRecorder()
-
-	s.mockFX.On("GetRate", req.Context(), "USD", "ETB").Return(0.0, assertAnError("oops")).Once()
-
-	s.h.GetFX(rr, req)
-	s.Equal(http.StatusBadGateway, rr.Result().StatusCode)
+	s.mockFX.On("GetRate", mock.Anything, "USD", "ETB").
+		Return(0.0, domain.UpstreamUnavailable("exchange rate provider is unavailable", errors.New("status 500: oops"))).Once()
+
+	rr, body := s.get("/fx?from=USD&to=ETB")
+	s.Equal(http.StatusServiceUnavailable, rr.Code)
+	s.Require().NotNil(body.Error)
+	s.Equal("UPSTREAM_UNAVAILABLE", body.Error.Code)
+	s.Equal("exchange rate provider is unavailable", body.Error.Message)
 }
 
-// helper provides an error without importing extra packages here
-type assertAnError string
-
-func (e assertAnError) Error() string { return string(e) }
-
 func TestFXHandlerSuite(t *testing.T) { suite.Run(t, new(FXHandlerSuite)) }
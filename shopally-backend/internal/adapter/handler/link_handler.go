@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// LinkHandler handles HTTP requests for resolving pasted product links.
+type LinkHandler struct {
+	uc *usecase.ResolveLinkUseCase
+}
+
+// NewLinkHandler creates a new LinkHandler with its dependencies.
+func NewLinkHandler(uc *usecase.ResolveLinkUseCase) *LinkHandler {
+	return &LinkHandler{uc: uc}
+}
+
+// ResolveLink handles GET /links/resolve?url=... and returns the linked product.
+func (h *LinkHandler) ResolveLink(c *gin.Context) {
+	raw := strings.TrimSpace(c.Query("url"))
+	if raw == "" {
+		invalidInput(c, "missing required query parameter: url")
+		return
+	}
+	p, err := h.uc.Execute(localizedContext(c), raw)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resolvedLinkEnvelope(p, raw))
+}
+
+// resolvedLinkEnvelope is the response to a resolved link, shared by the
+// dedicated endpoint and /search.
+func resolvedLinkEnvelope(p *domain.Product, raw string) envelope {
+	return envelope{Data: map[string]interface{}{
+		"products":     []*domain.Product{p},
+		"resolvedLink": raw,
+	}, Error: nil}
+}
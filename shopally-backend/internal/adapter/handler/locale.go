@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// localizedContext attaches the response language and currency to the
+// request context. Stored preferences win; otherwise both are derived from
+// Accept-Language ("am" -> Amharic/ETB, otherwise English/USD).
+func localizedContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	prefs := storedPreferences(ctx)
+
+	lang := apierror.Language(c)
+	currency := "USD"
+	if lang == "am" {
+		currency = "ETB"
+	}
+	if prefs != nil && prefs.Currency != "" {
+		currency = prefs.Currency
+	}
+
+	ctx = context.WithValue(ctx, contextkeys.RespLang, lang)
+	ctx = context.WithValue(ctx, contextkeys.RespCurrency, currency)
+	return ctx
+}
+
+// storedPreferences returns the caller's preferences loaded by the
+// preferences middleware, or nil.
+func storedPreferences(ctx context.Context) *domain.Preferences {
+	prefs, _ := ctx.Value(contextkeys.Preferences).(*domain.Preferences)
+	return prefs
+}
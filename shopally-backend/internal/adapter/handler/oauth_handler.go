@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// oauthFlowMaxAge bounds how long a started sign-in can be completed, in seconds.
+const oauthFlowMaxAge = 600
+
+// errOAuthDenied is reported when the user declined consent at the provider.
+var errOAuthDenied = domain.Unauthorized("sign-in was cancelled at the provider").WithCode("OAUTH_FAILED")
+
+// OAuthHandler handles sign-in through an external OAuth provider. The state
+// and PKCE verifier of a flow live in a short-lived HttpOnly cookie scoped to
+// the provider's routes, so no server-side storage is needed.
+type OAuthHandler struct {
+	login      *usecase.OAuthLoginUseCase
+	cookieName string
+	cookiePath string
+	// frontendURL receives the browser after the callback, when set.
+	frontendURL string
+}
+
+// NewOAuthHandler creates a handler for one provider, e.g. "google", whose
+// routes live under /api/v1/auth/<provider>.
+func NewOAuthHandler(login *usecase.OAuthLoginUseCase, provider string) *OAuthHandler {
+	return &OAuthHandler{
+		login:      login,
+		cookieName: "sa_oauth_" + provider,
+		cookiePath: "/api/v1/auth/" + provider,
+	}
+}
+
+// WithFrontendRedirect sends the browser back to frontendURL after the
+// provider's callback, with ?code=<one-time code> to redeem through Exchange
+// or ?error=<error code>. The login use case needs a hand-off store.
+func (h *OAuthHandler) WithFrontendRedirect(frontendURL string) *OAuthHandler {
+	h.frontendURL = frontendURL
+	return h
+}
+
+// Start handles GET /auth/<provider>/start and redirects to the consent page.
+func (h *OAuthHandler) Start(c *gin.Context) {
+	flow, err := h.login.Start()
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	h.setFlowCookie(c, flow.State+"."+flow.Verifier, oauthFlowMaxAge)
+	c.Redirect(http.StatusFound, flow.URL)
+}
+
+// Callback handles GET /auth/<provider>/callback. With a frontend redirect
+// it sends the browser back to the web app with a one-time code; otherwise
+// it returns our own tokens.
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	cookie, _ := c.Cookie(h.cookieName)
+	// The flow is single-use whatever the outcome.
+	h.setFlowCookie(c, "", -1)
+
+	user, tokens, err := h.complete(c, cookie)
+	if err == nil && h.frontendURL != "" {
+		var code string
+		if code, err = h.login.HandOff(c.Request.Context(), user, tokens); err == nil {
+			h.redirectToFrontend(c, "code", code)
+			return
+		}
+	}
+	if err != nil {
+		if h.frontendURL != "" {
+			_, body, _ := apierror.Render("en", err)
+			h.redirectToFrontend(c, "error", body.Code)
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"user": user, "tokens": tokens}, Error: nil})
+}
+
+// Exchange handles POST /auth/<provider>/exchange and redeems the one-time
+// code handed to the web app for our own tokens.
+func (h *OAuthHandler) Exchange(c *gin.Context) {
+	var p struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&p); err != nil {
+		apierror.Respond(c, errInvalidBody)
+		return
+	}
+	user, tokens, err := h.login.Redeem(c.Request.Context(), strings.TrimSpace(p.Code))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"user": user, "tokens": tokens}, Error: nil})
+}
+
+// complete checks the callback against the flow cookie and signs the user in.
+func (h *OAuthHandler) complete(c *gin.Context, cookie string) (*domain.User, *domain.AuthTokens, error) {
+	if c.Query("error") != "" {
+		return nil, nil, errOAuthDenied
+	}
+	state, verifier, ok := strings.Cut(cookie, ".")
+	query := c.Query("state")
+	if !ok || state == "" || verifier == "" || subtle.ConstantTimeCompare([]byte(state), []byte(query)) != 1 {
+		return nil, nil, usecase.ErrInvalidOAuthState
+	}
+	code := c.Query("code")
+	if code == "" {
+		return nil, nil, errInvalidBody
+	}
+	return h.login.Complete(c.Request.Context(), code, verifier)
+}
+
+func (h *OAuthHandler) redirectToFrontend(c *gin.Context, key, value string) {
+	target, err := url.Parse(h.frontendURL)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	q := target.Query()
+	q.Set(key, value)
+	target.RawQuery = q.Encode()
+	c.Redirect(http.StatusFound, target.String())
+}
+
+func (h *OAuthHandler) setFlowCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(h.cookieName, value, maxAge, h.cookiePath, "", secure, true)
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// consentProvider redirects to a consent URL carrying the state and accepts
+// only the verifier handed out with it.
+type consentProvider struct {
+	verifier string
+}
+
+func (p *consentProvider) AuthCodeURL(state, verifier string) string {
+	p.verifier = verifier
+	return "https://provider.test/consent?state=" + url.QueryEscape(state)
+}
+
+func (p *consentProvider) Exchange(ctx context.Context, code, verifier string) (*domain.OAuthIdentity, error) {
+	if code != "good-code" || verifier != p.verifier {
+		return nil, domain.ErrOAuthCodeRejected
+	}
+	return &domain.OAuthIdentity{Provider: domain.IdentityProviderGoogle, Subject: "g-1", Email: "a@example.com", EmailVerified: true}, nil
+}
+
+func TestOAuthHandler_Flow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := usecase.NewAuthUseCase(
+		repository.NewMockUserRepository(),
+		repository.NewMockSessionRepository(),
+		usecase.NewTokenService("test-secret", "shopally", time.Minute, time.Hour),
+	)
+	h := NewOAuthHandler(usecase.NewOAuthLoginUseCase(&consentProvider{}, repository.NewMockUserRepository(), auth), "google")
+	r := gin.New()
+	r.GET("/api/v1/auth/google/start", h.Start)
+	r.GET("/api/v1/auth/google/callback", h.Callback)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/start", nil))
+	require.Equal(t, http.StatusFound, w.Code)
+	consent, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	state := consent.Query().Get("state")
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	flow := cookies[0]
+	assert.True(t, flow.HttpOnly)
+	assert.Equal(t, "/api/v1/auth/google", flow.Path)
+
+	callback := func(query string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/callback?"+query, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, callback("code=good-code&state=forged", flow).Code)
+	assert.Equal(t, http.StatusBadRequest, callback("code=good-code&state="+url.QueryEscape(state), nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, callback("error=access_denied&state="+url.QueryEscape(state), flow).Code)
+	assert.Equal(t, http.StatusUnauthorized, callback("code=bad-code&state="+url.QueryEscape(state), flow).Code)
+
+	w = callback("code=good-code&state="+url.QueryEscape(state), flow)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"accessToken"`)
+	cleared := w.Result().Cookies()
+	require.Len(t, cleared, 1)
+	assert.Equal(t, "", cleared[0].Value)
+}
+
+func TestOAuthHandler_RedirectsToFrontend(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	store := gateway.NewRedisCache(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "sa:")
+	auth := usecase.NewAuthUseCase(
+		repository.NewMockUserRepository(),
+		repository.NewMockSessionRepository(),
+		usecase.NewTokenService("test-secret", "shopally", time.Minute, time.Hour),
+	)
+	login := usecase.NewOAuthLoginUseCase(&consentProvider{}, repository.NewMockUserRepository(), auth).WithHandoff(store, 0)
+	h := NewOAuthHandler(login, "google").WithFrontendRedirect("https://app.test/signed-in?from=google")
+	r := gin.New()
+	r.GET("/api/v1/auth/google/start", h.Start)
+	r.GET("/api/v1/auth/google/callback", h.Callback)
+	r.POST("/api/v1/auth/google/exchange", h.Exchange)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/start", nil))
+	require.Equal(t, http.StatusFound, w.Code)
+	consent, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	state := url.QueryEscape(consent.Query().Get("state"))
+	flow := w.Result().Cookies()[0]
+
+	callback := func(query string) *url.URL {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/callback?"+query, nil)
+		req.AddCookie(flow)
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+		target, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "app.test", target.Host)
+		assert.Equal(t, "google", target.Query().Get("from"))
+		return target
+	}
+	exchange := func(code string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/google/exchange", strings.NewReader(`{"code":"`+code+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Failures come back to the web app as an error code, not a JSON body.
+	failed := callback("error=access_denied&state=" + state)
+	assert.Equal(t, "OAUTH_FAILED", failed.Query().Get("error"))
+	assert.Empty(t, failed.Query().Get("code"))
+
+	signedIn := callback("code=good-code&state=" + state)
+	code := signedIn.Query().Get("code")
+	require.NotEmpty(t, code)
+	assert.NotContains(t, signedIn.String(), "accessToken")
+
+	w = exchange(code)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"accessToken"`)
+
+	// The code is single-use.
+	w = exchange(code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_CODE")
+}
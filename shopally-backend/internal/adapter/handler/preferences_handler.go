@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// PreferencesHandler serves the caller's preferences. They belong to the
+// signed-in user, or to the X-Device-ID of an anonymous caller.
+type PreferencesHandler struct {
+	uc *usecase.PreferencesUseCase
+}
+
+// NewPreferencesHandler creates a new PreferencesHandler.
+func NewPreferencesHandler(uc *usecase.PreferencesUseCase) *PreferencesHandler {
+	return &PreferencesHandler{uc: uc}
+}
+
+// Get handles GET /me/preferences.
+func (h *PreferencesHandler) Get(c *gin.Context) {
+	prefs, err := h.uc.Get(c.Request.Context(), requestOwner(c))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: prefs, Error: nil})
+}
+
+// Update handles PUT /me/preferences. The body replaces the stored
+// preferences; omitted fields fall back to their defaults.
+func (h *PreferencesHandler) Update(c *gin.Context) {
+	var p domain.Preferences
+	if err := c.ShouldBindJSON(&p); err != nil {
+		apierror.Respond(c, errInvalidBody)
+		return
+	}
+	prefs, err := h.uc.Update(c.Request.Context(), requestOwner(c), p)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: prefs, Error: nil})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferencesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPreferencesHandler(usecase.NewPreferencesUseCase(repository.NewMockPreferencesRepository()))
+	r := gin.New()
+	r.GET("/me/preferences", h.Get)
+	r.PUT("/me/preferences", h.Update)
+
+	do := func(method, device, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/me/preferences", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if device != "" {
+			req.Header.Set("X-Device-ID", device)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "", "").Code)
+	w := do(http.MethodGet, "dev-1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"shipToCountry":"ET"`)
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "dev-1", `{"currency":"EUR"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "dev-1", `not json`).Code)
+	w = do(http.MethodPut, "dev-1", `{"language":"am","shipToCountry":"ke","budget":{"maxUsd":40}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"shipToCountry":"KE"`)
+
+	w = do(http.MethodGet, "dev-1", "")
+	assert.Contains(t, w.Body.String(), `"language":"am"`)
+	assert.Contains(t, w.Body.String(), `"maxUsd":40`)
+}
+
+func TestLocalizedContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctxFor := func(lang string, prefs *domain.Preferences) context.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", lang)
+		if prefs != nil {
+			req = req.WithContext(context.WithValue(req.Context(), contextkeys.Preferences, prefs))
+		}
+		c.Request = req
+		return localizedContext(c)
+	}
+
+	ctx := ctxFor("am", nil)
+	assert.Equal(t, "am", ctx.Value(contextkeys.RespLang))
+	assert.Equal(t, "ETB", ctx.Value(contextkeys.RespCurrency))
+
+	ctx = ctxFor("am", &domain.Preferences{Language: "en"})
+	assert.Equal(t, "en", ctx.Value(contextkeys.RespLang))
+	assert.Equal(t, "USD", ctx.Value(contextkeys.RespCurrency))
+
+	ctx = ctxFor("en", &domain.Preferences{Currency: "ETB"})
+	assert.Equal(t, "en", ctx.Value(contextkeys.RespLang))
+	assert.Equal(t, "ETB", ctx.Value(contextkeys.RespCurrency))
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// ProductHandler handles HTTP requests for single products.
+type ProductHandler struct {
+	uc      *usecase.GetProductUseCase
+	history *usecase.PriceHistoryUseCase
+}
+
+// NewProductHandler creates a new ProductHandler with its dependencies.
+func NewProductHandler(uc *usecase.GetProductUseCase, history *usecase.PriceHistoryUseCase) *ProductHandler {
+	return &ProductHandler{uc: uc, history: history}
+}
+
+// GetProduct handles GET /products/:id.
+func (h *ProductHandler) GetProduct(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if !isProductID(id) {
+		invalidInput(c, "product id must be numeric")
+		return
+	}
+
+	p, err := h.uc.Execute(localizedContext(c), id)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"product": p}, Error: nil})
+}
+
+// GetPriceHistory handles GET /products/:id/price-history?windows=7d,30d.
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if !isProductID(id) {
+		invalidInput(c, "product id must be numeric")
+		return
+	}
+
+	var windows []string
+	for _, w := range strings.Split(c.Query("windows"), ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			windows = append(windows, w)
+		}
+	}
+
+	history, err := h.history.History(c.Request.Context(), id, windows)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, envelope{Data: history, Error: nil})
+}
+
+// isProductID reports whether id looks like an AliExpress product ID.
+func isProductID(id string) bool {
+	if id == "" || len(id) > 32 {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductHandler_GetProduct(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uc := usecase.NewGetProductUseCase(gateway.NewMockAlibabaGateway(), nil, nil, 0)
+	h := NewProductHandler(uc, nil)
+
+	router := gin.New()
+	router.GET("/products/:id", h.GetProduct)
+
+	do := func(id string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/products/"+id, nil)
+		router.ServeHTTP(w, req)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w, body
+	}
+
+	t.Run("invalid id", func(t *testing.T) {
+		w, body := do("abc")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_INPUT", body["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		w, body := do("999")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "NOT_FOUND", body["error"].(map[string]interface{})["code"])
+	})
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+)
+
+// SavedItemHandler handles the saved-items endpoints. Items belong to the
+// signed-in user, or to the X-Device-ID of an anonymous caller.
+type SavedItemHandler struct {
+	uc *usecase.SavedItemsUseCase
+}
+
+// NewSavedItemHandler creates a new SavedItemHandler.
+func NewSavedItemHandler(uc *usecase.SavedItemsUseCase) *SavedItemHandler {
+	return &SavedItemHandler{uc: uc}
+}
+
+type savePayload struct {
+	ProductID string `json:"productId"`
+	List      string `json:"list"`
+}
+
+type movePayload struct {
+	List string `json:"list"`
+}
+
+// Save handles POST /saved-items. It returns 201 for a new item and 200 when
+// the product was already saved.
+func (h *SavedItemHandler) Save(c *gin.Context) {
+	var p savePayload
+	if err := c.ShouldBindJSON(&p); err != nil || !isProductID(p.ProductID) {
+		apierror.Respond(c, errInvalidBody)
+		return
+	}
+	item, created, err := h.uc.Save(localizedContext(c), requestOwner(c), p.ProductID, p.List)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, envelope{Data: map[string]interface{}{"item": item}, Error: nil})
+}
+
+// List handles GET /saved-items?list=&sort=&page=&pageSize=.
+func (h *SavedItemHandler) List(c *gin.Context) {
+	q := domain.SavedItemQuery{List: c.Query("list"), Sort: c.Query("sort")}
+	var err error
+	if v := c.Query("page"); v != "" {
+		if q.Page, err = strconv.Atoi(v); err != nil {
+			apierror.Respond(c, usecase.ErrInvalidSavedQuery)
+			return
+		}
+	}
+	if v := c.Query("pageSize"); v != "" {
+		if q.PageSize, err = strconv.Atoi(v); err != nil {
+			apierror.Respond(c, usecase.ErrInvalidSavedQuery)
+			return
+		}
+	}
+	page, err := h.uc.List(localizedContext(c), requestOwner(c), q)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: page, Error: nil})
+}
+
+// Lists handles GET /saved-items/lists.
+func (h *SavedItemHandler) Lists(c *gin.Context) {
+	lists, err := h.uc.Lists(c.Request.Context(), requestOwner(c))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"lists": lists}, Error: nil})
+}
+
+// Move handles PATCH /saved-items/:id and moves the item to another list.
+func (h *SavedItemHandler) Move(c *gin.Context) {
+	var p movePayload
+	if err := c.ShouldBindJSON(&p); err != nil {
+		apierror.Respond(c, errInvalidBody)
+		return
+	}
+	item, err := h.uc.Move(c.Request.Context(), requestOwner(c), c.Param("id"), p.List)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"item": item}, Error: nil})
+}
+
+// Remove handles DELETE /saved-items/:id.
+func (h *SavedItemHandler) Remove(c *gin.Context) {
+	if err := h.uc.Remove(c.Request.Context(), requestOwner(c), c.Param("id")); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, envelope{Data: map[string]interface{}{"removed": true}, Error: nil})
+}
+
+// CreateAlert handles POST /saved-items/:id/alert, a one-tap price alert for
+// the item on the calling device.
+func (h *SavedItemHandler) CreateAlert(c *gin.Context) {
+	alert, err := h.uc.CreateAlert(localizedContext(c), requestOwner(c), c.Param("id"), c.GetHeader("X-Device-ID"))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, envelope{Data: map[string]interface{}{"alert": alert}, Error: nil})
+}
+
+// requestOwner identifies the caller by user when signed in, else by device.
+func requestOwner(c *gin.Context) domain.Owner {
+	owner := domain.Owner{DeviceID: c.GetHeader("X-Device-ID")}
+	if user, ok := currentUser(c.Request.Context()); ok {
+		owner.UserID = user.ID
+	}
+	return owner
+}
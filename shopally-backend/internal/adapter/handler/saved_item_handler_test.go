@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedLookup map[string]float64
+
+func (f fixedLookup) Execute(ctx context.Context, id string) (*domain.Product, error) {
+	usd, ok := f[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+	return &domain.Product{ID: id, Price: domain.Price{USD: usd}}, nil
+}
+
+func TestSavedItemHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uc := usecase.NewSavedItemsUseCase(repository.NewMockSavedItemRepository(), fixedLookup{"1": 10},
+		usecase.NewAlertManager(repository.NewMockAlertRepository()))
+	h := NewSavedItemHandler(uc)
+	r := gin.New()
+	r.POST("/saved-items", h.Save)
+	r.GET("/saved-items", h.List)
+	r.PATCH("/saved-items/:id", h.Move)
+	r.POST("/saved-items/:id/alert", h.CreateAlert)
+
+	do := func(method, path, device, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if device != "" {
+			req.Header.Set("X-Device-ID", device)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/saved-items", "", `{"productId":"1"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/saved-items", "dev-1", `{"productId":"abc"}`).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/saved-items", "dev-1", `{"productId":"2"}`).Code)
+
+	w := do(http.MethodPost, "/saved-items", "dev-1", `{"productId":"1"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp struct {
+		Data struct {
+			Item domain.SavedItem `json:"item"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	id := resp.Data.Item.ID
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/saved-items", "dev-1", `{"productId":"1"}`).Code)
+
+	w = do(http.MethodGet, "/saved-items?sort=price_desc&pageSize=5", "dev-1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"currentPrice"`)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/saved-items?page=x", "dev-1", "").Code)
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPatch, "/saved-items/"+id, "dev-2", `{"list":"gifts"}`).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodPatch, "/saved-items/"+id, "dev-1", `{"list":"gifts"}`).Code)
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/saved-items/"+id+"/alert", "dev-1", "").Code)
+}
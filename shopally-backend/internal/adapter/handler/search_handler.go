@@ -1,18 +1,20 @@
 package handler
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/adapter/apierror"
+	"github.com/shopally-ai/pkg/domain"
 	"github.com/shopally-ai/pkg/usecase"
 )
 
 // SearchHandler handles incoming HTTP requests for the /search endpoint.
 type SearchHandler struct {
-	uc *usecase.SearchProductsUseCase
+	uc    *usecase.SearchProductsUseCase
+	links *usecase.ResolveLinkUseCase
 }
 
 // NewSearchHandler creates a new SearchHandler with its dependencies.
@@ -20,6 +22,13 @@ func NewSearchHandler(uc *usecase.SearchProductsUseCase) *SearchHandler {
 	return &SearchHandler{uc: uc}
 }
 
+// WithLinkResolver makes Search resolve pasted AliExpress links directly
+// instead of running them through intent parsing.
+func (h *SearchHandler) WithLinkResolver(links *usecase.ResolveLinkUseCase) *SearchHandler {
+	h.links = links
+	return h
+}
+
 type envelope struct {
 	Data  interface{} `json:"data"`
 	Error interface{} `json:"error"`
@@ -30,31 +39,30 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	// Basic required param validation per contract
 	q := strings.TrimSpace(c.Query("q"))
 	if q == "" {
-		c.JSON(http.StatusBadRequest, envelope{Data: nil, Error: map[string]interface{}{
-			"code":    "INVALID_INPUT",
-			"message": "missing required query parameter: q",
-		}})
+		invalidInput(c, "missing required query parameter: q")
 		return
 	}
 
-	// get from header
-	lang := strings.ToLower(strings.TrimSpace(c.GetHeader("Accept-Language")))
-
-	ctx := c.Request.Context()
-	if lang == "am" {
-		ctx = context.WithValue(ctx, contextkeys.RespLang, "am")
-		ctx = context.WithValue(ctx, contextkeys.RespCurrency, "ETB")
-	} else {
-		ctx = context.WithValue(ctx, contextkeys.RespLang, "en")
-		ctx = context.WithValue(ctx, contextkeys.RespCurrency, "USD")
+	if h.links != nil {
+		if link, ok := h.links.DetectLink(q); ok {
+			p, err := h.links.Execute(localizedContext(c), link)
+			switch {
+			case err == nil:
+				c.JSON(http.StatusOK, resolvedLinkEnvelope(p, link))
+				return
+			case !errors.Is(err, domain.ErrUnsupportedLink):
+				apierror.Respond(c, err)
+				return
+			}
+			// Not an item link, e.g. a store page: search the text instead.
+		}
 	}
 
+	ctx := localizedContext(c)
+
 	data, err := h.uc.Search(ctx, q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, envelope{Data: nil, Error: map[string]interface{}{
-			"code":    "INTERNAL_SERVER_ERROR",
-			"message": err.Error(),
-		}})
+		apierror.Respond(c, err)
 		return
 	}
 
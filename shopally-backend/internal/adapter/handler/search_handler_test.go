@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopally-ai/internal/adapter/gateway"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// linkResolverFunc adapts a function to domain.LinkResolver.
+type linkResolverFunc func(ctx context.Context, rawURL string) (string, error)
+
+func (f linkResolverFunc) ResolveProductID(ctx context.Context, rawURL string) (string, error) {
+	return f(ctx, rawURL)
+}
+
+func TestSearchHandler_Links(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ag := gateway.NewMockAlibabaGateway()
+	products := usecase.NewGetProductUseCase(ag, nil, nil, 0)
+	resolver := linkResolverFunc(func(_ context.Context, raw string) (string, error) {
+		if u, _ := url.Parse(raw); u != nil && u.Path == "/item/1005001.html" {
+			return "MOCK-123", nil
+		}
+		return "", domain.ErrUnsupportedLink
+	})
+	h := NewSearchHandler(usecase.NewSearchProductsUseCase(ag, gateway.NewMockLLMGateway(), nil)).
+		WithLinkResolver(usecase.NewResolveLinkUseCase(resolver, products))
+
+	router := gin.New()
+	router.GET("/search", h.Search)
+	search := func(q string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q="+url.QueryEscape(q), nil))
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+		return w.Code, body
+	}
+
+	t.Run("item links resolve to the product", func(t *testing.T) {
+		status, body := search("https://www.aliexpress.com/item/1005001.html")
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body["data"], "resolvedLink")
+	})
+
+	t.Run("other links fall through to text search", func(t *testing.T) {
+		status, body := search("headphones like https://www.aliexpress.com/store/123")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.NotContains(t, body["data"], "resolvedLink")
+		assert.Nil(t, body["error"])
+	})
+}
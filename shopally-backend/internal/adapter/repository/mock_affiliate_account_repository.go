@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// MockAffiliateAccountRepository is a simple in-memory implementation used by unit tests.
+type MockAffiliateAccountRepository struct {
+	mu       sync.Mutex
+	accounts map[string]domain.AffiliateAccount // key: user ID
+}
+
+var _ domain.AffiliateAccountRepository = (*MockAffiliateAccountRepository)(nil)
+
+func NewMockAffiliateAccountRepository() *MockAffiliateAccountRepository {
+	return &MockAffiliateAccountRepository{accounts: map[string]domain.AffiliateAccount{}}
+}
+
+func (r *MockAffiliateAccountRepository) Upsert(ctx context.Context, a *domain.AffiliateAccount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.accounts[a.UserID] = *a
+	return nil
+}
+
+func (r *MockAffiliateAccountRepository) GetByUser(ctx context.Context, userID string) (*domain.AffiliateAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.accounts[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+func (r *MockAffiliateAccountRepository) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.accounts, userID)
+	return nil
+}
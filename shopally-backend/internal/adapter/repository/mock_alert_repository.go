@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"errors"
+	"context"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
@@ -13,6 +14,8 @@ type MockAlertRepository struct {
 	alerts sync.Map // key: ID, value: *domain.Alert
 }
 
+var _ domain.AlertOwnerRepository = (*MockAlertRepository)(nil)
+
 func NewMockAlertRepository() *MockAlertRepository {
 	return &MockAlertRepository{}
 }
@@ -36,7 +39,7 @@ func (r *MockAlertRepository) GetAlert(alertID string) (*domain.Alert, error) {
 		a := *(v.(*domain.Alert))
 		return &a, nil
 	}
-	return nil, errors.New("alert not found")
+	return nil, domain.ErrAlertNotFound
 }
 
 func (r *MockAlertRepository) DeleteAlert(alertID string) error {
@@ -46,5 +49,35 @@ func (r *MockAlertRepository) DeleteAlert(alertID string) error {
 		r.alerts.Store(alertID, a)
 		return nil
 	}
-	return errors.New("alert not found")
+	return domain.ErrAlertNotFound
+}
+
+func (r *MockAlertRepository) ListUnclaimed(ctx context.Context, deviceID string) ([]domain.Alert, error) {
+	return r.filter(func(a *domain.Alert) bool { return a.DeviceID == deviceID && a.UserID == "" && a.IsActive }), nil
+}
+
+func (r *MockAlertRepository) ListByUser(ctx context.Context, userID string) ([]domain.Alert, error) {
+	return r.filter(func(a *domain.Alert) bool { return a.UserID == userID && a.IsActive }), nil
+}
+
+func (r *MockAlertRepository) AssignUser(ctx context.Context, alertID, userID string) error {
+	if v, ok := r.alerts.Load(alertID); ok {
+		a := *(v.(*domain.Alert))
+		a.UserID = userID
+		r.alerts.Store(alertID, &a)
+		return nil
+	}
+	return domain.ErrAlertNotFound
+}
+
+func (r *MockAlertRepository) filter(keep func(*domain.Alert) bool) []domain.Alert {
+	out := []domain.Alert{}
+	r.alerts.Range(func(_, v any) bool {
+		if a := v.(*domain.Alert); keep(a) {
+			out = append(out, *a)
+		}
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
 }
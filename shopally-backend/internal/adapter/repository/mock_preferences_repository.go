@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// MockPreferencesRepository is a simple in-memory implementation used by unit tests.
+type MockPreferencesRepository struct {
+	mu    sync.Mutex
+	prefs map[string]domain.Preferences // key: owner key
+}
+
+var _ domain.PreferencesRepository = (*MockPreferencesRepository)(nil)
+
+func NewMockPreferencesRepository() *MockPreferencesRepository {
+	return &MockPreferencesRepository{prefs: map[string]domain.Preferences{}}
+}
+
+func (r *MockPreferencesRepository) Get(ctx context.Context, owner string) (*domain.Preferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.prefs[owner]
+	if !ok {
+		return nil, nil
+	}
+	p.NotificationChannels = append([]string(nil), p.NotificationChannels...)
+	return &p, nil
+}
+
+func (r *MockPreferencesRepository) Upsert(ctx context.Context, p *domain.Preferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *p
+	cp.NotificationChannels = append([]string(nil), p.NotificationChannels...)
+	r.prefs[p.Owner] = cp
+	return nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// MockPriceHistoryRepository is a simple in-memory implementation used by unit tests.
+type MockPriceHistoryRepository struct {
+	mu   sync.Mutex
+	data map[string][]domain.PriceObservation // key: product ID, oldest first
+}
+
+var _ domain.PriceHistoryRepository = (*MockPriceHistoryRepository)(nil)
+
+func NewMockPriceHistoryRepository() *MockPriceHistoryRepository {
+	return &MockPriceHistoryRepository{data: map[string][]domain.PriceObservation{}}
+}
+
+func (r *MockPriceHistoryRepository) Record(ctx context.Context, obs *domain.PriceObservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := append(r.data[obs.ProductID], *obs)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].ObservedAt.Before(list[j].ObservedAt) })
+	r.data[obs.ProductID] = list
+	return nil
+}
+
+func (r *MockPriceHistoryRepository) Latest(ctx context.Context, productID string) (*domain.PriceObservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := r.data[productID]
+	if len(list) == 0 {
+		return nil, nil
+	}
+	obs := list[len(list)-1]
+	return &obs, nil
+}
+
+func (r *MockPriceHistoryRepository) List(ctx context.Context, productID string, since time.Time) ([]domain.PriceObservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.PriceObservation{}
+	for _, obs := range r.data[productID] {
+		if !obs.ObservedAt.Before(since) {
+			out = append(out, obs)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// MockSavedItemRepository is a simple in-memory implementation used by unit tests.
+type MockSavedItemRepository struct {
+	mu    sync.Mutex
+	items map[string]domain.SavedItem // key: item ID
+}
+
+var _ domain.SavedItemRepository = (*MockSavedItemRepository)(nil)
+
+func NewMockSavedItemRepository() *MockSavedItemRepository {
+	return &MockSavedItemRepository{items: map[string]domain.SavedItem{}}
+}
+
+func (r *MockSavedItemRepository) Create(ctx context.Context, item *domain.SavedItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, it := range r.items {
+		if it.Owner == item.Owner && it.ProductID == item.ProductID {
+			return domain.ErrAlreadySaved
+		}
+	}
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *MockSavedItemRepository) Get(ctx context.Context, owner, id string) (*domain.SavedItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	it, ok := r.items[id]
+	if !ok || it.Owner != owner {
+		return nil, domain.ErrSavedItemNotFound
+	}
+	return &it, nil
+}
+
+func (r *MockSavedItemRepository) GetByProduct(ctx context.Context, owner, productID string) (*domain.SavedItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, it := range r.items {
+		if it.Owner == owner && it.ProductID == productID {
+			return &it, nil
+		}
+	}
+	return nil, domain.ErrSavedItemNotFound
+}
+
+func (r *MockSavedItemRepository) List(ctx context.Context, owner string, q domain.SavedItemQuery) ([]domain.SavedItem, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	matched := []domain.SavedItem{}
+	for _, it := range r.items {
+		if it.Owner == owner && (q.List == "" || it.List == q.List) {
+			matched = append(matched, it)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		switch q.Sort {
+		case domain.SavedSortOldest:
+			if !a.SavedAt.Equal(b.SavedAt) {
+				return a.SavedAt.Before(b.SavedAt)
+			}
+		case domain.SavedSortPriceAsc:
+			if a.Snapshot.Price.USD != b.Snapshot.Price.USD {
+				return a.Snapshot.Price.USD < b.Snapshot.Price.USD
+			}
+		case domain.SavedSortPriceDesc:
+			if a.Snapshot.Price.USD != b.Snapshot.Price.USD {
+				return a.Snapshot.Price.USD > b.Snapshot.Price.USD
+			}
+		case domain.SavedSortTitle:
+			if a.Snapshot.Title != b.Snapshot.Title {
+				return a.Snapshot.Title < b.Snapshot.Title
+			}
+		default:
+			if !a.SavedAt.Equal(b.SavedAt) {
+				return a.SavedAt.After(b.SavedAt)
+			}
+		}
+		return a.ID < b.ID
+	})
+	total := len(matched)
+	start := (q.Page - 1) * q.PageSize
+	if start > total {
+		start = total
+	}
+	end := start + q.PageSize
+	if end > total {
+		end = total
+	}
+	return matched[start:end], total, nil
+}
+
+func (r *MockSavedItemRepository) Lists(ctx context.Context, owner string) ([]domain.SavedList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := map[string]int{}
+	for _, it := range r.items {
+		if it.Owner == owner {
+			counts[it.List]++
+		}
+	}
+	lists := make([]domain.SavedList, 0, len(counts))
+	for name, n := range counts {
+		lists = append(lists, domain.SavedList{Name: name, Count: n})
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Name < lists[j].Name })
+	return lists, nil
+}
+
+func (r *MockSavedItemRepository) Update(ctx context.Context, item *domain.SavedItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	it, ok := r.items[item.ID]
+	if !ok || it.Owner != item.Owner {
+		return domain.ErrSavedItemNotFound
+	}
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *MockSavedItemRepository) Delete(ctx context.Context, owner, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	it, ok := r.items[id]
+	if !ok || it.Owner != owner {
+		return domain.ErrSavedItemNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+func (r *MockSavedItemRepository) ListAll(ctx context.Context, owner string) ([]domain.SavedItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := []domain.SavedItem{}
+	for _, it := range r.items {
+		if it.Owner == owner {
+			items = append(items, it)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+func (r *MockSavedItemRepository) Reassign(ctx context.Context, id, from, to string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	it, ok := r.items[id]
+	if !ok || it.Owner != from {
+		return domain.ErrSavedItemNotFound
+	}
+	for _, other := range r.items {
+		if other.Owner == to && other.ProductID == it.ProductID {
+			return domain.ErrAlreadySaved
+		}
+	}
+	it.Owner = to
+	r.items[id] = it
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// MockSessionRepository is a simple in-memory implementation used by unit tests.
+type MockSessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]domain.RefreshSession
+}
+
+var _ domain.SessionRepository = (*MockSessionRepository)(nil)
+
+func NewMockSessionRepository() *MockSessionRepository {
+	return &MockSessionRepository{sessions: map[string]domain.RefreshSession{}}
+}
+
+func (r *MockSessionRepository) Create(ctx context.Context, s *domain.RefreshSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[s.ID] = *s
+	return nil
+}
+
+func (r *MockSessionRepository) Get(ctx context.Context, id string) (*domain.RefreshSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (r *MockSessionRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok || s.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	s.RevokedAt = &now
+	r.sessions[id] = s
+	return true, nil
+}
+
+func (r *MockSessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	for id, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &now
+			r.sessions[id] = s
+		}
+	}
+	return nil
+}
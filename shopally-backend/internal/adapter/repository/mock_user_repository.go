@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// MockUserRepository is a simple in-memory implementation used by unit tests.
+type MockUserRepository struct {
+	mu    sync.Mutex
+	users map[string]domain.User // key: user ID
+}
+
+var _ domain.UserRepository = (*MockUserRepository)(nil)
+
+func NewMockUserRepository() *MockUserRepository {
+	return &MockUserRepository{users: map[string]domain.User{}}
+}
+
+func (r *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email == user.Email {
+			return domain.ErrEmailTaken
+		}
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *MockUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &u, nil
+}
+
+func (r *MockUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *MockUserRepository) GetByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		for _, id := range u.Identities {
+			if id.Provider == provider && id.Subject == subject {
+				return &u, nil
+			}
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *MockUserRepository) AddIdentity(ctx context.Context, userID string, identity domain.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[userID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	u.Identities = append(append([]domain.Identity(nil), u.Identities...), identity)
+	r.users[userID] = u
+	return nil
+}
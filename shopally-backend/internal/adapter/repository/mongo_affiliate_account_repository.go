@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// affiliateAccountDoc is the stored form of domain.AffiliateAccount. OAuth
+// tokens are only ever written sealed by the repository's SecretBox.
+type affiliateAccountDoc struct {
+	UserID           string    `bson:"_id"`
+	AccountID        string    `bson:"accountId"`
+	Account          string    `bson:"account"`
+	TrackingID       string    `bson:"trackingId,omitempty"`
+	AccessToken      string    `bson:"accessTokenEnc"`
+	RefreshToken     string    `bson:"refreshTokenEnc"`
+	AccessExpiresAt  time.Time `bson:"accessExpiresAt"`
+	RefreshExpiresAt time.Time `bson:"refreshExpiresAt"`
+	LinkedAt         time.Time `bson:"linkedAt"`
+	UpdatedAt        time.Time `bson:"updatedAt"`
+	NeedsRelink      bool      `bson:"needsRelink,omitempty"`
+	RefreshRetryAt   time.Time `bson:"refreshRetryAt,omitempty"`
+}
+
+// MongoAffiliateAccountRepository implements domain.AffiliateAccountRepository
+// using MongoDB, keyed by user ID, with tokens encrypted at rest.
+type MongoAffiliateAccountRepository struct {
+	coll *mongo.Collection
+	box  *util.SecretBox
+}
+
+var _ domain.AffiliateAccountRepository = (*MongoAffiliateAccountRepository)(nil)
+
+// NewMongoAffiliateAccountRepository creates a repository that seals tokens with box.
+func NewMongoAffiliateAccountRepository(coll *mongo.Collection, box *util.SecretBox) *MongoAffiliateAccountRepository {
+	return &MongoAffiliateAccountRepository{coll: coll, box: box}
+}
+
+func (r *MongoAffiliateAccountRepository) Upsert(ctx context.Context, a *domain.AffiliateAccount) error {
+	access, err := r.box.Seal(a.AccessToken)
+	if err != nil {
+		return err
+	}
+	refresh, err := r.box.Seal(a.RefreshToken)
+	if err != nil {
+		return err
+	}
+	doc := affiliateAccountDoc{
+		UserID:           a.UserID,
+		AccountID:        a.AccountID,
+		Account:          a.Account,
+		TrackingID:       a.TrackingID,
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		AccessExpiresAt:  a.AccessExpiresAt,
+		RefreshExpiresAt: a.RefreshExpiresAt,
+		LinkedAt:         a.LinkedAt,
+		UpdatedAt:        a.UpdatedAt,
+		NeedsRelink:      a.NeedsRelink,
+		RefreshRetryAt:   a.RefreshRetryAt,
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err = r.coll.ReplaceOne(ctx, bson.M{"_id": a.UserID}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoAffiliateAccountRepository) GetByUser(ctx context.Context, userID string) (*domain.AffiliateAccount, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var doc affiliateAccountDoc
+	if err := r.coll.FindOne(ctx, bson.M{"_id": userID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	access, err := r.box.Open(doc.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := r.box.Open(doc.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AffiliateAccount{
+		UserID:           doc.UserID,
+		AccountID:        doc.AccountID,
+		Account:          doc.Account,
+		TrackingID:       doc.TrackingID,
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		AccessExpiresAt:  doc.AccessExpiresAt,
+		RefreshExpiresAt: doc.RefreshExpiresAt,
+		LinkedAt:         doc.LinkedAt,
+		UpdatedAt:        doc.UpdatedAt,
+		NeedsRelink:      doc.NeedsRelink,
+		RefreshRetryAt:   doc.RefreshRetryAt,
+	}, nil
+}
+
+func (r *MongoAffiliateAccountRepository) Delete(ctx context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
@@ -16,6 +16,8 @@ type MongoAlertRepository struct {
 	coll *mongo.Collection
 }
 
+var _ domain.AlertOwnerRepository = (*MongoAlertRepository)(nil)
+
 // NewMongoAlertRepository creates a new MongoAlertRepository with the provided collection.
 func NewMongoAlertRepository(coll *mongo.Collection) *MongoAlertRepository {
 	return &MongoAlertRepository{coll: coll}
@@ -34,6 +36,7 @@ func (r *MongoAlertRepository) CreateAlert(alert *domain.Alert) error {
 	_, err := r.coll.InsertOne(ctx, bson.M{
 		"ID":           alert.ID,
 		"DeviceID":     alert.DeviceID,
+		"UserID":       alert.UserID,
 		"ProductID":    alert.ProductID,
 		"CurrentPrice": alert.CurrentPrice,
 		"IsActive":     alert.IsActive,
@@ -48,7 +51,7 @@ func (r *MongoAlertRepository) GetAlert(alertID string) (*domain.Alert, error) {
 	err := r.coll.FindOne(ctx, bson.M{"ID": alertID}).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, err
+			return nil, domain.ErrAlertNotFound
 		}
 		return nil, err
 	}
@@ -63,7 +66,46 @@ func (r *MongoAlertRepository) DeleteAlert(alertID string) error {
 		return err
 	}
 	if res.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+		return domain.ErrAlertNotFound
 	}
 	return nil
 }
+
+func (r *MongoAlertRepository) ListUnclaimed(ctx context.Context, deviceID string) ([]domain.Alert, error) {
+	return r.find(ctx, bson.M{
+		"DeviceID": deviceID,
+		"IsActive": true,
+		"$or":      bson.A{bson.M{"UserID": bson.M{"$exists": false}}, bson.M{"UserID": ""}},
+	})
+}
+
+func (r *MongoAlertRepository) ListByUser(ctx context.Context, userID string) ([]domain.Alert, error) {
+	return r.find(ctx, bson.M{"UserID": userID, "IsActive": true})
+}
+
+func (r *MongoAlertRepository) AssignUser(ctx context.Context, alertID, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	res, err := r.coll.UpdateOne(ctx, bson.M{"ID": alertID}, bson.M{"$set": bson.M{"UserID": userID}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrAlertNotFound
+	}
+	return nil
+}
+
+func (r *MongoAlertRepository) find(ctx context.Context, filter bson.M) ([]domain.Alert, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	cur, err := r.coll.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	alerts := []domain.Alert{}
+	if err := cur.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
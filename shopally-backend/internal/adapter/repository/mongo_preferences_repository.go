@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPreferencesRepository implements domain.PreferencesRepository using
+// MongoDB, one document per owner key.
+type MongoPreferencesRepository struct {
+	coll *mongo.Collection
+}
+
+var _ domain.PreferencesRepository = (*MongoPreferencesRepository)(nil)
+
+func NewMongoPreferencesRepository(coll *mongo.Collection) *MongoPreferencesRepository {
+	return &MongoPreferencesRepository{coll: coll}
+}
+
+func (r *MongoPreferencesRepository) Get(ctx context.Context, owner string) (*domain.Preferences, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var p domain.Preferences
+	if err := r.coll.FindOne(ctx, bson.M{"_id": owner}).Decode(&p); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *MongoPreferencesRepository) Upsert(ctx context.Context, p *domain.Preferences) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.ReplaceOne(ctx, bson.M{"_id": p.Owner}, p, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPriceHistoryRepository implements domain.PriceHistoryRepository using MongoDB.
+type MongoPriceHistoryRepository struct {
+	coll *mongo.Collection
+}
+
+var _ domain.PriceHistoryRepository = (*MongoPriceHistoryRepository)(nil)
+
+// NewMongoPriceHistoryRepository creates a new MongoPriceHistoryRepository with the provided collection.
+func NewMongoPriceHistoryRepository(coll *mongo.Collection) *MongoPriceHistoryRepository {
+	return &MongoPriceHistoryRepository{coll: coll}
+}
+
+// EnsureIndexes creates the (productId, observedAt) index used by all queries.
+func (r *MongoPriceHistoryRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "productId", Value: 1}, {Key: "observedAt", Value: -1}},
+	})
+	return err
+}
+
+func (r *MongoPriceHistoryRepository) Record(ctx context.Context, obs *domain.PriceObservation) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.InsertOne(ctx, obs)
+	return err
+}
+
+func (r *MongoPriceHistoryRepository) Latest(ctx context.Context, productID string) (*domain.PriceObservation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var obs domain.PriceObservation
+	opts := options.FindOne().SetSort(bson.D{{Key: "observedAt", Value: -1}})
+	err := r.coll.FindOne(ctx, bson.M{"productId": productID}, opts).Decode(&obs)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &obs, nil
+}
+
+func (r *MongoPriceHistoryRepository) List(ctx context.Context, productID string, since time.Time) ([]domain.PriceObservation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	filter := bson.M{"productId": productID, "observedAt": bson.M{"$gte": since}}
+	opts := options.Find().SetSort(bson.D{{Key: "observedAt", Value: 1}})
+	cur, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := []domain.PriceObservation{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSavedItemRepository implements domain.SavedItemRepository using MongoDB.
+type MongoSavedItemRepository struct {
+	coll *mongo.Collection
+}
+
+var _ domain.SavedItemRepository = (*MongoSavedItemRepository)(nil)
+
+// NewMongoSavedItemRepository creates a new MongoSavedItemRepository with the provided collection.
+func NewMongoSavedItemRepository(coll *mongo.Collection) *MongoSavedItemRepository {
+	return &MongoSavedItemRepository{coll: coll}
+}
+
+// EnsureIndexes makes a product unique per owner and indexes the list views.
+func (r *MongoSavedItemRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "owner", Value: 1}, {Key: "productId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "list", Value: 1}, {Key: "savedAt", Value: -1}}},
+	})
+	return err
+}
+
+func (r *MongoSavedItemRepository) Create(ctx context.Context, item *domain.SavedItem) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.InsertOne(ctx, item)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrAlreadySaved
+	}
+	return err
+}
+
+func (r *MongoSavedItemRepository) Get(ctx context.Context, owner, id string) (*domain.SavedItem, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "owner": owner})
+}
+
+func (r *MongoSavedItemRepository) GetByProduct(ctx context.Context, owner, productID string) (*domain.SavedItem, error) {
+	return r.findOne(ctx, bson.M{"owner": owner, "productId": productID})
+}
+
+// savedSortFields maps a sort order to its Mongo sort; _id breaks ties so
+// pages are stable.
+var savedSortFields = map[string]bson.D{
+	domain.SavedSortNewest:    {{Key: "savedAt", Value: -1}, {Key: "_id", Value: 1}},
+	domain.SavedSortOldest:    {{Key: "savedAt", Value: 1}, {Key: "_id", Value: 1}},
+	domain.SavedSortPriceAsc:  {{Key: "snapshot.price.usd", Value: 1}, {Key: "_id", Value: 1}},
+	domain.SavedSortPriceDesc: {{Key: "snapshot.price.usd", Value: -1}, {Key: "_id", Value: 1}},
+	domain.SavedSortTitle:     {{Key: "snapshot.title", Value: 1}, {Key: "_id", Value: 1}},
+}
+
+func (r *MongoSavedItemRepository) List(ctx context.Context, owner string, q domain.SavedItemQuery) ([]domain.SavedItem, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	filter := bson.M{"owner": owner}
+	if q.List != "" {
+		filter["list"] = q.List
+	}
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort, ok := savedSortFields[q.Sort]
+	if !ok {
+		sort = savedSortFields[domain.SavedSortNewest]
+	}
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(int64((q.Page - 1) * q.PageSize)).
+		SetLimit(int64(q.PageSize))
+	cur, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	items := []domain.SavedItem{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, 0, err
+	}
+	return items, int(total), nil
+}
+
+func (r *MongoSavedItemRepository) ListAll(ctx context.Context, owner string) ([]domain.SavedItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	cur, err := r.coll.Find(ctx, bson.M{"owner": owner}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	items := []domain.SavedItem{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoSavedItemRepository) Lists(ctx context.Context, owner string) ([]domain.SavedList, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	cur, err := r.coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"owner": owner}}},
+		{{Key: "$group", Value: bson.M{"_id": "$list", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	lists := []domain.SavedList{}
+	if err := cur.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (r *MongoSavedItemRepository) Update(ctx context.Context, item *domain.SavedItem) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": item.ID, "owner": item.Owner}, item)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrSavedItemNotFound
+	}
+	return nil
+}
+
+func (r *MongoSavedItemRepository) Delete(ctx context.Context, owner, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id, "owner": owner})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrSavedItemNotFound
+	}
+	return nil
+}
+
+func (r *MongoSavedItemRepository) Reassign(ctx context.Context, id, from, to string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "owner": from},
+		bson.M{"$set": bson.M{"owner": to, "updatedAt": time.Now().UTC()}})
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrAlreadySaved
+	}
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrSavedItemNotFound
+	}
+	return nil
+}
+
+func (r *MongoSavedItemRepository) findOne(ctx context.Context, filter bson.M) (*domain.SavedItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var item domain.SavedItem
+	if err := r.coll.FindOne(ctx, filter).Decode(&item); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrSavedItemNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSessionRepository implements domain.SessionRepository using MongoDB.
+type MongoSessionRepository struct {
+	coll *mongo.Collection
+}
+
+var _ domain.SessionRepository = (*MongoSessionRepository)(nil)
+
+// NewMongoSessionRepository creates a new MongoSessionRepository with the provided collection.
+func NewMongoSessionRepository(coll *mongo.Collection) *MongoSessionRepository {
+	return &MongoSessionRepository{coll: coll}
+}
+
+// EnsureIndexes creates the user index and a TTL index that drops sessions
+// once they expire.
+func (r *MongoSessionRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}}},
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (r *MongoSessionRepository) Create(ctx context.Context, s *domain.RefreshSession) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.InsertOne(ctx, s)
+	return err
+}
+
+func (r *MongoSessionRepository) Get(ctx context.Context, id string) (*domain.RefreshSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var s domain.RefreshSession
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&s); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *MongoSessionRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	res, err := r.coll.UpdateOne(ctx,
+		bson.M{"_id": id, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *MongoSessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.UpdateMany(ctx,
+		bson.M{"userId": userID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}})
+	return err
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoUserRepository implements domain.UserRepository using MongoDB.
+type MongoUserRepository struct {
+	coll *mongo.Collection
+}
+
+var _ domain.UserRepository = (*MongoUserRepository)(nil)
+
+// NewMongoUserRepository creates a new MongoUserRepository with the provided collection.
+func NewMongoUserRepository(coll *mongo.Collection) *MongoUserRepository {
+	return &MongoUserRepository{coll: coll}
+}
+
+// EnsureIndexes creates the unique email and linked-identity indexes.
+func (r *MongoUserRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "identities.provider", Value: 1}, {Key: "identities.subject", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	})
+	return err
+}
+
+func (r *MongoUserRepository) Create(ctx context.Context, user *domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := r.coll.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrEmailTaken
+	}
+	return err
+}
+
+func (r *MongoUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *MongoUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.findOne(ctx, bson.M{"email": email})
+}
+
+func (r *MongoUserRepository) GetByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	return r.findOne(ctx, bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}})
+}
+
+func (r *MongoUserRepository) AddIdentity(ctx context.Context, userID string, identity domain.Identity) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
+		"$push": bson.M{"identities": identity},
+		"$set":  bson.M{"updatedAt": time.Now().UTC()},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) findOne(ctx context.Context, filter bson.M) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var user domain.User
+	if err := r.coll.FindOne(ctx, filter).Decode(&user); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
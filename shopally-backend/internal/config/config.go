@@ -7,12 +7,22 @@ import (
 type Config struct {
 	Server struct {
 		Port string `mapstructure:"port"`
+		// DevMode relaxes startup checks meant for deployments, e.g. it
+		// allows running without auth.jwt_secret. Never set it in production.
+		DevMode bool `mapstructure:"dev_mode"`
 	} `mapstructure:"server"`
 
 	Mongo struct {
 		URI             string `mapstructure:"uri"`
 		Database        string `mapstructure:"database"`
 		AlertCollection string `mapstructure:"alert_collection"`
+
+		PriceHistoryCollection string `mapstructure:"price_history_collection"`
+		UserCollection         string `mapstructure:"user_collection"`
+		SessionCollection      string `mapstructure:"session_collection"`
+		AffiliateCollection    string `mapstructure:"affiliate_collection"`
+		SavedItemCollection    string `mapstructure:"saved_item_collection"`
+		PreferenceCollection   string `mapstructure:"preference_collection"`
 	} `mapstructure:"mongo"`
 
 	Redis struct {
@@ -30,23 +40,53 @@ type Config struct {
 		CacheTTLSeconds int    `mapstructure:"cache_ttl_seconds"`
 	}
 
+	Auth struct {
+		JWTSecret        string `mapstructure:"jwt_secret"`
+		Issuer           string `mapstructure:"issuer"`
+		AccessTTLMinutes int    `mapstructure:"access_ttl_minutes"`
+		RefreshTTLHours  int    `mapstructure:"refresh_ttl_hours"`
+		// EncryptionKey is a base64-encoded 32-byte key that encrypts stored
+		// OAuth tokens. It is required when AliExpress account linking is
+		// configured.
+		EncryptionKey string `mapstructure:"encryption_key"`
+	} `mapstructure:"auth"`
+
 	OAuth struct {
 		Google struct {
 			ClientID     string `mapstructure:"client_id"`
 			ClientSecret string `mapstructure:"client_secret"`
 			RedirectURI  string `mapstructure:"redirect_uri"`
+			// AuthURL, TokenURL and UserInfoURL override Google's endpoints,
+			// e.g. to point at a local fake OAuth server.
+			AuthURL     string `mapstructure:"auth_url"`
+			TokenURL    string `mapstructure:"token_url"`
+			UserInfoURL string `mapstructure:"userinfo_url"`
+			// FrontendURL is the web app page the callback redirects to with a
+			// one-time code (needs Redis). When unset the callback returns the
+			// tokens as JSON.
+			FrontendURL string `mapstructure:"frontend_url"`
 		} `mapstructure:"google"`
 
 		Aliexpress struct {
 			ClientID     string `mapstructure:"client_id"`
 			ClientSecret string `mapstructure:"client_secret"`
 			RedirectURI  string `mapstructure:"redirect_uri"`
+			// AuthURL and RestURL override the Open Platform endpoints.
+			AuthURL string `mapstructure:"auth_url"`
+			RestURL string `mapstructure:"rest_url"`
 		} `mapstructure:"aliexpress"`
 	} `mapstructure:"oauth"`
 
 	RateLimit struct {
 		Limit  int `mapstructure:"limit"`
 		Window int `mapstructure:"window"`
+		// UserLimit is the limit per window for signed-in users; Limit applies when unset.
+		UserLimit int `mapstructure:"user_limit"`
+		// Costs overrides the tokens a route takes, keyed by route pattern.
+		Costs map[string]int `mapstructure:"costs"`
+		// FailurePolicy is "open" (in-process limiting, the default) or
+		// "closed" (reject with 503) while Redis is unavailable.
+		FailurePolicy string `mapstructure:"failure_policy"`
 	} `mapstructure:"rate-limit"`
 
 	Aliexpress struct {
@@ -56,6 +96,50 @@ type Config struct {
 		BaseURL     string `mapstructure:"base_url"`
 	} `mapstructure:"aliexpress"`
 
+	Search struct {
+		PriceRelaxMargin float64 `mapstructure:"price_relax_margin"`
+		// PriceDedupMinutes suppresses repeat observations of an unchanged price.
+		PriceDedupMinutes int `mapstructure:"price_dedup_minutes"`
+	} `mapstructure:"search"`
+
+	Compare struct {
+		// ResultTTLMinutes is how long comparison results stay cached and shareable.
+		ResultTTLMinutes int `mapstructure:"result_ttl_minutes"`
+	} `mapstructure:"compare"`
+
+	LandedCost struct {
+		// TariffFile is an optional JSON tariff table; the built-in table is used when empty.
+		TariffFile string `mapstructure:"tariff_file"`
+	} `mapstructure:"landed_cost"`
+
+	Logging struct {
+		// Level is debug, info, warn or error.
+		Level string `mapstructure:"level"`
+		// Format is json (default) or text.
+		Format string `mapstructure:"format"`
+		// RedactQueries hides user searches and LLM prompts in logs.
+		RedactQueries bool `mapstructure:"redact_queries"`
+	} `mapstructure:"logging"`
+
+	Metrics struct {
+		// Addr is the private listen address of /metrics, e.g. ":9090",
+		// kept off the public API port. Empty disables the endpoint.
+		Addr string `mapstructure:"addr"`
+		// WorkerAddr is the same for the worker, which may run on the same
+		// host as the API.
+		WorkerAddr string `mapstructure:"worker_addr"`
+	} `mapstructure:"metrics"`
+
+	Tracing struct {
+		// Exporter is off (default), stdout or otlp.
+		Exporter string `mapstructure:"exporter"`
+		// Endpoint is the OTLP/HTTP collector address, e.g. localhost:4318.
+		Endpoint string `mapstructure:"endpoint"`
+		Insecure bool   `mapstructure:"insecure"`
+		// SampleRatio is the fraction of new traces kept; 0 keeps all.
+		SampleRatio float64 `mapstructure:"sample_ratio"`
+	} `mapstructure:"tracing"`
+
 	Gemini struct {
 		APIKey string `mapstructure:"api_key"`
 	} `mapstructure:"gemini"`
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// LoadTariffTable reads a landed-cost tariff table from a JSON file.
+func LoadTariffTable(path string) (domain.TariffTable, error) {
+	var table domain.TariffTable
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return table, err
+	}
+	if err := json.Unmarshal(b, &table); err != nil {
+		return table, fmt.Errorf("parse tariff table %s: %w", path, err)
+	}
+	return table, nil
+}
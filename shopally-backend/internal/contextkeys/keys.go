@@ -9,4 +9,13 @@ type key string
 var (
 	RespLang     = key("resp_lang")
 	RespCurrency = key("resp_currency")
+	// User holds the authenticated *domain.User, if any.
+	User = key("user")
+	// Affiliate holds the *domain.AffiliateCredentials of the caller's linked
+	// AliExpress account, if any.
+	Affiliate = key("affiliate")
+	// RequestID holds the request's correlation ID (X-Request-ID).
+	RequestID = key("request_id")
+	// Preferences holds the caller's stored *domain.Preferences, if any.
+	Preferences = key("preferences")
 )
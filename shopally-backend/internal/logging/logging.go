@@ -0,0 +1,142 @@
+// Package logging configures the process-wide structured logger (log/slog)
+// and hands out per-component loggers.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config selects the level, format and redaction of log output.
+type Config struct {
+	// Level is debug, info (default), warn or error.
+	Level string
+	// Format is json (default) or text.
+	Format string
+	// RedactQueries hides user search queries and LLM prompts.
+	RedactQueries bool
+}
+
+// New returns a logger writing to w. Secrets are always redacted; user
+// queries only when cfg.RedactQueries is set. Records logged with a context
+// carry its request and trace IDs.
+func New(w io.Writer, cfg Config) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       ParseLevel(cfg.Level),
+		ReplaceAttr: redactAttr(cfg.RedactQueries),
+	}
+	var h slog.Handler
+	if strings.EqualFold(cfg.Format, "text") {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(contextHandler{h})
+}
+
+// Setup installs a logger writing to stdout as the slog and log default.
+// Its RedactQueries setting also applies to RedactURL.
+func Setup(cfg Config) *slog.Logger {
+	logger := New(os.Stdout, cfg)
+	slog.SetDefault(logger)
+	queriesRedacted.Store(cfg.RedactQueries)
+	return logger
+}
+
+// ParseLevel maps a level name to a slog.Level, defaulting to info.
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// Component returns a logger that tags records with component=name. It
+// writes through whatever slog.Default is at the time of the call, so it is
+// safe to create in package variables before Setup runs.
+func Component(name string) *slog.Logger {
+	return slog.New(defaultHandler{}).With("component", name)
+}
+
+// Err is the attribute used for errors.
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
+
+// contextHandler adds the request and trace IDs of the record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if id, ok := ctx.Value(contextkeys.RequestID).(string); ok && id != "" {
+			r.AddAttrs(slog.String("request_id", id))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// defaultHandler delegates to the current slog.Default handler, replaying
+// the attributes and groups added to it.
+type defaultHandler struct {
+	wrap func(slog.Handler) slog.Handler
+}
+
+func (h defaultHandler) target() slog.Handler {
+	base := slog.Default().Handler()
+	if h.wrap == nil {
+		return base
+	}
+	return h.wrap(base)
+}
+
+func (h defaultHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slog.Default().Handler().Enabled(ctx, level)
+}
+
+func (h defaultHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.target().Handle(ctx, r)
+}
+
+func (h defaultHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prev := h.wrap
+	return defaultHandler{wrap: func(base slog.Handler) slog.Handler {
+		if prev != nil {
+			base = prev(base)
+		}
+		return base.WithAttrs(attrs)
+	}}
+}
+
+func (h defaultHandler) WithGroup(name string) slog.Handler {
+	prev := h.wrap
+	return defaultHandler{wrap: func(base slog.Handler) slog.Handler {
+		if prev != nil {
+			base = prev(base)
+		}
+		return base.WithGroup(name)
+	}}
+}
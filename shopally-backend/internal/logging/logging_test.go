@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m), buf.String())
+	return m
+}
+
+func TestNew_RedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, Config{})
+
+	logger.Info("calling https://api.example.com/v1?key=abc123&q=phone",
+		"url", "https://api-sg.aliexpress.com/sync?app_key=1&sign=DEADBEEF&method=x",
+		"token", "tok",
+		Err(errors.New(`Post "https://host/x?key=abc123": timeout`)),
+		"params", map[string]string{"app_secret": "s3cr3t", "method": "search"},
+		"auth", "Bearer eyJhbGciOi.payload.sig",
+	)
+
+	m := decode(t, &buf)
+	assert.Equal(t, "calling https://api.example.com/v1?key="+Redacted+"&q=phone", m["msg"])
+	assert.Equal(t, "https://api-sg.aliexpress.com/sync?app_key="+Redacted+"&sign="+Redacted+"&method=x", m["url"])
+	assert.Equal(t, Redacted, m["token"])
+	assert.Equal(t, `Post "https://host/x?key=`+Redacted+`": timeout`, m["error"])
+	assert.Equal(t, map[string]interface{}{"app_secret": Redacted, "method": "search"}, m["params"])
+	assert.Equal(t, "Bearer "+Redacted, m["auth"])
+	assert.NotContains(t, buf.String(), "abc123")
+	assert.NotContains(t, buf.String(), "DEADBEEF")
+}
+
+func TestNew_RedactsQueriesOnlyWhenConfigured(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, Config{}).Info("parse intent", "query", "red shoes")
+	assert.Equal(t, "red shoes", decode(t, &buf)["query"])
+
+	buf.Reset()
+	New(&buf, Config{RedactQueries: true}).Info("parse intent", "query", "red shoes",
+		"filters", map[string]interface{}{"keywords": "shoes", "max_sale_price": 20.0})
+	m := decode(t, &buf)
+	assert.Equal(t, "[REDACTED len=9]", m["query"])
+	assert.Equal(t, map[string]interface{}{"keywords": "[REDACTED len=5]", "max_sale_price": 20.0}, m["filters"])
+
+	// Search text inside URLs, e.g. in client errors, is hidden too.
+	buf.Reset()
+	New(&buf, Config{RedactQueries: true}).Info("search failed",
+		Err(errors.New(`Get "https://api-sg.aliexpress.com/sync?keywords=red+shoes&method=x": timeout`)))
+	assert.Equal(t, `Get "https://api-sg.aliexpress.com/sync?keywords=`+Redacted+`&method=x": timeout`, decode(t, &buf)["error"])
+}
+
+func TestRedactURL_FollowsSetup(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(prev)
+		queriesRedacted.Store(false)
+	})
+	const u = "https://api-sg.aliexpress.com/sync?keywords=red+shoes&sign=DEADBEEF"
+
+	Setup(Config{})
+	assert.Equal(t, "https://api-sg.aliexpress.com/sync?keywords=red+shoes&sign="+Redacted, RedactURL(u))
+	Setup(Config{RedactQueries: true})
+	assert.Equal(t, "https://api-sg.aliexpress.com/sync?keywords="+Redacted+"&sign="+Redacted, RedactURL(u))
+}
+
+func TestNew_LevelAndRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, Config{Level: "warn"})
+
+	logger.Info("dropped")
+	assert.Zero(t, buf.Len())
+
+	ctx := context.WithValue(context.Background(), contextkeys.RequestID, "req-1")
+	logger.WarnContext(ctx, "kept")
+	m := decode(t, &buf)
+	assert.Equal(t, "WARN", m["level"])
+	assert.Equal(t, "req-1", m["request_id"])
+}
+
+func TestComponent_WritesThroughCurrentDefault(t *testing.T) {
+	log := Component("gemini").With("model", "flash")
+
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	var buf bytes.Buffer
+	slog.SetDefault(New(&buf, Config{Level: "debug"}))
+
+	log.Debug("hello", "api_key", "k")
+	m := decode(t, &buf)
+	assert.Equal(t, "gemini", m["component"])
+	assert.Equal(t, "flash", m["model"])
+	assert.Equal(t, Redacted, m["api_key"])
+}
@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Redacted replaces secret values in log output.
+const Redacted = "[REDACTED]"
+
+// secretKeys are attribute and map keys whose values are never logged.
+var secretKeys = map[string]bool{
+	"api_key":        true,
+	"apikey":         true,
+	"app_key":        true,
+	"app_secret":     true,
+	"secret":         true,
+	"client_secret":  true,
+	"encryption_key": true,
+	"sign":           true,
+	"signature":      true,
+	"session":        true,
+	"token":          true,
+	"access_token":   true,
+	"refresh_token":  true,
+	"password":       true,
+	"authorization":  true,
+}
+
+// queryKeys hold user-entered text, or LLM output echoing it, hidden when
+// queries are redacted.
+var queryKeys = map[string]bool{
+	"query":    true,
+	"prompt":   true,
+	"keywords": true,
+	"response": true,
+}
+
+var (
+	// secretParam matches secret query parameters inside URLs and error
+	// messages, e.g. Gemini's ?key= or AliExpress' &sign=.
+	secretParam = regexp.MustCompile(`(?i)([?&](?:key|api_?key|access_key|app_key|app_secret|client_secret|sign|signature|session|access_token|refresh_token|token|code|password)=)[^&\s"']+`)
+	bearerToken = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+	// queryParam matches URL parameters carrying user search text, e.g.
+	// AliExpress' &keywords=.
+	queryParam = regexp.MustCompile(`(?i)([?&](?:keywords|q|query|prompt)=)[^&\s"']+`)
+)
+
+// queriesRedacted is the RedactQueries setting of the installed logger.
+var queriesRedacted atomic.Bool
+
+// RedactString hides secret URL parameters and bearer tokens in s.
+func RedactString(s string) string {
+	if !strings.ContainsAny(s, "?&") && !strings.Contains(strings.ToLower(s), "bearer") {
+		return s
+	}
+	s = secretParam.ReplaceAllString(s, "${1}"+Redacted)
+	return bearerToken.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// RedactURL is RedactString for output outside the logger, such as span
+// attributes: it also hides search text in s when the logger installed by
+// Setup redacts queries.
+func RedactURL(s string) string {
+	return redact(s, queriesRedacted.Load())
+}
+
+// redact hides secrets in s, and search text in URL parameters when
+// redactQueries is set.
+func redact(s string, redactQueries bool) string {
+	s = RedactString(s)
+	if redactQueries && strings.ContainsAny(s, "?&") {
+		s = queryParam.ReplaceAllString(s, "${1}"+Redacted)
+	}
+	return s
+}
+
+// redactAttr is the slog ReplaceAttr hook applying the redaction rules.
+func redactAttr(redactQueries bool) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		key := strings.ToLower(a.Key)
+		if secretKeys[key] {
+			return slog.String(a.Key, Redacted)
+		}
+		if redactQueries && queryKeys[key] {
+			return slog.String(a.Key, hiddenQuery(a.Value.String()))
+		}
+
+		switch a.Value.Kind() {
+		case slog.KindString:
+			a.Value = slog.StringValue(redact(a.Value.String(), redactQueries))
+		case slog.KindAny:
+			switch v := a.Value.Any().(type) {
+			case error:
+				a.Value = slog.StringValue(redact(v.Error(), redactQueries))
+			case map[string]string:
+				a.Value = slog.AnyValue(redactMap(v, redactQueries))
+			case map[string]interface{}:
+				a.Value = slog.AnyValue(redactMap(v, redactQueries))
+			case fmt.Stringer:
+				a.Value = slog.StringValue(redact(v.String(), redactQueries))
+			}
+		}
+		return a
+	}
+}
+
+func redactMap[V any](m map[string]V, redactQueries bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		key := strings.ToLower(k)
+		switch {
+		case secretKeys[key]:
+			out[k] = Redacted
+		case redactQueries && queryKeys[key]:
+			out[k] = hiddenQuery(fmt.Sprint(v))
+		default:
+			if s, ok := any(v).(string); ok {
+				out[k] = redact(s, redactQueries)
+			} else {
+				out[k] = v
+			}
+		}
+	}
+	return out
+}
+
+func hiddenQuery(s string) string {
+	return fmt.Sprintf("[REDACTED len=%d]", len(s))
+}
@@ -0,0 +1,172 @@
+// Package metrics defines the Prometheus metrics of the API and worker and
+// serves them on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shopally-ai/internal/logging"
+)
+
+var log = logging.Component("metrics")
+
+const namespace = "shopally"
+
+// Outcomes of an upstream call.
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+// Results of a cache lookup.
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+// Registry holds every metric of the process, plus the Go runtime and
+// process collectors.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts handled requests by route pattern and status.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests handled, by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration observes request latency by route pattern and status.
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// UpstreamRequests counts calls to Gemini, AliExpress, the FX provider
+	// and FCM by operation and outcome.
+	UpstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "requests_total",
+		Help:      "Calls to upstream services, by upstream, operation and outcome.",
+	}, []string{"upstream", "operation", "outcome"})
+
+	// UpstreamDuration observes upstream call latency. LLM calls take
+	// seconds, so the buckets reach further than the HTTP ones.
+	UpstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "request_duration_seconds",
+		Help:      "Upstream call latency, by upstream and operation.",
+		Buckets:   []float64{.025, .05, .1, .25, .5, 1, 2, 4, 8, 15},
+	}, []string{"upstream", "operation"})
+
+	// CacheRequests counts cache lookups by cache and result.
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Cache lookups, by cache and result (hit, miss, error).",
+	}, []string{"cache", "result"})
+
+	// RateLimitRejections counts requests the rate limiter turned away, by
+	// route and reason (limited, unavailable).
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rate_limit",
+		Name:      "rejections_total",
+		Help:      "Requests rejected by the rate limiter, by route and reason.",
+	}, []string{"route", "reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		UpstreamRequests,
+		UpstreamDuration,
+		CacheRequests,
+		RateLimitRejections,
+	)
+}
+
+// Handler serves the metrics in Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Serve serves /metrics on addr in the background, on a listener of its own
+// so it is not reachable through a public port. An empty addr serves
+// nothing.
+func Serve(addr string) {
+	if addr == "" {
+		log.Info("no metrics address is set; /metrics is disabled")
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	go func() {
+		log.Info("serving metrics", "addr", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Error("metrics server stopped", logging.Err(err))
+		}
+	}()
+}
+
+// knownMethods are recorded as is; clients choose the method, so any other
+// is recorded as "other" to bound the number of series.
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodConnect: true,
+	http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// ObserveHTTP records a handled request. An empty route (no match) is
+// recorded as "unmatched" and unknown methods as "other" to keep
+// client-chosen values out of the labels.
+func ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	if !knownMethods[method] {
+		method = "other"
+	}
+	code := strconv.Itoa(status)
+	HTTPRequests.WithLabelValues(method, route, code).Inc()
+	HTTPDuration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+// ObserveUpstream records a call to upstream that started at start and
+// failed when err is non-nil.
+func ObserveUpstream(upstream, operation string, start time.Time, err error) {
+	outcome := OutcomeOK
+	if err != nil {
+		outcome = OutcomeError
+	}
+	UpstreamRequests.WithLabelValues(upstream, operation, outcome).Inc()
+	UpstreamDuration.WithLabelValues(upstream, operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveCache records a cache lookup.
+func ObserveCache(cache string, hit bool, err error) {
+	result := CacheMiss
+	switch {
+	case err != nil:
+		result = CacheError
+	case hit:
+		result = CacheHit
+	}
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterOff})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+
+	shutdown, err = Setup(context.Background(), Config{Exporter: ExporterStdout, ServiceName: "test"})
+	require.NoError(t, err)
+	_, span := Tracer("test").Start(context.Background(), "span")
+	assert.True(t, span.SpanContext().IsValid())
+	span.End()
+	assert.NoError(t, shutdown(context.Background()))
+}
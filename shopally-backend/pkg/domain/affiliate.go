@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// AffiliateAccount is an AliExpress account a user linked through OAuth.
+// Affiliate calls made for the user carry its session and tracking ID
+// instead of only the app-wide credentials.
+type AffiliateAccount struct {
+	UserID           string    `json:"-"`
+	AccountID        string    `json:"accountId"`
+	Account          string    `json:"account"`
+	TrackingID       string    `json:"trackingId,omitempty"`
+	AccessToken      string    `json:"-"`
+	RefreshToken     string    `json:"-"`
+	AccessExpiresAt  time.Time `json:"accessExpiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+	LinkedAt         time.Time `json:"linkedAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	// NeedsRelink is set once AliExpress refuses the refresh token; the
+	// account is not refreshed again until it is linked anew.
+	NeedsRelink bool `json:"needsRelink"`
+	// RefreshRetryAt holds off refreshing after a failed attempt.
+	RefreshRetryAt time.Time `json:"-"`
+}
+
+// AffiliateToken is a token grant returned by the AliExpress OAuth API.
+type AffiliateToken struct {
+	AccountID        string
+	Account          string
+	AccessToken      string
+	RefreshToken     string
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+}
+
+// AffiliateCredentials are the per-request credentials the affiliate
+// gateway uses for a signed-in user with a linked account.
+type AffiliateCredentials struct {
+	Session    string
+	TrackingID string
+}
@@ -1,9 +1,21 @@
 package domain
 
 type Alert struct {
-	ID           string  `json:"alertId"`
-	DeviceID     string  `json:"deviceId"`
+	ID       string `json:"alertId"`
+	DeviceID string `json:"deviceId"`
+	// UserID is set once the alert belongs to a signed-in user; DeviceID
+	// stays the device that receives the push.
+	UserID       string  `json:"userId,omitempty"`
 	ProductID    string  `json:"productId"`
 	CurrentPrice float64 `json:"currentPrice"`
 	IsActive     bool    `json:"isActive"`
 }
+
+// ClaimResult reports what a device claim moved into a user account.
+// Merged items were duplicates of something the user already had.
+type ClaimResult struct {
+	AlertsClaimed     int `json:"alertsClaimed"`
+	AlertsMerged      int `json:"alertsMerged"`
+	SavedItemsClaimed int `json:"savedItemsClaimed"`
+	SavedItemsMerged  int `json:"savedItemsMerged"`
+}
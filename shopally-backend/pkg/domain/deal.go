@@ -0,0 +1,33 @@
+package domain
+
+// Deal verdicts.
+const (
+	DealGreat    = "great"
+	DealFair     = "fair"
+	DealInflated = "inflated"
+)
+
+// Deal reason codes, stable for client-side localization.
+const (
+	ReasonSaleAboveOriginal   = "SALE_ABOVE_ORIGINAL"
+	ReasonDiscountMismatch    = "DISCOUNT_MISMATCH"
+	ReasonOriginalNeverSeen   = "ORIGINAL_NEVER_SEEN"
+	ReasonPriceSpike          = "PRICE_SPIKE"
+	ReasonLowestPrice         = "LOWEST_PRICE"
+	ReasonBelowAverage        = "BELOW_AVERAGE"
+	ReasonInsufficientHistory = "INSUFFICIENT_HISTORY"
+)
+
+// DealReason explains one input to a deal verdict.
+type DealReason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Deal is the verdict on whether a product's advertised price is a real bargain.
+type Deal struct {
+	Verdict string       `json:"verdict"`
+	Reasons []DealReason `json:"reasons"`
+	// HistoryCount is the number of stored observations the verdict used.
+	HistoryCount int `json:"historyCount"`
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrorKind classifies an error so adapters can report it consistently,
+// e.g. as an HTTP status.
+type ErrorKind string
+
+const (
+	KindInternal            ErrorKind = "internal"
+	KindValidation          ErrorKind = "validation"
+	KindNotFound            ErrorKind = "not_found"
+	KindConflict            ErrorKind = "conflict"
+	KindUnauthorized        ErrorKind = "unauthorized"
+	KindForbidden           ErrorKind = "forbidden"
+	KindUpstreamUnavailable ErrorKind = "upstream_unavailable"
+	KindRateLimited         ErrorKind = "rate_limited"
+)
+
+// Error is an error of a known kind. Code is a stable machine-readable code
+// for clients; empty uses the default code of the kind. Message and Detail
+// are safe to show to callers; the wrapped Err may not be.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	// Detail narrows Message down, e.g. to the field that is invalid.
+	Detail string
+	// RetryAfter tells callers when to retry a rate-limited or unavailable
+	// request, when known.
+	RetryAfter time.Duration
+	Err        error
+
+	// base is the error WithDetail was called on.
+	base *Error
+}
+
+func (e *Error) Error() string {
+	msg := e.PublicMessage()
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is matches the errors e was derived from with WithDetail.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	for b := e.base; b != nil; b = b.base {
+		if b == t {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicMessage is the message shown to callers: Message and Detail.
+func (e *Error) PublicMessage() string {
+	if e.Detail != "" {
+		return e.Message + ": " + e.Detail
+	}
+	return e.Message
+}
+
+// WithCode returns e with a specific client code.
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+// WithDetail returns a copy of e with detail shown to callers. The copy
+// still matches e with errors.Is, so sentinel errors can carry specifics.
+func (e *Error) WithDetail(detail string) *Error {
+	d := *e
+	d.Detail = detail
+	d.base = e
+	return &d
+}
+
+// Validation reports invalid caller input.
+func Validation(message string) *Error {
+	return &Error{Kind: KindValidation, Message: message}
+}
+
+// NotFound reports a missing resource.
+func NotFound(message string) *Error {
+	return &Error{Kind: KindNotFound, Message: message}
+}
+
+// Conflict reports a request that clashes with existing state.
+func Conflict(message string) *Error {
+	return &Error{Kind: KindConflict, Message: message}
+}
+
+// Unauthorized reports missing or rejected credentials.
+func Unauthorized(message string) *Error {
+	return &Error{Kind: KindUnauthorized, Message: message}
+}
+
+// Forbidden reports a caller who may not perform the request.
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+// UpstreamUnavailable reports a failed call to a service we depend on;
+// cause is kept for logs and errors.Is but not shown to callers.
+func UpstreamUnavailable(message string, cause error) *Error {
+	return &Error{Kind: KindUpstreamUnavailable, Message: message, Err: cause}
+}
+
+// RateLimited reports a caller over their request budget.
+func RateLimited(message string, retryAfter time.Duration) *Error {
+	return &Error{Kind: KindRateLimited, Message: message, RetryAfter: retryAfter}
+}
+
+// AsError returns the first *Error in err's chain.
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of err, KindInternal for errors of no known kind.
+func KindOf(err error) ErrorKind {
+	if e, ok := AsError(err); ok {
+		return e.Kind
+	}
+	return KindInternal
+}
+
+var (
+	// ErrProductNotFound is returned when a product ID does not resolve upstream.
+	ErrProductNotFound = NotFound("product not found")
+	// ErrUnsupportedLink is returned when a link does not point at a marketplace product.
+	ErrUnsupportedLink = Validation("link is not an AliExpress product link")
+	// ErrComparisonNotFound is returned for an unknown or expired comparison ID.
+	ErrComparisonNotFound = NotFound("comparison not found")
+	// ErrUnsupportedProvider is returned for a product reference at an unknown marketplace.
+	ErrUnsupportedProvider = Validation("unsupported product provider")
+	// ErrUserNotFound is returned when no account matches the lookup.
+	ErrUserNotFound = NotFound("user not found")
+	// ErrEmailTaken is returned when registering an email that already has an account.
+	ErrEmailTaken = Conflict("email is already registered")
+	// ErrSavedItemNotFound is returned when the caller has no such saved item.
+	ErrSavedItemNotFound = NotFound("saved item not found")
+	// ErrAlreadySaved is returned when saving a product the owner already saved.
+	ErrAlreadySaved = Conflict("product is already saved")
+	// ErrOAuthCodeRejected is returned when a provider refuses an authorization code.
+	ErrOAuthCodeRejected = Unauthorized("authorization code was rejected").WithCode("OAUTH_FAILED")
+	// ErrAlertNotFound is returned when no alert has the given ID.
+	ErrAlertNotFound = NotFound("alert not found")
+)
@@ -8,6 +8,15 @@ import (
 // AlibabaGateway defines the contract for fetching products from an external source.
 type AlibabaGateway interface {
 	FetchProducts(ctx context.Context, query string, filters map[string]interface{}) ([]*Product, error)
+	// FetchProductDetail returns a single product by its marketplace ID, or
+	// (nil, nil) if the product does not exist.
+	FetchProductDetail(ctx context.Context, productID string) (*Product, error)
+}
+
+// LinkResolver turns a pasted marketplace link into a product ID, following
+// short-link redirects where needed.
+type LinkResolver interface {
+	ResolveProductID(ctx context.Context, rawURL string) (string, error)
 }
 
 // LLMGateway defines the contract for a Large Language Model service
@@ -16,7 +25,8 @@ type LLMGateway interface {
 	ParseIntent(ctx context.Context, query string) (map[string]interface{}, error)
 	// SummarizeProduct generates short bullet points for a product based on provided fields.
 	SummarizeProduct(context.Context, *Product, string) (*Product, error)
-	CompareProducts(ctx context.Context, productDetails []*Product) (map[string]interface{}, error)
+	// CompareProducts returns a synthesis per product; the result is not yet validated.
+	CompareProducts(ctx context.Context, productDetails []*Product) (*ComparisonResult, error)
 }
 
 // CacheGateway defines the contract for a caching service.
@@ -36,12 +46,119 @@ type ICachePort interface {
 	Set(ctx context.Context, key, val string, ttl time.Duration) error
 }
 
+// OneTimeStore holds short-lived values that may be read only once, such as
+// sign-in hand-off codes.
+type OneTimeStore interface {
+	// Set stores the value with a TTL.
+	Set(ctx context.Context, key, val string, ttl time.Duration) error
+	// Take returns the value and deletes it in one step; found is false
+	// when the key is missing or expired.
+	Take(ctx context.Context, key string) (string, bool, error)
+}
+
 type AlertRepository interface {
 	CreateAlert(alert *Alert) error
 	GetAlert(alertID string) (*Alert, error)
 	DeleteAlert(alertID string) error
 }
 
+// AlertOwnerRepository moves alerts from devices to user accounts.
+type AlertOwnerRepository interface {
+	// ListUnclaimed returns the active alerts of deviceID without a user.
+	ListUnclaimed(ctx context.Context, deviceID string) ([]Alert, error)
+	// ListByUser returns the active alerts of userID.
+	ListByUser(ctx context.Context, userID string) ([]Alert, error)
+	AssignUser(ctx context.Context, alertID, userID string) error
+}
+
+// PriceHistoryRepository persists price observations per product.
+type PriceHistoryRepository interface {
+	// Record stores a new observation.
+	Record(ctx context.Context, obs *PriceObservation) error
+	// Latest returns the most recent observation for a product, or nil if none.
+	Latest(ctx context.Context, productID string) (*PriceObservation, error)
+	// List returns observations observed at or after since, oldest first.
+	List(ctx context.Context, productID string, since time.Time) ([]PriceObservation, error)
+}
+
 type IPushNotificationGateway interface {
 	Send(ctx context.Context, token, title, body string, data map[string]string) (string, error)
 }
+
+// UserRepository persists user accounts.
+type UserRepository interface {
+	// Create stores a new user and returns ErrEmailTaken for a duplicate email.
+	Create(ctx context.Context, user *User) error
+	// GetByID and GetByEmail return ErrUserNotFound when no user matches.
+	GetByID(ctx context.Context, id string) (*User, error)
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	// GetByIdentity returns the user linked to the provider account, or
+	// ErrUserNotFound.
+	GetByIdentity(ctx context.Context, provider, subject string) (*User, error)
+	// AddIdentity links an external account to an existing user.
+	AddIdentity(ctx context.Context, userID string, identity Identity) error
+}
+
+// OAuthProvider runs the authorization-code flow of an external identity
+// provider. verifier is the PKCE code verifier generated for the flow.
+type OAuthProvider interface {
+	// AuthCodeURL returns the consent page URL the user is redirected to.
+	AuthCodeURL(state, verifier string) string
+	// Exchange redeems the authorization code and returns the signed-in account.
+	Exchange(ctx context.Context, code, verifier string) (*OAuthIdentity, error)
+}
+
+// AffiliateAccountRepository persists linked AliExpress accounts, one per user.
+type AffiliateAccountRepository interface {
+	// Upsert stores the account of account.UserID, replacing any previous one.
+	Upsert(ctx context.Context, account *AffiliateAccount) error
+	// GetByUser returns the linked account, or (nil, nil) if there is none.
+	GetByUser(ctx context.Context, userID string) (*AffiliateAccount, error)
+	Delete(ctx context.Context, userID string) error
+}
+
+// AliExpressAuthGateway runs the AliExpress Open Platform OAuth flow.
+type AliExpressAuthGateway interface {
+	// AuthCodeURL returns the AliExpress consent page URL.
+	AuthCodeURL(state string) string
+	// ExchangeCode and Refresh return ErrOAuthCodeRejected when AliExpress
+	// refuses the code or refresh token.
+	ExchangeCode(ctx context.Context, code string) (*AffiliateToken, error)
+	Refresh(ctx context.Context, refreshToken string) (*AffiliateToken, error)
+}
+
+// SavedItemRepository persists saved items. owner is an Owner.Key().
+type SavedItemRepository interface {
+	// Create returns ErrAlreadySaved when the owner already saved the product.
+	Create(ctx context.Context, item *SavedItem) error
+	// Get, GetByProduct, Update and Delete return ErrSavedItemNotFound when
+	// the owner has no such item.
+	Get(ctx context.Context, owner, id string) (*SavedItem, error)
+	GetByProduct(ctx context.Context, owner, productID string) (*SavedItem, error)
+	List(ctx context.Context, owner string, q SavedItemQuery) ([]SavedItem, int, error)
+	ListAll(ctx context.Context, owner string) ([]SavedItem, error)
+	Lists(ctx context.Context, owner string) ([]SavedList, error)
+	Update(ctx context.Context, item *SavedItem) error
+	Delete(ctx context.Context, owner, id string) error
+	// Reassign moves an item to another owner and returns ErrAlreadySaved
+	// when that owner already saved the product.
+	Reassign(ctx context.Context, id, from, to string) error
+}
+
+// PreferencesRepository persists preferences. owner is an Owner.Key().
+type PreferencesRepository interface {
+	// Get returns the preferences, or (nil, nil) if none are stored.
+	Get(ctx context.Context, owner string) (*Preferences, error)
+	Upsert(ctx context.Context, p *Preferences) error
+}
+
+// SessionRepository persists refresh-token sessions.
+type SessionRepository interface {
+	Create(ctx context.Context, s *RefreshSession) error
+	// Get returns the session, or (nil, nil) if it does not exist.
+	Get(ctx context.Context, id string) (*RefreshSession, error)
+	// Revoke reports whether this call revoked the session; false means it
+	// was already revoked or does not exist.
+	Revoke(ctx context.Context, id string) (bool, error)
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,76 @@
+package domain
+
+// Landed cost line codes, in the order they are applied.
+const (
+	CostItem        = "ITEM"
+	CostSellerTax   = "SELLER_TAX"
+	CostShipping    = "SHIPPING"
+	CostDuty        = "CUSTOMS_DUTY"
+	CostExcise      = "EXCISE"
+	CostVAT         = "VAT"
+	CostSurtax      = "SURTAX"
+	CostWithholding = "WITHHOLDING"
+)
+
+// TariffTable holds the data used to estimate landed cost in Ethiopia.
+// Categories are matched by keyword against a product's category names.
+type TariffTable struct {
+	// VATRate, SurtaxRate and WithholdingRate apply to every import.
+	VATRate         float64 `json:"vatRate"`
+	SurtaxRate      float64 `json:"surtaxRate"`
+	WithholdingRate float64 `json:"withholdingRate"`
+	// Default is used when no category matches.
+	Default    TariffCategory   `json:"default"`
+	Categories []TariffCategory `json:"categories"`
+}
+
+// TariffCategory holds the customs rates and shipping estimate for a group of
+// product categories.
+type TariffCategory struct {
+	Key         string   `json:"key"`
+	Keywords    []string `json:"keywords"`
+	DutyRate    float64  `json:"dutyRate"`
+	ExciseRate  float64  `json:"exciseRate"`
+	ShippingUSD float64  `json:"shippingUsd"`
+}
+
+// LandedCostLine is one itemized component of the landed cost.
+type LandedCostLine struct {
+	Code string  `json:"code"`
+	Rate float64 `json:"rate,omitempty"`
+	USD  float64 `json:"usd"`
+	ETB  float64 `json:"etb"`
+}
+
+// LandedCost is the estimated total a buyer in Ethiopia pays for a product,
+// including shipping and import taxes. ETB amounts are zero when no FX rate
+// was available.
+type LandedCost struct {
+	TariffCategory string           `json:"tariffCategory"`
+	Lines          []LandedCostLine `json:"lines"`
+	TotalUSD       float64          `json:"totalUsd"`
+	TotalETB       float64          `json:"totalEtb"`
+	FXRate         float64          `json:"fxRate,omitempty"`
+}
+
+// CartItem is a product and quantity in a cart quote request.
+type CartItem struct {
+	Product  ProductRef `json:"product"`
+	Quantity int        `json:"quantity"`
+}
+
+// CartQuoteItem is a priced cart line; LandedCost is per unit.
+type CartQuoteItem struct {
+	Product  Product `json:"product"`
+	Quantity int     `json:"quantity"`
+	TotalUSD float64 `json:"totalUsd"`
+	TotalETB float64 `json:"totalEtb"`
+}
+
+// CartQuote is the itemized landed cost of a whole cart.
+type CartQuote struct {
+	Items    []CartQuoteItem  `json:"items"`
+	Lines    []LandedCostLine `json:"lines"`
+	TotalUSD float64          `json:"totalUsd"`
+	TotalETB float64          `json:"totalEtb"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// DefaultShipToCountry is the delivery country used when a caller has not
+// chosen one.
+const DefaultShipToCountry = "ET"
+
+// Notification channels a caller can opt into.
+const (
+	NotificationChannelPush  = "push"
+	NotificationChannelEmail = "email"
+)
+
+// Budget is a default price band applied to searches that do not name one.
+// Either bound may be omitted.
+type Budget struct {
+	MinUSD *float64 `json:"minUsd,omitempty" bson:"minUsd,omitempty"`
+	MaxUSD *float64 `json:"maxUsd,omitempty" bson:"maxUsd,omitempty"`
+}
+
+// Preferences are a user's or device's stored settings. An empty Language or
+// Currency means "follow the request" (Accept-Language).
+type Preferences struct {
+	Owner                string    `json:"-" bson:"_id"`
+	Language             string    `json:"language" bson:"language,omitempty"`
+	Currency             string    `json:"currency" bson:"currency,omitempty"`
+	ShipToCountry        string    `json:"shipToCountry" bson:"shipToCountry,omitempty"`
+	NotificationChannels []string  `json:"notificationChannels" bson:"notificationChannels"`
+	Budget               *Budget   `json:"budget,omitempty" bson:"budget,omitempty"`
+	UpdatedAt            time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// ShipTo returns the delivery country, DefaultShipToCountry when unset. It
+// is safe to call on a nil receiver.
+func (p *Preferences) ShipTo() string {
+	if p == nil || p.ShipToCountry == "" {
+		return DefaultShipToCountry
+	}
+	return p.ShipToCountry
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Price observation sources.
+const (
+	PriceSourceSearch = "search"
+	PriceSourceDetail = "detail"
+	PriceSourceAlert  = "alert"
+)
+
+// PriceObservation is a single price seen for a product at a point in time.
+type PriceObservation struct {
+	ProductID   string    `json:"productId" bson:"productId"`
+	USD         float64   `json:"usd" bson:"usd"`
+	ETB         float64   `json:"etb" bson:"etb"`
+	Discount    float64   `json:"discount" bson:"discount"`
+	FXTimestamp time.Time `json:"fxTimestamp" bson:"fxTimestamp"`
+	Source      string    `json:"source" bson:"source"`
+	ObservedAt  time.Time `json:"observedAt" bson:"observedAt"`
+}
+
+// PriceStats summarizes observed prices in one currency.
+type PriceStats struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+// PriceWindowStats summarizes a product's prices over a trailing window.
+type PriceWindowStats struct {
+	Window string     `json:"window"`
+	From   time.Time  `json:"from"`
+	Count  int        `json:"count"`
+	USD    PriceStats `json:"usd"`
+	ETB    PriceStats `json:"etb"`
+}
+
+// PriceHistory is the chart payload for a product.
+type PriceHistory struct {
+	ProductID string             `json:"productId"`
+	Points    []PriceObservation `json:"points"`
+	Stats     []PriceWindowStats `json:"stats"`
+	Latest    *PriceObservation  `json:"latest"`
+}
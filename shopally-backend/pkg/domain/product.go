@@ -7,26 +7,38 @@ type Price struct {
 	ETB         float64   `json:"etb"`
 	USD         float64   `json:"usd"`
 	FXTimestamp time.Time `json:"fxTimestamp"`
+	// OriginalUSD is the pre-discount list price claimed by the seller.
+	OriginalUSD float64 `json:"originalUsd,omitempty"`
+}
+
+// Category identifies a node in the upstream marketplace category tree.
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 // Product represents a product found on an e-commerce platform.
 type Product struct {
-	ID                 string   `json:"id"`
-	Title              string   `json:"title"`
-	ImageURL           string   `json:"imageUrl"`
-	AIMatchPercentage  int      `json:"aiMatchPercentage"`
-	Price              Price    `json:"price"`
-	ProductRating      float64  `json:"productRating"`
-	SellerScore        int      `json:"sellerScore"`
-	DeliveryEstimate   string   `json:"deliveryEstimate"`
-	Description        string   `json:"description"`
-	CustomerHighlights string   `json:"customerHighlights"`
-	CustomerReview     string   `json:"customerReview"`
-	NumberSold         int      `json:"numberSold"`
-	SummaryBullets     []string `json:"summaryBullets"`
-	DeeplinkURL        string   `json:"deeplinkUrl"`
-	TaxRate            float64  `json:"taxRate"`
-	Discount           float64  `json:"discount"`
+	ID                 string      `json:"id"`
+	Title              string      `json:"title"`
+	ImageURL           string      `json:"imageUrl"`
+	AIMatchPercentage  int         `json:"aiMatchPercentage"`
+	Price              Price       `json:"price"`
+	ProductRating      float64     `json:"productRating"`
+	SellerScore        int         `json:"sellerScore"`
+	DeliveryEstimate   string      `json:"deliveryEstimate"`
+	Description        string      `json:"description"`
+	CustomerHighlights string      `json:"customerHighlights"`
+	CustomerReview     string      `json:"customerReview"`
+	NumberSold         int         `json:"numberSold"`
+	SummaryBullets     []string    `json:"summaryBullets"`
+	DeeplinkURL        string      `json:"deeplinkUrl"`
+	TaxRate            float64     `json:"taxRate"`
+	Discount           float64     `json:"discount"`
+	Category           Category    `json:"category"`
+	SubCategory        Category    `json:"subCategory"`
+	Deal               *Deal       `json:"deal,omitempty"`
+	LandedCost         *LandedCost `json:"landedCost,omitempty"`
 }
 
 // Synthesis captures comparison insights for a product.
@@ -43,7 +55,30 @@ type ProductComparison struct {
 	Synthesis Synthesis `json:"synthesis"`
 }
 
+// Comparison modes select how a comparison is produced.
+const (
+	// CompareModeAuto uses the LLM and falls back to rules when it fails.
+	CompareModeAuto = "auto"
+	// CompareModeLLM uses the LLM only.
+	CompareModeLLM = "llm"
+	// CompareModeRules uses the deterministic rule-based comparator only.
+	CompareModeRules = "rules"
+)
+
+// Comparison sources report which comparator produced a result.
+const (
+	ComparisonSourceLLM   = "llm"
+	ComparisonSourceRules = "rules"
+)
+
 // ComparisonResult holds multiple product comparisons (for side-by-side results).
 type ComparisonResult struct {
-	Products []ProductComparison `json:"products"`
+	// ID identifies a stored comparison that can be shared until ExpiresAt.
+	ID        string              `json:"id,omitempty"`
+	ExpiresAt *time.Time          `json:"expiresAt,omitempty"`
+	Products  []ProductComparison `json:"products"`
+	// Source is the comparator that produced the result (llm or rules).
+	Source string `json:"source,omitempty"`
+	// Specs aligns normalized specifications across the compared products.
+	Specs *SpecMatrix `json:"specs,omitempty"`
 }
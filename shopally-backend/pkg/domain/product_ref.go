@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ProviderAliExpress is the only marketplace currently supported.
+const ProviderAliExpress = "aliexpress"
+
+// ProductRef identifies a product at a marketplace provider. An empty
+// provider means ProviderAliExpress.
+type ProductRef struct {
+	ID       string `json:"id"`
+	Provider string `json:"provider,omitempty"`
+}
+
+// UnmarshalJSON accepts either a bare ID string or an {"id","provider"} object.
+func (r *ProductRef) UnmarshalJSON(b []byte) error {
+	var id string
+	if err := json.Unmarshal(b, &id); err == nil {
+		*r = ProductRef{ID: id}
+		return nil
+	}
+	type plain ProductRef
+	var p plain
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*r = ProductRef(p)
+	return nil
+}
+
+// NormalizedProvider returns the lower-cased provider, defaulting to AliExpress.
+func (r ProductRef) NormalizedProvider() string {
+	p := strings.ToLower(strings.TrimSpace(r.Provider))
+	if p == "" {
+		return ProviderAliExpress
+	}
+	return p
+}
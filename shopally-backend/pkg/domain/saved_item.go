@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// DefaultSavedList is the list items are saved to unless another is named.
+const DefaultSavedList = "default"
+
+// Saved-item sort orders. Price sorts use the saved price.
+const (
+	SavedSortNewest    = "newest"
+	SavedSortOldest    = "oldest"
+	SavedSortPriceAsc  = "price_asc"
+	SavedSortPriceDesc = "price_desc"
+	SavedSortTitle     = "title"
+)
+
+// Owner identifies whose data a record is: the signed-in user, or the
+// device for anonymous callers.
+type Owner struct {
+	UserID   string
+	DeviceID string
+}
+
+// Key is the stored owner value, "user:<id>" or "device:<id>". It is empty
+// when neither is known.
+func (o Owner) Key() string {
+	switch {
+	case o.UserID != "":
+		return "user:" + o.UserID
+	case o.DeviceID != "":
+		return "device:" + o.DeviceID
+	}
+	return ""
+}
+
+// SavedSnapshot is the product as it was when saved.
+type SavedSnapshot struct {
+	Title         string  `json:"title" bson:"title"`
+	ImageURL      string  `json:"imageUrl" bson:"imageUrl"`
+	DeeplinkURL   string  `json:"deeplinkUrl" bson:"deeplinkUrl"`
+	Price         Price   `json:"price" bson:"price"`
+	ProductRating float64 `json:"productRating" bson:"productRating"`
+	Discount      float64 `json:"discount" bson:"discount"`
+}
+
+// SavedItem is a product on a user's or device's saved-items page.
+type SavedItem struct {
+	ID        string        `json:"id" bson:"_id"`
+	Owner     string        `json:"-" bson:"owner"`
+	ProductID string        `json:"productId" bson:"productId"`
+	List      string        `json:"list" bson:"list"`
+	Snapshot  SavedSnapshot `json:"snapshot" bson:"snapshot"`
+	AlertID   string        `json:"alertId,omitempty" bson:"alertId,omitempty"`
+	SavedAt   time.Time     `json:"savedAt" bson:"savedAt"`
+	UpdatedAt time.Time     `json:"updatedAt" bson:"updatedAt"`
+
+	// CurrentPrice is looked up on read and is nil when unavailable.
+	CurrentPrice *Price `json:"currentPrice,omitempty" bson:"-"`
+	// PriceChangeUSD is the current minus the saved USD price.
+	PriceChangeUSD *float64 `json:"priceChangeUsd,omitempty" bson:"-"`
+}
+
+// SavedItemQuery selects a page of saved items. An empty List matches all lists.
+type SavedItemQuery struct {
+	List     string
+	Sort     string
+	Page     int
+	PageSize int
+}
+
+// SavedItemPage is one page of saved items.
+type SavedItemPage struct {
+	Items    []SavedItem `json:"items"`
+	Total    int         `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+}
+
+// SavedList is a named list and how many items it holds.
+type SavedList struct {
+	Name  string `json:"name" bson:"_id"`
+	Count int    `json:"count" bson:"count"`
+}
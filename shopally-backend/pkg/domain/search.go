@@ -0,0 +1,45 @@
+package domain
+
+// Relaxation actions reported back to the client.
+const (
+	RelaxDropped    = "dropped"
+	RelaxWidened    = "widened"
+	RelaxSimplified = "simplified"
+)
+
+// RelaxedConstraint describes a search filter that was dropped or widened
+// to recover from an empty result set.
+type RelaxedConstraint struct {
+	Filter string      `json:"filter"`
+	Action string      `json:"action"`
+	From   interface{} `json:"from"`
+	To     interface{} `json:"to,omitempty"`
+}
+
+// SearchFacets summarizes a page of search results for rendering filters.
+type SearchFacets struct {
+	Categories     []FacetCount   `json:"categories"`
+	PriceHistogram PriceHistogram `json:"priceHistogram"`
+	DeliveryTime   []FacetCount   `json:"deliveryTime"`
+	Rating         []FacetCount   `json:"rating"`
+}
+
+// FacetCount is the number of products that fall into a facet value.
+type FacetCount struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// PriceHistogram buckets product prices in the display currency.
+type PriceHistogram struct {
+	Currency string        `json:"currency"`
+	Buckets  []PriceBucket `json:"buckets"`
+}
+
+// PriceBucket counts products priced within [Min, Max].
+type PriceBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
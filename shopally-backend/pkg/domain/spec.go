@@ -0,0 +1,35 @@
+package domain
+
+// Normalized specification keys, in matrix order.
+const (
+	SpecStorage    = "storage"
+	SpecRAM        = "ram"
+	SpecScreenSize = "screenSize"
+	SpecBattery    = "battery"
+	SpecMaterial   = "material"
+	SpecSize       = "size"
+)
+
+// SpecValue is one product's value for a specification. Numeric specs set
+// Value in the row's canonical unit; textual specs set Text. Missing is true
+// when the product does not state the spec.
+type SpecValue struct {
+	Value   float64 `json:"value,omitempty"`
+	Text    string  `json:"text,omitempty"`
+	Display string  `json:"display,omitempty"`
+	Missing bool    `json:"missing"`
+}
+
+// SpecRow holds one specification across all compared products. Values is
+// aligned with SpecMatrix.ProductIDs.
+type SpecRow struct {
+	Key    string      `json:"key"`
+	Unit   string      `json:"unit,omitempty"`
+	Values []SpecValue `json:"values"`
+}
+
+// SpecMatrix is a normalized specification table for a comparison.
+type SpecMatrix struct {
+	ProductIDs []string  `json:"productIds"`
+	Rows       []SpecRow `json:"rows"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// User is a registered account. PasswordHash is empty for accounts that only
+// sign in through an external provider.
+type User struct {
+	ID           string `json:"id" bson:"_id"`
+	Email        string `json:"email" bson:"email"`
+	Name         string `json:"name" bson:"name"`
+	PasswordHash string `json:"-" bson:"passwordHash,omitempty"`
+	// Identities are the external accounts linked to this user.
+	Identities []Identity `json:"identities,omitempty" bson:"identities,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Identity links an external provider account to a user. Subject is the
+// provider's stable account ID and is never exposed to clients.
+type Identity struct {
+	Provider string    `json:"provider" bson:"provider"`
+	Subject  string    `json:"-" bson:"subject"`
+	Email    string    `json:"email,omitempty" bson:"email,omitempty"`
+	LinkedAt time.Time `json:"linkedAt" bson:"linkedAt"`
+}
+
+// RefreshSession backs a refresh token. Refresh tokens are rotated on use,
+// so each session is redeemed at most once.
+type RefreshSession struct {
+	ID        string     `bson:"_id"`
+	UserID    string     `bson:"userId"`
+	CreatedAt time.Time  `bson:"createdAt"`
+	ExpiresAt time.Time  `bson:"expiresAt"`
+	RevokedAt *time.Time `bson:"revokedAt,omitempty"`
+}
+
+// AuthTokens is the token pair returned on login, registration and refresh.
+type AuthTokens struct {
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+	TokenType        string    `json:"tokenType"`
+	AccessExpiresAt  time.Time `json:"accessExpiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+// OAuthIdentity is the account an OAuth provider signed in.
+type OAuthIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// IdentityProviderGoogle names Google sign-in in Identity.Provider.
+const IdentityProviderGoogle = "google"
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+var affiliateLog = logging.Component("affiliate_account")
+
+// affiliateRefreshMargin renews access tokens this long before they expire.
+const affiliateRefreshMargin = 5 * time.Minute
+
+// affiliateRefreshBackoff is how long a failed refresh is not retried, so an
+// AliExpress outage is not hit again by every request of the user.
+const affiliateRefreshBackoff = time.Minute
+
+// errAffiliateRelink is returned when only linking the account again can
+// restore its credentials.
+var errAffiliateRelink = errors.New("refresh token refused or expired, the account must be linked again")
+
+var (
+	// ErrAffiliateNotLinked is returned when the user has no linked AliExpress account.
+	ErrAffiliateNotLinked = domain.NotFound("no AliExpress account is linked")
+	// ErrInvalidOAuthState is returned when a linking callback's state was not
+	// issued to the calling user or has expired.
+	ErrInvalidOAuthState = domain.Validation("sign-in state is missing or does not match").WithCode("INVALID_STATE")
+	// ErrInvalidTrackingID is returned for a malformed affiliate tracking ID.
+	ErrInvalidTrackingID = domain.Validation("tracking ID must be 1-64 letters, digits, '_' or '-'")
+)
+
+var trackingIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// AffiliateAccountUseCase links AliExpress accounts to users and hands out
+// their credentials for affiliate calls, refreshing tokens as they expire.
+type AffiliateAccountUseCase struct {
+	gateway  domain.AliExpressAuthGateway
+	accounts domain.AffiliateAccountRepository
+	tokens   *TokenService
+	now      func() time.Time
+
+	// refreshes collapses concurrent refreshes of one user so the same
+	// refresh token is not redeemed twice.
+	refreshes singleflight.Group
+}
+
+// NewAffiliateAccountUseCase creates a new AffiliateAccountUseCase.
+func NewAffiliateAccountUseCase(gateway domain.AliExpressAuthGateway, accounts domain.AffiliateAccountRepository, tokens *TokenService) *AffiliateAccountUseCase {
+	return &AffiliateAccountUseCase{gateway: gateway, accounts: accounts, tokens: tokens, now: time.Now}
+}
+
+// StartLink returns the AliExpress consent URL for userID. Its state is only
+// accepted back from the same user.
+func (uc *AffiliateAccountUseCase) StartLink(userID string) (string, error) {
+	state, err := uc.tokens.IssueOAuthState(userID)
+	if err != nil {
+		return "", err
+	}
+	return uc.gateway.AuthCodeURL(state), nil
+}
+
+// CompleteLink redeems the code returned to userID and stores the account.
+// Relinking replaces the previous account but keeps its tracking ID.
+func (uc *AffiliateAccountUseCase) CompleteLink(ctx context.Context, userID, code, state string) (*domain.AffiliateAccount, error) {
+	claims, err := uc.tokens.Parse(state, TokenKindOAuthState)
+	if err != nil || claims.Subject != userID {
+		return nil, ErrInvalidOAuthState
+	}
+	tok, err := uc.gateway.ExchangeCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	prev, err := uc.accounts.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := uc.now().UTC()
+	account := &domain.AffiliateAccount{UserID: userID, LinkedAt: now}
+	if prev != nil {
+		account.TrackingID = prev.TrackingID
+	}
+	applyAffiliateToken(account, tok, now)
+	if err := uc.accounts.Upsert(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Get returns the linked account of userID.
+func (uc *AffiliateAccountUseCase) Get(ctx context.Context, userID string) (*domain.AffiliateAccount, error) {
+	account, err := uc.accounts.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrAffiliateNotLinked
+	}
+	return account, nil
+}
+
+// SetTrackingID sets the tracking ID used for the user's affiliate calls.
+func (uc *AffiliateAccountUseCase) SetTrackingID(ctx context.Context, userID, trackingID string) (*domain.AffiliateAccount, error) {
+	trackingID = strings.TrimSpace(trackingID)
+	if !trackingIDPattern.MatchString(trackingID) {
+		return nil, ErrInvalidTrackingID
+	}
+	account, err := uc.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	account.TrackingID = trackingID
+	account.UpdatedAt = uc.now().UTC()
+	if err := uc.accounts.Upsert(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Unlink forgets the linked account of userID.
+func (uc *AffiliateAccountUseCase) Unlink(ctx context.Context, userID string) error {
+	return uc.accounts.Delete(ctx, userID)
+}
+
+// Credentials returns the affiliate credentials of userID, or nil when no
+// usable account is linked, in which case calls use the app credentials.
+func (uc *AffiliateAccountUseCase) Credentials(ctx context.Context, userID string) (*domain.AffiliateCredentials, error) {
+	account, err := uc.accounts.GetByUser(ctx, userID)
+	if err != nil || account == nil {
+		return nil, err
+	}
+	now := uc.now()
+	if now.Add(affiliateRefreshMargin).After(account.AccessExpiresAt) && refreshable(account, now) {
+		refreshed, err := uc.refresh(ctx, userID)
+		if err != nil {
+			affiliateLog.WarnContext(ctx, "affiliate token refresh failed", "user_id", userID, logging.Err(err))
+		} else {
+			account = refreshed
+		}
+	}
+	if !now.Before(account.AccessExpiresAt) {
+		return nil, nil
+	}
+	return &domain.AffiliateCredentials{Session: account.AccessToken, TrackingID: account.TrackingID}, nil
+}
+
+// refreshable reports whether a refresh of account may be attempted now.
+func refreshable(account *domain.AffiliateAccount, now time.Time) bool {
+	return !account.NeedsRelink && !now.Before(account.RefreshRetryAt)
+}
+
+// refresh renews the access token of userID, sharing one upstream call
+// among concurrent callers. It outlives a cancelled caller so the others
+// still get its result.
+func (uc *AffiliateAccountUseCase) refresh(ctx context.Context, userID string) (*domain.AffiliateAccount, error) {
+	v, err, _ := uc.refreshes.Do(userID, func() (interface{}, error) {
+		return uc.doRefresh(context.WithoutCancel(ctx), userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*domain.AffiliateAccount), nil
+}
+
+// doRefresh re-reads the account so a refresh that already happened is not
+// repeated, and records a failure on it: a refused or expired refresh token
+// marks the account for relinking, anything else backs off.
+func (uc *AffiliateAccountUseCase) doRefresh(ctx context.Context, userID string) (*domain.AffiliateAccount, error) {
+	account, err := uc.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := uc.now()
+	if now.Add(affiliateRefreshMargin).Before(account.AccessExpiresAt) {
+		return account, nil
+	}
+	if account.NeedsRelink {
+		return nil, errAffiliateRelink
+	}
+	if !refreshable(account, now) {
+		return nil, errors.New("token refresh is backing off after a failure")
+	}
+
+	var tok *domain.AffiliateToken
+	if account.RefreshToken == "" || now.After(account.RefreshExpiresAt) {
+		err = errAffiliateRelink
+	} else {
+		tok, err = uc.gateway.Refresh(ctx, account.RefreshToken)
+	}
+	if err != nil {
+		if errors.Is(err, errAffiliateRelink) || errors.Is(err, domain.ErrOAuthCodeRejected) {
+			account.NeedsRelink = true
+		} else {
+			account.RefreshRetryAt = now.Add(affiliateRefreshBackoff).UTC()
+		}
+		account.UpdatedAt = now.UTC()
+		if uerr := uc.accounts.Upsert(ctx, account); uerr != nil {
+			affiliateLog.WarnContext(ctx, "recording affiliate refresh failure failed", "user_id", userID, logging.Err(uerr))
+		}
+		return nil, err
+	}
+	applyAffiliateToken(account, tok, now.UTC())
+	if err := uc.accounts.Upsert(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func applyAffiliateToken(account *domain.AffiliateAccount, tok *domain.AffiliateToken, now time.Time) {
+	if tok.AccountID != "" {
+		account.AccountID = tok.AccountID
+	}
+	if tok.Account != "" {
+		account.Account = tok.Account
+	}
+	account.AccessToken = tok.AccessToken
+	if tok.RefreshToken != "" {
+		account.RefreshToken = tok.RefreshToken
+		account.RefreshExpiresAt = tok.RefreshExpiresAt
+	}
+	account.AccessExpiresAt = tok.AccessExpiresAt
+	account.UpdatedAt = now
+	account.NeedsRelink = false
+	account.RefreshRetryAt = time.Time{}
+}
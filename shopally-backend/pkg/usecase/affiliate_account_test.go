@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAliExpressAuth grants tokens numbered by call so refreshes are visible.
+type stubAliExpressAuth struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	attempts  int
+	refreshes int
+	rejectAll bool
+	fail      error
+	delay     time.Duration
+}
+
+func (g *stubAliExpressAuth) AuthCodeURL(state string) string {
+	return "https://ae.test/authorize?state=" + url.QueryEscape(state)
+}
+
+func (g *stubAliExpressAuth) grant(access string) *domain.AffiliateToken {
+	now := g.now()
+	return &domain.AffiliateToken{
+		AccountID: "2001", Account: "buyer@example.com",
+		AccessToken: access, RefreshToken: "refresh-" + access,
+		AccessExpiresAt: now.Add(time.Hour), RefreshExpiresAt: now.Add(24 * time.Hour),
+	}
+}
+
+func (g *stubAliExpressAuth) ExchangeCode(ctx context.Context, code string) (*domain.AffiliateToken, error) {
+	if code != "good-code" {
+		return nil, domain.ErrOAuthCodeRejected
+	}
+	return g.grant("access-0"), nil
+}
+
+func (g *stubAliExpressAuth) Refresh(ctx context.Context, refreshToken string) (*domain.AffiliateToken, error) {
+	time.Sleep(g.delay)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.attempts++
+	if g.rejectAll {
+		return nil, domain.ErrOAuthCodeRejected
+	}
+	if g.fail != nil {
+		return nil, g.fail
+	}
+	g.refreshes++
+	return g.grant("access-" + strconv.Itoa(g.refreshes)), nil
+}
+
+func TestAffiliateAccountUseCase(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	tokens := NewTokenService("test-secret", "shopally", time.Minute, time.Hour)
+	tokens.now = clock
+	gw := &stubAliExpressAuth{now: clock}
+	uc := NewAffiliateAccountUseCase(gw, repository.NewMockAffiliateAccountRepository(), tokens)
+	uc.now = clock
+
+	consent, err := uc.StartLink("user-1")
+	require.NoError(t, err)
+	parsed, err := url.Parse(consent)
+	require.NoError(t, err)
+	state := parsed.Query().Get("state")
+
+	// The state is bound to the user who started the flow.
+	_, err = uc.CompleteLink(ctx, "user-2", "good-code", state)
+	assert.ErrorIs(t, err, ErrInvalidOAuthState)
+	_, err = uc.CompleteLink(ctx, "user-1", "bad-code", state)
+	assert.ErrorIs(t, err, domain.ErrOAuthCodeRejected)
+
+	account, err := uc.CompleteLink(ctx, "user-1", "good-code", state)
+	require.NoError(t, err)
+	assert.Equal(t, "2001", account.AccountID)
+
+	_, err = uc.SetTrackingID(ctx, "user-1", "bad id!")
+	assert.ErrorIs(t, err, ErrInvalidTrackingID)
+	_, err = uc.SetTrackingID(ctx, "user-1", "shopally_1")
+	require.NoError(t, err)
+
+	creds, err := uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, &domain.AffiliateCredentials{Session: "access-0", TrackingID: "shopally_1"}, creds)
+
+	// Near expiry the access token is refreshed once and stored.
+	now = now.Add(58 * time.Minute)
+	creds, err = uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", creds.Session)
+	creds, err = uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", creds.Session)
+	assert.Equal(t, 1, gw.refreshes)
+
+	// A refused refresh falls back to the app credentials and marks the
+	// account for relinking instead of retrying upstream.
+	gw.rejectAll = true
+	now = now.Add(2 * time.Hour)
+	creds, err = uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+	creds, err = uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+	assert.Equal(t, 2, gw.attempts)
+	account, err = uc.Get(ctx, "user-1")
+	require.NoError(t, err)
+	assert.True(t, account.NeedsRelink)
+
+	// Relinking keeps the tracking ID; unlinking forgets the account.
+	state, err = tokens.IssueOAuthState("user-1")
+	require.NoError(t, err)
+	account, err = uc.CompleteLink(ctx, "user-1", "good-code", state)
+	require.NoError(t, err)
+	assert.Equal(t, "shopally_1", account.TrackingID)
+	assert.False(t, account.NeedsRelink)
+
+	require.NoError(t, uc.Unlink(ctx, "user-1"))
+	_, err = uc.Get(ctx, "user-1")
+	assert.ErrorIs(t, err, ErrAffiliateNotLinked)
+	creds, err = uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+}
+
+func TestAffiliateAccountUseCase_RefreshFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	gw := &stubAliExpressAuth{now: clock}
+	accounts := repository.NewMockAffiliateAccountRepository()
+	uc := NewAffiliateAccountUseCase(gw, accounts, NewTokenService("test-secret", "shopally", time.Minute, time.Hour))
+	uc.now = clock
+	require.NoError(t, accounts.Upsert(ctx, &domain.AffiliateAccount{
+		UserID: "user-1", AccessToken: "access-0", RefreshToken: "refresh-0",
+		AccessExpiresAt: now.Add(2 * time.Minute), RefreshExpiresAt: now.Add(24 * time.Hour),
+	}))
+
+	// An outage keeps the still-valid token in use and backs off.
+	gw.fail = errors.New("connection reset")
+	creds, err := uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-0", creds.Session)
+	_, err = uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, gw.attempts, "no retry while backing off")
+
+	// After the backoff the refresh is retried.
+	gw.fail = nil
+	now = now.Add(affiliateRefreshBackoff)
+	creds, err = uc.Credentials(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", creds.Session)
+
+	// Concurrent requests of one user share a single refresh.
+	now = now.Add(time.Hour)
+	gw.delay = 20 * time.Millisecond
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			creds, err := uc.Credentials(ctx, "user-1")
+			assert.NoError(t, err)
+			if assert.NotNil(t, creds) {
+				assert.Equal(t, "access-2", creds.Session)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 2, gw.refreshes)
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var authLog = logging.Component("auth")
+
+// MinPasswordLength is the shortest password accepted at registration.
+const MinPasswordLength = 8
+
+// MaxPasswordBytes is the longest password accepted at registration, the
+// most bcrypt hashes.
+const MaxPasswordBytes = 72
+
+// dummyPasswordHash is compared against when there is no real hash, at the
+// cost Register hashes with.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, err := bcrypt.GenerateFromPassword([]byte("shopally-dummy-password"), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
+var (
+	// ErrInvalidCredentials is returned when the email or password is wrong.
+	ErrInvalidCredentials = domain.Unauthorized("invalid email or password").WithCode("INVALID_CREDENTIALS")
+	// ErrInvalidEmail is returned for a malformed email address.
+	ErrInvalidEmail = domain.Validation("invalid email address")
+	// ErrWeakPassword is returned for a password shorter than MinPasswordLength.
+	ErrWeakPassword = domain.Validation("password must be at least 8 characters")
+	// ErrLongPassword is returned for a password over MaxPasswordBytes.
+	ErrLongPassword = domain.Validation("password must be at most 72 bytes")
+)
+
+// AuthUseCase registers users and manages their sessions. Access tokens are
+// short-lived JWTs; refresh tokens are JWTs backed by a stored session that
+// is rotated on every refresh and revoked on logout.
+type AuthUseCase struct {
+	users    domain.UserRepository
+	sessions domain.SessionRepository
+	tokens   *TokenService
+	now      func() time.Time
+}
+
+// NewAuthUseCase creates a new AuthUseCase.
+func NewAuthUseCase(users domain.UserRepository, sessions domain.SessionRepository, tokens *TokenService) *AuthUseCase {
+	return &AuthUseCase{users: users, sessions: sessions, tokens: tokens, now: time.Now}
+}
+
+// Register creates an account with a bcrypt-hashed password and signs it in.
+func (uc *AuthUseCase) Register(ctx context.Context, email, password, name string) (*domain.User, *domain.AuthTokens, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(password) < MinPasswordLength {
+		return nil, nil, ErrWeakPassword
+	}
+	if len(password) > MaxPasswordBytes {
+		return nil, nil, ErrLongPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := uc.now().UTC()
+	user := &domain.User{
+		ID:           uuid.New().String(),
+		Email:        email,
+		Name:         strings.TrimSpace(name),
+		PasswordHash: string(hash),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := uc.users.Create(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := uc.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// Login checks the password and issues a new token pair.
+func (uc *AuthUseCase) Login(ctx context.Context, email, password string) (*domain.User, *domain.AuthTokens, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+	user, err := uc.users.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, nil, err
+	}
+	// Unknown emails and accounts without a password are checked against a
+	// dummy hash so the response time does not tell them apart.
+	known := err == nil && user.PasswordHash != ""
+	hash := dummyPasswordHash()
+	if known {
+		hash = []byte(user.PasswordHash)
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil || !known {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	tokens, err := uc.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// IssueTokens starts a new refresh session for user and returns a token pair.
+func (uc *AuthUseCase) IssueTokens(ctx context.Context, user *domain.User) (*domain.AuthTokens, error) {
+	now := uc.now().UTC()
+	session := &domain.RefreshSession{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.tokens.RefreshTTL()),
+	}
+	if err := uc.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	access, accessExp, err := uc.tokens.IssueAccess(user)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := uc.tokens.IssueRefresh(session)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuthTokens{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		AccessExpiresAt:  accessExp,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// Refresh redeems a refresh token for a new token pair. The old session is
+// revoked; presenting an already revoked token revokes every session of the
+// user, since it means the token was copied.
+func (uc *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (*domain.AuthTokens, error) {
+	claims, err := uc.tokens.Parse(refreshToken, TokenKindRefresh)
+	if err != nil {
+		return nil, err
+	}
+	session, err := uc.sessions.Get(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != claims.Subject {
+		return nil, ErrInvalidToken
+	}
+	if session.RevokedAt != nil {
+		return nil, uc.reused(ctx, session.UserID)
+	}
+
+	user, err := uc.users.GetByID(ctx, session.UserID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Rotation hinges on revoking the session: a concurrent refresh that
+	// revoked it first means the token was used twice.
+	revoked, err := uc.sessions.Revoke(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, uc.reused(ctx, session.UserID)
+	}
+	return uc.IssueTokens(ctx, user)
+}
+
+// reused revokes every session of a user whose refresh token was presented
+// after rotation, as it may have been stolen.
+func (uc *AuthUseCase) reused(ctx context.Context, userID string) error {
+	authLog.WarnContext(ctx, "revoked refresh token reused, revoking all sessions", "user_id", userID)
+	if err := uc.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	return ErrInvalidToken
+}
+
+// Logout revokes the session behind a refresh token. Logging out twice is not
+// an error.
+func (uc *AuthUseCase) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := uc.tokens.Parse(refreshToken, TokenKindRefresh)
+	if err != nil {
+		return err
+	}
+	_, err = uc.sessions.Revoke(ctx, claims.ID)
+	return err
+}
+
+// Authenticate verifies an access token and returns the user it was issued
+// to, built from the token claims without a database lookup.
+func (uc *AuthUseCase) Authenticate(accessToken string) (*domain.User, error) {
+	claims, err := uc.tokens.Parse(accessToken, TokenKindAccess)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.User{ID: claims.Subject, Email: claims.Email, Name: claims.Name}, nil
+}
+
+// Me returns the stored account of the given user.
+func (uc *AuthUseCase) Me(ctx context.Context, userID string) (*domain.User, error) {
+	return uc.users.GetByID(ctx, userID)
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuth() *AuthUseCase {
+	tokens := NewTokenService("test-secret", "shopally", time.Minute, time.Hour)
+	return NewAuthUseCase(repository.NewMockUserRepository(), repository.NewMockSessionRepository(), tokens)
+}
+
+func TestAuthUseCase_RegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	auth := newTestAuth()
+
+	user, tokens, err := auth.Register(ctx, " Abebe@Example.com ", "correct horse", "Abebe")
+	require.NoError(t, err)
+	assert.Equal(t, "abebe@example.com", user.Email)
+	assert.NotEqual(t, "correct horse", user.PasswordHash)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+
+	current, err := auth.Authenticate(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, current.ID)
+	assert.Equal(t, "abebe@example.com", current.Email)
+
+	_, _, err = auth.Register(ctx, "abebe@example.com", "another password", "")
+	assert.ErrorIs(t, err, domain.ErrEmailTaken)
+	_, _, err = auth.Register(ctx, "not-an-email", "correct horse", "")
+	assert.ErrorIs(t, err, ErrInvalidEmail)
+	_, _, err = auth.Register(ctx, "short@example.com", "short", "")
+	assert.ErrorIs(t, err, ErrWeakPassword)
+	_, _, err = auth.Register(ctx, "long@example.com", strings.Repeat("ü", 37), "")
+	assert.ErrorIs(t, err, ErrLongPassword, "74 bytes in 37 characters")
+	assert.Equal(t, domain.KindValidation, domain.KindOf(err))
+
+	_, _, err = auth.Login(ctx, "ABEBE@example.com", "correct horse")
+	assert.NoError(t, err)
+	_, _, err = auth.Login(ctx, "abebe@example.com", "wrong password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, _, err = auth.Login(ctx, "nobody@example.com", "correct horse")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, _, err = auth.Login(ctx, "nobody@example.com", "shopally-dummy-password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "the dummy hash never signs anyone in")
+}
+
+func TestAuthUseCase_RefreshAndLogout(t *testing.T) {
+	ctx := context.Background()
+	auth := newTestAuth()
+	_, tokens, err := auth.Register(ctx, "a@example.com", "correct horse", "")
+	require.NoError(t, err)
+
+	_, err = auth.Authenticate(tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken, "refresh tokens are not access tokens")
+
+	rotated, err := auth.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+
+	// Reusing the old refresh token revokes every session of the user.
+	_, err = auth.Refresh(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = auth.Refresh(ctx, rotated.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, fresh, err := auth.Login(ctx, "a@example.com", "correct horse")
+	require.NoError(t, err)
+	require.NoError(t, auth.Logout(ctx, fresh.RefreshToken))
+	require.NoError(t, auth.Logout(ctx, fresh.RefreshToken))
+	_, err = auth.Refresh(ctx, fresh.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+// staleSessions returns sessions as they were before any revocation, like a
+// read that raced a concurrent refresh.
+type staleSessions struct {
+	*repository.MockSessionRepository
+}
+
+func (s staleSessions) Get(ctx context.Context, id string) (*domain.RefreshSession, error) {
+	session, err := s.MockSessionRepository.Get(ctx, id)
+	if session != nil {
+		session.RevokedAt = nil
+	}
+	return session, err
+}
+
+func TestAuthUseCase_RefreshRace(t *testing.T) {
+	ctx := context.Background()
+	sessions := staleSessions{repository.NewMockSessionRepository()}
+	auth := NewAuthUseCase(repository.NewMockUserRepository(), sessions, NewTokenService("test-secret", "shopally", time.Minute, time.Hour))
+	_, tokens, err := auth.Register(ctx, "a@example.com", "correct horse", "")
+	require.NoError(t, err)
+
+	rotated, err := auth.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+
+	// The losing refresh still sees the session as live but cannot revoke
+	// it, and is treated as reuse.
+	_, err = auth.Refresh(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	claims, err := auth.tokens.Parse(rotated.RefreshToken, TokenKindRefresh)
+	require.NoError(t, err)
+	session, err := sessions.MockSessionRepository.Get(ctx, claims.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, session.RevokedAt, "the winner's session is revoked too")
+}
+
+func TestTokenService_Expiry(t *testing.T) {
+	svc := NewTokenService("secret", "shopally", time.Minute, time.Hour)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	token, _, err := svc.IssueAccess(&domain.User{ID: "u1"})
+	require.NoError(t, err)
+	_, err = svc.Parse(token, TokenKindAccess)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return now.Add(2 * time.Minute) }
+	_, err = svc.Parse(token, TokenKindAccess)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	other := NewTokenService("other-secret", "shopally", time.Minute, time.Hour)
+	_, err = other.Parse(token, TokenKindAccess)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// Cart quote limits.
+const (
+	MaxCartItems    = 20
+	MaxCartQuantity = 99
+)
+
+// ErrInvalidCart is returned for an empty or oversized cart or a bad quantity.
+var ErrInvalidCart = domain.Validation("invalid cart")
+
+// CartQuoteUseCase prices a cart with server-side product data and returns
+// the itemized landed cost.
+type CartQuoteUseCase struct {
+	resolver   ProductsResolver
+	landedCost *LandedCostCalculator
+}
+
+// NewCartQuoteUseCase creates a new CartQuoteUseCase.
+func NewCartQuoteUseCase(resolver ProductsResolver, lc *LandedCostCalculator) *CartQuoteUseCase {
+	return &CartQuoteUseCase{resolver: resolver, landedCost: lc}
+}
+
+// Quote resolves the cart's products and sums their landed cost. Each
+// product's LandedCost is per unit; the quote lines are per cart.
+func (uc *CartQuoteUseCase) Quote(ctx context.Context, items []domain.CartItem) (*domain.CartQuote, error) {
+	if len(items) == 0 || len(items) > MaxCartItems {
+		return nil, ErrInvalidCart
+	}
+	refs := make([]domain.ProductRef, len(items))
+	for i, it := range items {
+		if it.Quantity < 1 || it.Quantity > MaxCartQuantity {
+			return nil, ErrInvalidCart
+		}
+		refs[i] = it.Product
+	}
+
+	products, err := uc.resolver.Resolve(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+	uc.landedCost.AnnotateAll(ctx, products)
+
+	quote := &domain.CartQuote{Items: make([]domain.CartQuoteItem, 0, len(items))}
+	lineIdx := map[string]int{}
+	for i, p := range products {
+		qty := float64(items[i].Quantity)
+		item := domain.CartQuoteItem{Product: *p, Quantity: items[i].Quantity}
+		if p.LandedCost != nil {
+			item.TotalUSD = roundTo(p.LandedCost.TotalUSD*qty, 2)
+			item.TotalETB = roundTo(p.LandedCost.TotalETB*qty, 2)
+			for _, l := range p.LandedCost.Lines {
+				j, ok := lineIdx[l.Code]
+				if !ok {
+					j = len(quote.Lines)
+					lineIdx[l.Code] = j
+					quote.Lines = append(quote.Lines, domain.LandedCostLine{Code: l.Code})
+				}
+				quote.Lines[j].USD = roundTo(quote.Lines[j].USD+l.USD*qty, 2)
+				quote.Lines[j].ETB = roundTo(quote.Lines[j].ETB+l.ETB*qty, 2)
+			}
+		}
+		quote.TotalUSD = roundTo(quote.TotalUSD+item.TotalUSD, 2)
+		quote.TotalETB = roundTo(quote.TotalETB+item.TotalETB, 2)
+		quote.Items = append(quote.Items, item)
+	}
+	return quote, nil
+}
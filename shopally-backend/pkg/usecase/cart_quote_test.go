@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCartQuoteUseCase_Quote(t *testing.T) {
+	resolver := new(MockProductsResolver)
+	refs := []domain.ProductRef{{ID: "1"}, {ID: "2"}}
+	resolver.On("Resolve", context.Background(), refs).Return([]*domain.Product{
+		{ID: "1", Price: domain.Price{USD: 10}},
+		{ID: "2", Price: domain.Price{USD: 20}},
+	}, nil)
+	uc := NewCartQuoteUseCase(resolver, NewLandedCostCalculator(DefaultTariffTable, fixedFX(100)))
+
+	quote, err := uc.Quote(context.Background(), []domain.CartItem{{Product: refs[0], Quantity: 2}, {Product: refs[1], Quantity: 1}})
+	require.NoError(t, err)
+	require.Len(t, quote.Items, 2)
+
+	one := quote.Items[0].Product.LandedCost
+	require.NotNil(t, one)
+	assert.InDelta(t, one.TotalUSD*2, quote.Items[0].TotalUSD, 1e-9)
+	assert.Equal(t, domain.CostItem, quote.Lines[0].Code)
+	assert.InDelta(t, 40, quote.Lines[0].USD, 1e-9)
+	assert.InDelta(t, quote.Items[0].TotalUSD+quote.Items[1].TotalUSD, quote.TotalUSD, 1e-9)
+	assert.InDelta(t, quote.TotalUSD*100, quote.TotalETB, 0.05)
+
+	_, err = uc.Quote(context.Background(), []domain.CartItem{{Product: refs[0], Quantity: 0}})
+	assert.ErrorIs(t, err, ErrInvalidCart)
+	_, err = uc.Quote(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrInvalidCart)
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// DefaultComparisonTTL is how long a comparison stays cached and shareable.
+const DefaultComparisonTTL = 72 * time.Hour
+
+type resolvedProductsKey struct{}
+
+// WithResolvedProducts marks the products compared under ctx as resolved
+// server-side. Only such comparisons are cached and shareable: products
+// sent by clients may carry forged titles, images or links.
+func WithResolvedProducts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resolvedProductsKey{}, true)
+}
+
+func productsResolved(ctx context.Context) bool {
+	resolved, _ := ctx.Value(resolvedProductsKey{}).(bool)
+	return resolved
+}
+
+func comparisonCacheKey(id string) string {
+	return "comparison:" + id
+}
+
+// comparisonID hashes the product set, language and mode. Prices are part of
+// the hash so a price change yields a fresh comparison.
+func comparisonID(ctx context.Context, products []*domain.Product, mode string) string {
+	lang, _ := ctx.Value(contextkeys.RespLang).(string)
+	if lang == "" {
+		lang = "en"
+	}
+
+	parts := make([]string, 0, len(products))
+	for _, p := range products {
+		if p != nil {
+			parts = append(parts, fmt.Sprintf("%s@%.2f", p.ID, p.Price.USD))
+		}
+	}
+	sort.Strings(parts)
+
+	sum := sha256.Sum256([]byte(lang + "|" + mode + "|" + strings.Join(parts, ",")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Get returns a stored comparison by ID or domain.ErrComparisonNotFound.
+func (uc *CompareProductsUseCase) Get(ctx context.Context, id string) (*domain.ComparisonResult, error) {
+	if result := uc.fromCache(ctx, id); result != nil {
+		return result, nil
+	}
+	return nil, domain.ErrComparisonNotFound
+}
+
+func (uc *CompareProductsUseCase) fromCache(ctx context.Context, id string) *domain.ComparisonResult {
+	if uc.cache == nil || !isComparisonID(id) {
+		return nil
+	}
+	val, ok, err := uc.cache.Get(ctx, comparisonCacheKey(id))
+	if err != nil || !ok {
+		return nil
+	}
+	var result domain.ComparisonResult
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		return nil
+	}
+	return &result
+}
+
+// toCache stores result under id and returns a copy stamped with the ID and
+// expiry, or result unchanged when it could not be stored.
+func (uc *CompareProductsUseCase) toCache(ctx context.Context, id string, result *domain.ComparisonResult) *domain.ComparisonResult {
+	if uc.cache == nil {
+		return result
+	}
+	expires := uc.now().UTC().Add(uc.ttl)
+	stored := *result
+	stored.ID = id
+	stored.ExpiresAt = &expires
+
+	b, err := json.Marshal(&stored)
+	if err != nil {
+		return result
+	}
+	if err := uc.cache.Set(ctx, comparisonCacheKey(id), string(b), uc.ttl); err != nil {
+		compareLog.WarnContext(ctx, "comparison cache set failed", "comparison_id", id, logging.Err(err))
+		return result
+	}
+	return &stored
+}
+
+func isComparisonID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareProducts_ResultCache(t *testing.T) {
+	products := []*domain.Product{
+		{ID: "A", Price: domain.Price{USD: 20}},
+		{ID: "B", Price: domain.Price{USD: 10}},
+	}
+	valid := comparison(entry("A", true), entry("B", false))
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	resolved := WithResolvedProducts(context.Background())
+
+	t.Run("serves repeat requests and shared links from cache", func(t *testing.T) {
+		llm := &scriptedCompareLLM{results: []*domain.ComparisonResult{valid, valid, valid}}
+		uc := NewCompareProductsUseCase(llm).WithResultCache(newMemoryCache(), time.Hour)
+		uc.now = func() time.Time { return now }
+
+		first, err := uc.Execute(resolved, products, "")
+		require.NoError(t, err)
+		require.NotEmpty(t, first.ID)
+		assert.Equal(t, now.Add(time.Hour), *first.ExpiresAt)
+
+		reversed := []*domain.Product{products[1], products[0]}
+		again, err := uc.Execute(resolved, reversed, domain.CompareModeAuto)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, again.ID)
+		assert.Equal(t, 1, llm.calls)
+
+		shared, err := uc.Get(context.Background(), first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first, shared)
+
+		am := context.WithValue(resolved, contextkeys.RespLang, "am")
+		other, err := uc.Execute(am, products, "")
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, other.ID)
+		assert.Equal(t, 2, llm.calls)
+	})
+
+	t.Run("does not cache a rules fallback in auto mode", func(t *testing.T) {
+		llm := &scriptedCompareLLM{errs: []error{errors.New("down"), errors.New("down")}}
+		uc := NewCompareProductsUseCase(llm).WithResultCache(newMemoryCache(), 0)
+
+		out, err := uc.Execute(resolved, products, "")
+		require.NoError(t, err)
+		assert.Empty(t, out.ID)
+	})
+
+	t.Run("client-supplied products bypass the cache", func(t *testing.T) {
+		llm := &scriptedCompareLLM{results: []*domain.ComparisonResult{valid, valid}}
+		uc := NewCompareProductsUseCase(llm).WithResultCache(newMemoryCache(), time.Hour)
+
+		forged := []*domain.Product{
+			{ID: "A", Price: domain.Price{USD: 20}, DeeplinkURL: "https://evil.example"},
+			{ID: "B", Price: domain.Price{USD: 10}},
+		}
+		out, err := uc.Execute(context.Background(), forged, "")
+		require.NoError(t, err)
+		assert.Empty(t, out.ID)
+
+		out, err = uc.Execute(resolved, products, "")
+		require.NoError(t, err)
+		assert.NotEmpty(t, out.ID)
+		assert.Equal(t, 2, llm.calls, "the forged comparison was not served")
+	})
+
+	t.Run("unknown IDs are not found", func(t *testing.T) {
+		uc := NewCompareProductsUseCase(&scriptedCompareLLM{}).WithResultCache(newMemoryCache(), 0)
+		_, err := uc.Get(context.Background(), "0123456789abcdef0123456789abcdef")
+		assert.ErrorIs(t, err, domain.ErrComparisonNotFound)
+		_, err = uc.Get(context.Background(), "../etc")
+		assert.ErrorIs(t, err, domain.ErrComparisonNotFound)
+	})
+}
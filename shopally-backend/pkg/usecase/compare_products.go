@@ -2,34 +2,171 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/tracing"
 	"github.com/shopally-ai/pkg/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var compareLog = logging.Component("compare")
+
 // CompareProductsExecutor defines the contract for comparing products.
 type CompareProductsExecutor interface {
-	Execute(ctx context.Context, products []*domain.Product) (interface{}, error)
+	Execute(ctx context.Context, products []*domain.Product, mode string) (*domain.ComparisonResult, error)
+	// Get returns a previously stored comparison or domain.ErrComparisonNotFound.
+	Get(ctx context.Context, id string) (*domain.ComparisonResult, error)
 }
 
-// CompareProductsUseCase is the real implementation that calls the LLM gateway.
+// ErrInvalidCompareMode is returned for a mode other than auto, llm or rules.
+var ErrInvalidCompareMode = domain.Validation("mode must be one of: auto, llm, rules")
+
+// CompareProductsUseCase is the real implementation that calls the LLM gateway
+// and falls back to the rule-based comparator.
 type CompareProductsUseCase struct {
 	llmGateway domain.LLMGateway
+	rules      *RuleBasedComparator
+	landedCost *LandedCostCalculator
+	cache      domain.ICachePort
+	ttl        time.Duration
+	now        func() time.Time
 }
 
 var _ CompareProductsExecutor = (*CompareProductsUseCase)(nil)
 
-// Execute delegates to the LLMGateway to compare products.
-func (uc *CompareProductsUseCase) Execute(ctx context.Context, products []*domain.Product) (interface{}, error) {
-	result, err := uc.llmGateway.CompareProducts(ctx, products)
+// Execute compares products using the given mode. An empty mode means
+// domain.CompareModeAuto: the LLM is tried first and the rule-based
+// comparator is used when it fails. Comparisons of products resolved
+// server-side (see WithResolvedProducts) are served from the result cache
+// when the same product set was compared recently.
+func (uc *CompareProductsUseCase) Execute(ctx context.Context, products []*domain.Product, mode string) (_ *domain.ComparisonResult, err error) {
+	ctx, span := tracer.Start(ctx, "compare", trace.WithAttributes(
+		attribute.Int("products.count", len(products)),
+		attribute.String("compare.mode", mode),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	switch mode {
+	case "":
+		mode = domain.CompareModeAuto
+	case domain.CompareModeAuto, domain.CompareModeLLM, domain.CompareModeRules:
+	default:
+		return nil, ErrInvalidCompareMode
+	}
+
+	if !productsResolved(ctx) {
+		return uc.compare(ctx, products, mode)
+	}
+
+	id := comparisonID(ctx, products, mode)
+	cached := uc.fromCache(ctx, id)
+	span.SetAttributes(attribute.Bool("cache.hit", cached != nil))
+	if cached != nil {
+		return cached, nil
+	}
+
+	result, err := uc.compare(ctx, products, mode)
 	if err != nil {
 		return nil, err
 	}
+	// A rules fallback is not cached in auto mode so the next request retries the LLM.
+	if mode != domain.CompareModeAuto || result.Source == domain.ComparisonSourceLLM {
+		result = uc.toCache(ctx, id, result)
+	}
+	return result, nil
+}
+
+func (uc *CompareProductsUseCase) compare(ctx context.Context, products []*domain.Product, mode string) (*domain.ComparisonResult, error) {
+	if uc.landedCost != nil {
+		uc.landedCost.AnnotateAll(ctx, products)
+	}
+	if mode == domain.CompareModeRules {
+		return uc.rules.Compare(ctx, products), nil
+	}
+
+	if uc.llmGateway == nil {
+		if mode == domain.CompareModeLLM {
+			return nil, errors.New("llm comparator is not configured")
+		}
+		return uc.rules.Compare(ctx, products), nil
+	}
+
+	result, err := uc.compareWithLLM(ctx, products)
+	if err != nil {
+		if mode == domain.CompareModeLLM {
+			return nil, err
+		}
+		compareLog.WarnContext(ctx, "LLM comparison failed, using rules", logging.Err(err))
+		return uc.rules.Compare(ctx, products), nil
+	}
+	result.Source = domain.ComparisonSourceLLM
+
+	// The LLM's free-form features do not line up across products, so the
+	// aligned spec matrix is always extracted server-side.
+	ordered := make([]*domain.Product, len(result.Products))
+	for i := range result.Products {
+		ordered[i] = &result.Products[i].Product
+	}
+	result.Specs = buildSpecMatrix(ordered)
 	return result, nil
 }
 
+// compareWithLLM asks the LLMGateway to compare products and validates the
+// result. An invalid or unparseable answer is retried once; if the retry is
+// still invalid the best available answer is repaired instead of failing.
+func (uc *CompareProductsUseCase) compareWithLLM(ctx context.Context, products []*domain.Product) (_ *domain.ComparisonResult, err error) {
+	ctx, span := tracer.Start(ctx, "compare.llm")
+	defer func() { tracing.End(span, err) }()
+
+	var result *domain.ComparisonResult
+	for attempt := 1; attempt <= 2; attempt++ {
+		span.SetAttributes(attribute.Int("compare.attempts", attempt))
+		candidate, callErr := uc.llmGateway.CompareProducts(ctx, products)
+		if callErr != nil {
+			compareLog.WarnContext(ctx, "LLM comparison attempt failed", "attempt", attempt, logging.Err(callErr))
+			err = callErr
+			continue
+		}
+		verr := validateComparison(candidate, products)
+		if verr == nil {
+			return candidate, nil
+		}
+		compareLog.WarnContext(ctx, "LLM comparison attempt returned an invalid comparison", "attempt", attempt, logging.Err(verr))
+		result = candidate
+	}
+
+	if result == nil {
+		return nil, err
+	}
+	return repairComparison(result, products), nil
+}
+
 // NewCompareProductsUseCase creates a new use case instance.
 func NewCompareProductsUseCase(lg domain.LLMGateway) *CompareProductsUseCase {
 	return &CompareProductsUseCase{
 		llmGateway: lg,
+		rules:      NewRuleBasedComparator(),
+		ttl:        DefaultComparisonTTL,
+		now:        time.Now,
+	}
+}
+
+// WithLandedCost attaches an itemized landed cost to every compared product.
+func (uc *CompareProductsUseCase) WithLandedCost(lc *LandedCostCalculator) *CompareProductsUseCase {
+	uc.landedCost = lc
+	return uc
+}
+
+// WithResultCache stores comparison results so repeat requests and shared
+// links are served without re-running the comparator. Non-positive ttl keeps
+// DefaultComparisonTTL.
+func (uc *CompareProductsUseCase) WithResultCache(cache domain.ICachePort, ttl time.Duration) *CompareProductsUseCase {
+	uc.cache = cache
+	if ttl > 0 {
+		uc.ttl = ttl
 	}
+	return uc
 }
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedCompareLLM returns the queued comparison results in order.
+type scriptedCompareLLM struct {
+	stubIntentLLM
+	results []*domain.ComparisonResult
+	errs    []error
+	calls   int
+}
+
+func (s *scriptedCompareLLM) CompareProducts(ctx context.Context, products []*domain.Product) (*domain.ComparisonResult, error) {
+	i := s.calls
+	s.calls++
+	var err error
+	if i < len(s.errs) {
+		err = s.errs[i]
+	}
+	if i < len(s.results) {
+		return s.results[i], err
+	}
+	return nil, err
+}
+
+func comparison(entries ...domain.ProductComparison) *domain.ComparisonResult {
+	return &domain.ComparisonResult{Products: entries}
+}
+
+func entry(id string, best bool) domain.ProductComparison {
+	return domain.ProductComparison{Product: domain.Product{ID: id}, Synthesis: domain.Synthesis{IsBestValue: best, Pros: []string{"pro " + id}}}
+}
+
+func TestCompareProducts_ValidatesAndRepairs(t *testing.T) {
+	products := []*domain.Product{
+		{ID: "A", Price: domain.Price{USD: 20}},
+		{ID: "B", Price: domain.Price{USD: 10}},
+	}
+
+	t.Run("returns a valid first answer", func(t *testing.T) {
+		llm := &scriptedCompareLLM{results: []*domain.ComparisonResult{comparison(entry("A", true), entry("B", false))}}
+		out, err := NewCompareProductsUseCase(llm).Execute(context.Background(), products, domain.CompareModeLLM)
+		require.NoError(t, err)
+		assert.Equal(t, 1, llm.calls)
+		assert.True(t, out.Products[0].Synthesis.IsBestValue)
+	})
+
+	t.Run("retries once when the answer is invalid", func(t *testing.T) {
+		llm := &scriptedCompareLLM{results: []*domain.ComparisonResult{
+			comparison(entry("A", true)),
+			comparison(entry("B", true), entry("A", false)),
+		}}
+		out, err := NewCompareProductsUseCase(llm).Execute(context.Background(), products, domain.CompareModeLLM)
+		require.NoError(t, err)
+		assert.Equal(t, 2, llm.calls)
+		assert.Equal(t, "B", out.Products[0].Product.ID)
+	})
+
+	t.Run("repairs when the retry is still invalid", func(t *testing.T) {
+		llm := &scriptedCompareLLM{results: []*domain.ComparisonResult{
+			comparison(entry("A", true), entry("A", true), entry("X", false)),
+			comparison(entry("A", false), entry("X", true)),
+		}}
+		out, err := NewCompareProductsUseCase(llm).Execute(context.Background(), products, domain.CompareModeLLM)
+		require.NoError(t, err)
+		require.NoError(t, validateComparison(out, products))
+		assert.Equal(t, []string{"pro A"}, out.Products[0].Synthesis.Pros)
+		assert.Equal(t, 20.0, out.Products[0].Product.Price.USD)
+		assert.Empty(t, out.Products[1].Synthesis.Pros)
+		assert.True(t, out.Products[1].Synthesis.IsBestValue, "cheapest product becomes best value")
+	})
+
+	t.Run("repairs the first answer when the retry errors", func(t *testing.T) {
+		llm := &scriptedCompareLLM{
+			results: []*domain.ComparisonResult{comparison(entry("A", true), entry("B", true))},
+			errs:    []error{nil, errors.New("boom")},
+		}
+		out, err := NewCompareProductsUseCase(llm).Execute(context.Background(), products, domain.CompareModeLLM)
+		require.NoError(t, err)
+		assert.True(t, out.Products[0].Synthesis.IsBestValue)
+		assert.False(t, out.Products[1].Synthesis.IsBestValue)
+	})
+
+	t.Run("fails when both attempts error", func(t *testing.T) {
+		llm := &scriptedCompareLLM{errs: []error{errors.New("boom"), errors.New("boom again")}}
+		_, err := NewCompareProductsUseCase(llm).Execute(context.Background(), products, domain.CompareModeLLM)
+		assert.EqualError(t, err, "boom again")
+	})
+}
+
+func TestCompareProducts_Modes(t *testing.T) {
+	products := []*domain.Product{
+		{ID: "A", Price: domain.Price{USD: 20}},
+		{ID: "B", Price: domain.Price{USD: 10}},
+	}
+
+	t.Run("auto falls back to rules when the LLM fails", func(t *testing.T) {
+		llm := &scriptedCompareLLM{errs: []error{errors.New("boom"), errors.New("boom")}}
+		out, err := NewCompareProductsUseCase(llm).Execute(context.Background(), products, "")
+		require.NoError(t, err)
+		assert.Equal(t, domain.ComparisonSourceRules, out.Source)
+		assert.True(t, out.Products[1].Synthesis.IsBestValue)
+	})
+
+	t.Run("rules mode never calls the LLM", func(t *testing.T) {
+		llm := &scriptedCompareLLM{}
+		out, err := NewCompareProductsUseCase(llm).Execute(context.Background(), products, domain.CompareModeRules)
+		require.NoError(t, err)
+		assert.Zero(t, llm.calls)
+		assert.Equal(t, domain.ComparisonSourceRules, out.Source)
+	})
+
+	t.Run("llm results are tagged with their source", func(t *testing.T) {
+		llm := &scriptedCompareLLM{results: []*domain.ComparisonResult{comparison(entry("A", true), entry("B", false))}}
+		out, err := NewCompareProductsUseCase(llm).Execute(context.Background(), products, domain.CompareModeAuto)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ComparisonSourceLLM, out.Source)
+	})
+
+	t.Run("rejects unknown modes", func(t *testing.T) {
+		_, err := NewCompareProductsUseCase(&scriptedCompareLLM{}).Execute(context.Background(), products, "magic")
+		assert.ErrorIs(t, err, ErrInvalidCompareMode)
+	})
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// ErrInvalidComparison is returned when an LLM comparison does not cover the
+// requested products correctly.
+var ErrInvalidComparison = errors.New("invalid comparison result")
+
+// validateComparison checks that every input product appears exactly once,
+// no unknown products are present, and exactly one product is the best value.
+func validateComparison(result *domain.ComparisonResult, products []*domain.Product) error {
+	if result == nil {
+		return fmt.Errorf("%w: empty result", ErrInvalidComparison)
+	}
+
+	want := make(map[string]bool, len(products))
+	for _, p := range products {
+		if p != nil {
+			want[p.ID] = true
+		}
+	}
+
+	seen := make(map[string]bool, len(result.Products))
+	best := 0
+	for _, pc := range result.Products {
+		id := pc.Product.ID
+		if !want[id] {
+			return fmt.Errorf("%w: unknown product %q", ErrInvalidComparison, id)
+		}
+		if seen[id] {
+			return fmt.Errorf("%w: product %q listed more than once", ErrInvalidComparison, id)
+		}
+		seen[id] = true
+		if pc.Synthesis.IsBestValue {
+			best++
+		}
+	}
+	for id := range want {
+		if !seen[id] {
+			return fmt.Errorf("%w: product %q missing", ErrInvalidComparison, id)
+		}
+	}
+	if best != 1 {
+		return fmt.Errorf("%w: %d products marked as best value", ErrInvalidComparison, best)
+	}
+	return nil
+}
+
+// repairComparison rebuilds result so it passes validateComparison: entries
+// follow input order, duplicates and unknown products are dropped, missing
+// products get an empty synthesis, and a single best value is kept (the first
+// one the LLM marked, otherwise the cheapest product).
+func repairComparison(result *domain.ComparisonResult, products []*domain.Product) *domain.ComparisonResult {
+	synth := make(map[string]domain.Synthesis)
+	if result != nil {
+		for _, pc := range result.Products {
+			if _, dup := synth[pc.Product.ID]; !dup {
+				synth[pc.Product.ID] = pc.Synthesis
+			}
+		}
+	}
+
+	out := &domain.ComparisonResult{Products: make([]domain.ProductComparison, 0, len(products))}
+	bestIdx := -1
+	for _, p := range products {
+		if p == nil {
+			continue
+		}
+		s := synth[p.ID]
+		if s.IsBestValue && bestIdx == -1 {
+			bestIdx = len(out.Products)
+		}
+		s.IsBestValue = false
+		out.Products = append(out.Products, domain.ProductComparison{Product: *p, Synthesis: s})
+	}
+	if len(out.Products) == 0 {
+		return out
+	}
+
+	if bestIdx == -1 {
+		bestIdx = 0
+		for i, pc := range out.Products {
+			if pc.Product.Price.USD > 0 && (out.Products[bestIdx].Product.Price.USD <= 0 || pc.Product.Price.USD < out.Products[bestIdx].Product.Price.USD) {
+				bestIdx = i
+			}
+		}
+	}
+	out.Products[bestIdx].Synthesis.IsBestValue = true
+	return out
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+var dealLog = logging.Component("deal_analyzer")
+
+const (
+	// dealHistoryWindow is how far back the analyzer looks for reference prices.
+	dealHistoryWindow = 90 * 24 * time.Hour
+	// dealMinHistory is the number of observations needed to judge against history.
+	dealMinHistory = 3
+	// discountTolerance is the allowed gap, in percentage points, between the
+	// advertised discount and the one implied by original vs sale price.
+	discountTolerance = 10.0
+	// originalHeadroom lets the claimed original price exceed the highest
+	// observed price by this fraction before it is called inflated.
+	originalHeadroom = 0.10
+	// spikeThreshold flags a current price this far above the historical average.
+	spikeThreshold = 0.20
+	// bargainThreshold marks a current price this far below the historical average as great.
+	bargainThreshold = 0.10
+)
+
+// DealAnalyzer judges whether advertised discounts are genuine by comparing
+// the original price, the sale price and stored price history.
+type DealAnalyzer struct {
+	history domain.PriceHistoryRepository
+	now     func() time.Time
+}
+
+// NewDealAnalyzer creates a new DealAnalyzer. history may be nil, in which
+// case only the listing itself is checked.
+func NewDealAnalyzer(history domain.PriceHistoryRepository) *DealAnalyzer {
+	return &DealAnalyzer{
+		history: history,
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// AnnotateAll sets Deal on every product, looking up history in parallel.
+func (a *DealAnalyzer) AnnotateAll(ctx context.Context, products []*domain.Product) {
+	var wg sync.WaitGroup
+	for _, p := range products {
+		if p == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(p *domain.Product) {
+			defer wg.Done()
+			p.Deal = a.Analyze(ctx, p)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// Analyze returns the deal verdict for p. Any red flag makes the deal
+// inflated; a price at or well below the historical norm makes it great;
+// everything else is fair.
+func (a *DealAnalyzer) Analyze(ctx context.Context, p *domain.Product) *domain.Deal {
+	deal := &domain.Deal{Verdict: domain.DealFair, Reasons: []domain.DealReason{}}
+	sale := p.Price.USD
+	original := p.Price.OriginalUSD
+	inflated, great := false, false
+
+	if original > 0 && sale > 0 {
+		if sale > original {
+			inflated = true
+			deal.Reasons = append(deal.Reasons, domain.DealReason{
+				Code:    domain.ReasonSaleAboveOriginal,
+				Message: fmt.Sprintf("Sale price $%.2f is higher than the listed original price $%.2f", sale, original),
+			})
+		} else if p.Discount > 0 {
+			implied := (1 - sale/original) * 100
+			if math.Abs(implied-p.Discount) > discountTolerance {
+				inflated = true
+				deal.Reasons = append(deal.Reasons, domain.DealReason{
+					Code:    domain.ReasonDiscountMismatch,
+					Message: fmt.Sprintf("Advertised %.0f%% discount does not match the prices (actual %.0f%%)", p.Discount, implied),
+				})
+			}
+		}
+	}
+
+	history := a.loadHistory(ctx, p.ID)
+	deal.HistoryCount = len(history)
+	if len(history) < dealMinHistory || sale <= 0 {
+		deal.Reasons = append(deal.Reasons, domain.DealReason{
+			Code:    domain.ReasonInsufficientHistory,
+			Message: "Not enough price history to verify this discount",
+		})
+	} else {
+		lo, hi, sum := history[0].USD, history[0].USD, 0.0
+		for _, o := range history {
+			lo = math.Min(lo, o.USD)
+			hi = math.Max(hi, o.USD)
+			sum += o.USD
+		}
+		avg := sum / float64(len(history))
+		days := int(dealHistoryWindow.Hours() / 24)
+
+		if original > 0 && original > hi*(1+originalHeadroom) {
+			inflated = true
+			deal.Reasons = append(deal.Reasons, domain.DealReason{
+				Code:    domain.ReasonOriginalNeverSeen,
+				Message: fmt.Sprintf("Original price $%.2f was never charged in the last %d days (highest seen $%.2f)", original, days, hi),
+			})
+		}
+		switch {
+		case sale > avg*(1+spikeThreshold):
+			inflated = true
+			deal.Reasons = append(deal.Reasons, domain.DealReason{
+				Code:    domain.ReasonPriceSpike,
+				Message: fmt.Sprintf("Price is %.0f%% above its %d-day average of $%.2f", (sale/avg-1)*100, days, avg),
+			})
+		case sale <= lo*1.02:
+			great = true
+			deal.Reasons = append(deal.Reasons, domain.DealReason{
+				Code:    domain.ReasonLowestPrice,
+				Message: fmt.Sprintf("Lowest price in the last %d days", days),
+			})
+		case sale <= avg*(1-bargainThreshold):
+			great = true
+			deal.Reasons = append(deal.Reasons, domain.DealReason{
+				Code:    domain.ReasonBelowAverage,
+				Message: fmt.Sprintf("Price is %.0f%% below its %d-day average of $%.2f", (1-sale/avg)*100, days, avg),
+			})
+		}
+	}
+
+	switch {
+	case inflated:
+		deal.Verdict = domain.DealInflated
+	case great:
+		deal.Verdict = domain.DealGreat
+	}
+	return deal
+}
+
+func (a *DealAnalyzer) loadHistory(ctx context.Context, productID string) []domain.PriceObservation {
+	if a.history == nil || productID == "" {
+		return nil
+	}
+	obs, err := a.history.List(ctx, productID, a.now().Add(-dealHistoryWindow))
+	if err != nil {
+		dealLog.WarnContext(ctx, "price history lookup failed", "product_id", productID, logging.Err(err))
+		return nil
+	}
+	return obs
+}
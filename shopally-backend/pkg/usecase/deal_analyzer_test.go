@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func reasonCodes(d *domain.Deal) []string {
+	codes := make([]string, 0, len(d.Reasons))
+	for _, r := range d.Reasons {
+		codes = append(codes, r.Code)
+	}
+	return codes
+}
+
+func TestDealAnalyzer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	repo := repository.NewMockPriceHistoryRepository()
+	for i, usd := range []float64{20, 22, 21, 20} {
+		_ = repo.Record(ctx, &domain.PriceObservation{ProductID: "100", USD: usd, ObservedAt: now.Add(-time.Duration(10-i) * 24 * time.Hour)})
+	}
+	a := NewDealAnalyzer(repo)
+	a.now = func() time.Time { return now }
+
+	t.Run("sale above original is inflated", func(t *testing.T) {
+		d := a.Analyze(ctx, &domain.Product{ID: "999", Price: domain.Price{USD: 362, OriginalUSD: 100}, Discount: 50})
+		assert.Equal(t, domain.DealInflated, d.Verdict)
+		assert.Contains(t, reasonCodes(d), domain.ReasonSaleAboveOriginal)
+		assert.Contains(t, reasonCodes(d), domain.ReasonInsufficientHistory)
+	})
+
+	t.Run("mismatched discount is inflated", func(t *testing.T) {
+		d := a.Analyze(ctx, &domain.Product{ID: "999", Price: domain.Price{USD: 45, OriginalUSD: 50}, Discount: 50})
+		assert.Equal(t, domain.DealInflated, d.Verdict)
+		assert.Contains(t, reasonCodes(d), domain.ReasonDiscountMismatch)
+	})
+
+	t.Run("original price never charged is inflated", func(t *testing.T) {
+		d := a.Analyze(ctx, &domain.Product{ID: "100", Price: domain.Price{USD: 20, OriginalUSD: 40}, Discount: 50})
+		assert.Equal(t, domain.DealInflated, d.Verdict)
+		assert.Contains(t, reasonCodes(d), domain.ReasonOriginalNeverSeen)
+		assert.Equal(t, 4, d.HistoryCount)
+	})
+
+	t.Run("sudden spike is inflated", func(t *testing.T) {
+		d := a.Analyze(ctx, &domain.Product{ID: "100", Price: domain.Price{USD: 30}})
+		assert.Equal(t, domain.DealInflated, d.Verdict)
+		assert.Equal(t, []string{domain.ReasonPriceSpike}, reasonCodes(d))
+	})
+
+	t.Run("lowest observed price is great", func(t *testing.T) {
+		d := a.Analyze(ctx, &domain.Product{ID: "100", Price: domain.Price{USD: 18, OriginalUSD: 22.5}, Discount: 20})
+		assert.Equal(t, domain.DealGreat, d.Verdict)
+		assert.Equal(t, []string{domain.ReasonLowestPrice}, reasonCodes(d))
+	})
+
+	t.Run("typical price is fair", func(t *testing.T) {
+		d := a.Analyze(ctx, &domain.Product{ID: "100", Price: domain.Price{USD: 21.5}})
+		assert.Equal(t, domain.DealFair, d.Verdict)
+		assert.Empty(t, d.Reasons)
+	})
+}
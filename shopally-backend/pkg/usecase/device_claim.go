@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+var claimLog = logging.Component("device_claim")
+
+// DeviceClaimUseCase moves the data an anonymous device collected into the
+// account of the user who signed in on it. It is safe to run repeatedly:
+// only data still keyed by the device is touched, and every step leaves the
+// device with less to move.
+type DeviceClaimUseCase struct {
+	alerts     *AlertManager
+	alertOwner domain.AlertOwnerRepository
+	saved      domain.SavedItemRepository
+	now        func() time.Time
+}
+
+// NewDeviceClaimUseCase creates a new DeviceClaimUseCase.
+func NewDeviceClaimUseCase(alerts *AlertManager, alertOwner domain.AlertOwnerRepository, saved domain.SavedItemRepository) *DeviceClaimUseCase {
+	return &DeviceClaimUseCase{alerts: alerts, alertOwner: alertOwner, saved: saved, now: time.Now}
+}
+
+// Claim reassigns the alerts and saved items of deviceID to userID.
+//
+// Conflicts resolve in favour of what the user already has: a device alert
+// on a product the user is already alerted on is deactivated, and saved
+// items pointing at it are repointed to the user's alert. A device saved
+// item for a product the user already saved is dropped after handing its
+// alert to the user's copy if that copy has none.
+func (uc *DeviceClaimUseCase) Claim(ctx context.Context, userID, deviceID string) (*domain.ClaimResult, error) {
+	if userID == "" || deviceID == "" {
+		return nil, ErrMissingOwner
+	}
+	var res domain.ClaimResult
+	surviving, err := uc.claimAlerts(ctx, userID, deviceID, &res)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.claimSavedItems(ctx, userID, deviceID, surviving, &res); err != nil {
+		return nil, err
+	}
+	if res != (domain.ClaimResult{}) {
+		claimLog.InfoContext(ctx, "device claimed", "device_id", deviceID, "user_id", userID, "result", res)
+	}
+	return &res, nil
+}
+
+// claimAlerts moves the device's alerts to the user and returns the user's
+// active alert on each product.
+func (uc *DeviceClaimUseCase) claimAlerts(ctx context.Context, userID, deviceID string, res *domain.ClaimResult) (map[string]string, error) {
+	owned, err := uc.alertOwner.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// surviving maps each product to the user's alert on it.
+	surviving := make(map[string]string, len(owned))
+	for _, a := range owned {
+		surviving[a.ProductID] = a.ID
+	}
+
+	unclaimed, err := uc.alertOwner.ListUnclaimed(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range unclaimed {
+		if _, ok := surviving[a.ProductID]; ok {
+			if err := uc.alerts.DeleteAlert(a.ID); err != nil {
+				return nil, err
+			}
+			res.AlertsMerged++
+			continue
+		}
+		if err := uc.alertOwner.AssignUser(ctx, a.ID, userID); err != nil {
+			return nil, err
+		}
+		surviving[a.ProductID] = a.ID
+		res.AlertsClaimed++
+	}
+	return surviving, nil
+}
+
+// claimSavedItems moves the device's saved items to the user. An item whose
+// alert is not among the user's surviving alerts is repointed to the user's
+// alert on its product before it moves, so a claim interrupted after
+// claimAlerts still repoints it when retried.
+func (uc *DeviceClaimUseCase) claimSavedItems(ctx context.Context, userID, deviceID string, surviving map[string]string, res *domain.ClaimResult) error {
+	from := domain.Owner{DeviceID: deviceID}.Key()
+	to := domain.Owner{UserID: userID}.Key()
+	items, err := uc.saved.ListAll(ctx, from)
+	if err != nil {
+		return err
+	}
+	kept := make(map[string]bool, len(surviving))
+	for _, id := range surviving {
+		kept[id] = true
+	}
+	for i := range items {
+		item := &items[i]
+		if id, ok := surviving[item.ProductID]; ok && item.AlertID != "" && !kept[item.AlertID] {
+			item.AlertID = id
+			item.UpdatedAt = uc.now().UTC()
+			if err := uc.saved.Update(ctx, item); err != nil {
+				return err
+			}
+		}
+		err := uc.saved.Reassign(ctx, item.ID, from, to)
+		switch {
+		case err == nil:
+			res.SavedItemsClaimed++
+		case errors.Is(err, domain.ErrAlreadySaved):
+			if err := uc.mergeSavedItem(ctx, item, to); err != nil {
+				return err
+			}
+			res.SavedItemsMerged++
+		case errors.Is(err, domain.ErrSavedItemNotFound):
+			// Claimed concurrently.
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *DeviceClaimUseCase) mergeSavedItem(ctx context.Context, item *domain.SavedItem, to string) error {
+	if item.AlertID != "" {
+		kept, err := uc.saved.GetByProduct(ctx, to, item.ProductID)
+		if err != nil {
+			return err
+		}
+		if kept.AlertID == "" {
+			kept.AlertID = item.AlertID
+			kept.UpdatedAt = uc.now().UTC()
+			if err := uc.saved.Update(ctx, kept); err != nil {
+				return err
+			}
+		}
+	}
+	err := uc.saved.Delete(ctx, item.Owner, item.ID)
+	if errors.Is(err, domain.ErrSavedItemNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceClaimUseCase(t *testing.T) {
+	ctx := context.Background()
+	alertRepo := repository.NewMockAlertRepository()
+	alerts := NewAlertManager(alertRepo)
+	savedRepo := repository.NewMockSavedItemRepository()
+	book := &priceBook{prices: map[string]float64{"1": 10, "2": 20, "3": 30}}
+	saved := NewSavedItemsUseCase(savedRepo, book, alerts)
+	claims := NewDeviceClaimUseCase(alerts, alertRepo, savedRepo)
+
+	user := domain.Owner{UserID: "user-1", DeviceID: "phone-a"}
+	device := domain.Owner{DeviceID: "phone-b"}
+
+	// The account already has product 1 saved and alerted from phone A.
+	_, _, err := saved.Save(ctx, user, "1", "")
+	require.NoError(t, err)
+	userAlert := &domain.Alert{DeviceID: "phone-a", UserID: "user-1", ProductID: "1", CurrentPrice: 10}
+	require.NoError(t, alerts.CreateAlert(userAlert))
+
+	// Phone B collected overlapping data anonymously.
+	dup, _, err := saved.Save(ctx, device, "1", "wishlist")
+	require.NoError(t, err)
+	dupAlert, err := saved.CreateAlert(ctx, device, dup.ID, "phone-b")
+	require.NoError(t, err)
+	_, _, err = saved.Save(ctx, device, "2", "wishlist")
+	require.NoError(t, err)
+	require.NoError(t, alerts.CreateAlert(&domain.Alert{DeviceID: "phone-b", ProductID: "3", CurrentPrice: 30}))
+
+	res, err := claims.Claim(ctx, "user-1", "phone-b")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClaimResult{AlertsClaimed: 1, AlertsMerged: 1, SavedItemsClaimed: 1, SavedItemsMerged: 1}, *res)
+
+	// The duplicate alert on product 1 was deactivated; product 3's moved.
+	got, err := alerts.GetAlert(dupAlert.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsActive)
+	owned, err := alertRepo.ListByUser(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Len(t, owned, 2)
+	for _, a := range owned {
+		if a.ProductID == "3" {
+			assert.Equal(t, "phone-b", a.DeviceID, "pushes still go to the device that created the alert")
+		}
+	}
+
+	// The account keeps its own copy of product 1, alerted by the account's
+	// alert rather than the deactivated duplicate.
+	page, err := saved.List(ctx, user, domain.SavedItemQuery{Sort: domain.SavedSortPriceAsc})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, savedProductIDs(page.Items))
+	assert.Equal(t, domain.DefaultSavedList, page.Items[0].List)
+	assert.Equal(t, userAlert.ID, page.Items[0].AlertID)
+	assert.Equal(t, "wishlist", page.Items[1].List)
+
+	page, err = saved.List(ctx, device, domain.SavedItemQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+
+	// Claiming again changes nothing.
+	res, err = claims.Claim(ctx, "user-1", "phone-b")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClaimResult{}, *res)
+
+	_, err = claims.Claim(ctx, "user-1", "")
+	assert.ErrorIs(t, err, ErrMissingOwner)
+}
+
+func TestDeviceClaimUseCase_RepointsMergedAlerts(t *testing.T) {
+	ctx := context.Background()
+	alertRepo := repository.NewMockAlertRepository()
+	alerts := NewAlertManager(alertRepo)
+	savedRepo := repository.NewMockSavedItemRepository()
+	book := &priceBook{prices: map[string]float64{"1": 10}}
+	saved := NewSavedItemsUseCase(savedRepo, book, alerts)
+	claims := NewDeviceClaimUseCase(alerts, alertRepo, savedRepo)
+
+	// The account is alerted on product 1 but has not saved it.
+	userAlert := &domain.Alert{DeviceID: "phone-a", UserID: "user-1", ProductID: "1", CurrentPrice: 10}
+	require.NoError(t, alerts.CreateAlert(userAlert))
+
+	// Phone B saved product 1 with its own alert.
+	device := domain.Owner{DeviceID: "phone-b"}
+	item, _, err := saved.Save(ctx, device, "1", "")
+	require.NoError(t, err)
+	deviceAlert, err := saved.CreateAlert(ctx, device, item.ID, "phone-b")
+	require.NoError(t, err)
+
+	res, err := claims.Claim(ctx, "user-1", "phone-b")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClaimResult{AlertsMerged: 1, SavedItemsClaimed: 1}, *res)
+
+	got, err := alerts.GetAlert(deviceAlert.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsActive)
+
+	page, err := saved.List(ctx, domain.Owner{UserID: "user-1"}, domain.SavedItemQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, userAlert.ID, page.Items[0].AlertID, "the saved item follows the surviving alert")
+}
+
+// failingReassign fails the next Reassign, like a claim cut short.
+type failingReassign struct {
+	*repository.MockSavedItemRepository
+	fail bool
+}
+
+func (r *failingReassign) Reassign(ctx context.Context, id, from, to string) error {
+	if r.fail {
+		r.fail = false
+		return errors.New("connection reset")
+	}
+	return r.MockSavedItemRepository.Reassign(ctx, id, from, to)
+}
+
+func TestDeviceClaimUseCase_RetryAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	alertRepo := repository.NewMockAlertRepository()
+	alerts := NewAlertManager(alertRepo)
+	savedRepo := &failingReassign{MockSavedItemRepository: repository.NewMockSavedItemRepository()}
+	book := &priceBook{prices: map[string]float64{"1": 10}}
+	saved := NewSavedItemsUseCase(savedRepo, book, alerts)
+	claims := NewDeviceClaimUseCase(alerts, alertRepo, savedRepo)
+
+	userAlert := &domain.Alert{DeviceID: "phone-a", UserID: "user-1", ProductID: "1", CurrentPrice: 10}
+	require.NoError(t, alerts.CreateAlert(userAlert))
+	device := domain.Owner{DeviceID: "phone-b"}
+	item, _, err := saved.Save(ctx, device, "1", "")
+	require.NoError(t, err)
+	_, err = saved.CreateAlert(ctx, device, item.ID, "phone-b")
+	require.NoError(t, err)
+
+	// The first attempt deactivates the device alert, then fails.
+	savedRepo.fail = true
+	_, err = claims.Claim(ctx, "user-1", "phone-b")
+	require.Error(t, err)
+
+	res, err := claims.Claim(ctx, "user-1", "phone-b")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClaimResult{SavedItemsClaimed: 1}, *res)
+
+	page, err := saved.List(ctx, domain.Owner{UserID: "user-1"}, domain.SavedItemQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, userAlert.ID, page.Items[0].AlertID, "the retry repoints to the surviving alert")
+}
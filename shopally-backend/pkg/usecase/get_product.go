@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/tracing"
+	"github.com/shopally-ai/pkg/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var productLog = logging.Component("product")
+
+// DefaultProductCacheTTL is how long product details are cached.
+const DefaultProductCacheTTL = 30 * time.Minute
+
+// GetProductUseCase fetches a single product by ID, caches the upstream
+// result and prices it in ETB.
+type GetProductUseCase struct {
+	alibabaGateway domain.AlibabaGateway
+	fxClient       domain.IFXClient
+	cache          domain.ICachePort
+	ttl            time.Duration
+	priceHistory   *PriceHistoryUseCase
+	dealAnalyzer   *DealAnalyzer
+	landedCost     *LandedCostCalculator
+}
+
+// NewGetProductUseCase creates a new GetProductUseCase. fx and cache are optional.
+func NewGetProductUseCase(ag domain.AlibabaGateway, fx domain.IFXClient, cache domain.ICachePort, ttl time.Duration) *GetProductUseCase {
+	if ttl <= 0 {
+		ttl = DefaultProductCacheTTL
+	}
+	return &GetProductUseCase{
+		alibabaGateway: ag,
+		fxClient:       fx,
+		cache:          cache,
+		ttl:            ttl,
+	}
+}
+
+// WithPriceHistory records the price of every product lookup.
+func (uc *GetProductUseCase) WithPriceHistory(ph *PriceHistoryUseCase) *GetProductUseCase {
+	uc.priceHistory = ph
+	return uc
+}
+
+// WithDealAnalyzer attaches a deal verdict to every product lookup.
+func (uc *GetProductUseCase) WithDealAnalyzer(da *DealAnalyzer) *GetProductUseCase {
+	uc.dealAnalyzer = da
+	return uc
+}
+
+// WithLandedCost attaches an itemized landed cost to every product lookup.
+func (uc *GetProductUseCase) WithLandedCost(lc *LandedCostCalculator) *GetProductUseCase {
+	uc.landedCost = lc
+	return uc
+}
+
+// productCacheKey is per delivery country, as upstream prices and
+// availability depend on it.
+func productCacheKey(country, id string) string {
+	return "product:" + country + ":" + id
+}
+
+// Execute returns the product with the given ID or domain.ErrProductNotFound.
+// The cached copy holds upstream data only; ETB pricing is applied on every
+// call so it follows the current FX rate.
+func (uc *GetProductUseCase) Execute(ctx context.Context, productID string) (_ *domain.Product, err error) {
+	ctx, span := tracer.Start(ctx, "product.get", trace.WithAttributes(attribute.String("product.id", productID)))
+	defer func() { tracing.End(span, err) }()
+
+	p := uc.fromCache(ctx, productID)
+	span.SetAttributes(attribute.Bool("cache.hit", p != nil))
+	if p == nil {
+		fetched, err := uc.alibabaGateway.FetchProductDetail(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		if fetched == nil {
+			return nil, domain.ErrProductNotFound
+		}
+		p = fetched
+		uc.toCache(ctx, p)
+	}
+
+	if err := applyETBPricing(ctx, uc.fxClient, []*domain.Product{p}); err != nil {
+		productLog.WarnContext(ctx, "ETB pricing failed", "product_id", productID, logging.Err(err))
+	}
+	if uc.dealAnalyzer != nil {
+		p.Deal = uc.dealAnalyzer.Analyze(ctx, p)
+	}
+	if uc.landedCost != nil {
+		uc.landedCost.AnnotateAll(ctx, []*domain.Product{p})
+	}
+	if uc.priceHistory != nil {
+		uc.priceHistory.RecordAsync(ctx, []*domain.Product{p}, domain.PriceSourceDetail)
+	}
+	return p, nil
+}
+
+func (uc *GetProductUseCase) fromCache(ctx context.Context, productID string) *domain.Product {
+	if uc.cache == nil {
+		return nil
+	}
+	val, ok, err := uc.cache.Get(ctx, productCacheKey(shipTo(ctx), productID))
+	if err != nil || !ok {
+		return nil
+	}
+	var p domain.Product
+	if err := json.Unmarshal([]byte(val), &p); err != nil {
+		return nil
+	}
+	return &p
+}
+
+func (uc *GetProductUseCase) toCache(ctx context.Context, p *domain.Product) {
+	if uc.cache == nil {
+		return
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+	if err := uc.cache.Set(ctx, productCacheKey(shipTo(ctx), p.ID), string(b), uc.ttl); err != nil {
+		productLog.WarnContext(ctx, "product cache set failed", "product_id", p.ID, logging.Err(err))
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// detailGateway serves product details from a map and counts upstream calls.
+type detailGateway struct {
+	mu       sync.Mutex
+	products map[string]*domain.Product
+	calls    int
+}
+
+func (g *detailGateway) FetchProducts(ctx context.Context, query string, filters map[string]interface{}) ([]*domain.Product, error) {
+	return nil, nil
+}
+
+func (g *detailGateway) FetchProductDetail(ctx context.Context, productID string) (*domain.Product, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls++
+	if p, ok := g.products[productID]; ok {
+		cp := *p
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+// memoryCache is an in-memory domain.ICachePort.
+type memoryCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMemoryCache() *memoryCache { return &memoryCache{data: map[string]string{}} }
+
+func (m *memoryCache) Get(ctx context.Context, key string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[key]
+	return v, ok, nil
+}
+
+func (m *memoryCache) Set(ctx context.Context, key, val string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = val
+	return nil
+}
+
+// fixedFX returns a constant rate.
+type fixedFX float64
+
+func (f fixedFX) GetRate(ctx context.Context, from, to string) (float64, error) {
+	return float64(f), nil
+}
+
+func TestGetProductUseCase(t *testing.T) {
+	ag := &detailGateway{products: map[string]*domain.Product{
+		"100": {ID: "100", Title: "Earbuds", Price: domain.Price{USD: 10}},
+	}}
+	cache := newMemoryCache()
+	uc := NewGetProductUseCase(ag, fixedFX(150), cache, time.Minute)
+
+	p, err := uc.Execute(context.Background(), "100")
+	require.NoError(t, err)
+	assert.InDelta(t, 1500, p.Price.ETB, 1e-9)
+	assert.Contains(t, cache.data, "product:"+domain.DefaultShipToCountry+":100")
+
+	p, err = uc.Execute(context.Background(), "100")
+	require.NoError(t, err)
+	assert.Equal(t, "Earbuds", p.Title)
+	assert.InDelta(t, 1500, p.Price.ETB, 1e-9)
+	assert.Equal(t, 1, ag.calls, "second lookup should be served from cache")
+
+	_, err = uc.Execute(context.Background(), "404")
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	// Another delivery country is priced upstream separately.
+	us := context.WithValue(context.Background(), contextkeys.Preferences, &domain.Preferences{ShipToCountry: "US"})
+	_, err = uc.Execute(us, "100")
+	require.NoError(t, err)
+	assert.Equal(t, 3, ag.calls)
+	assert.Contains(t, cache.data, "product:US:100")
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+var landedCostLog = logging.Component("landed_cost")
+
+// DefaultTariffTable is an estimate of Ethiopian import taxes on small
+// parcels. Deployments can override it with a JSON table (see
+// config.LoadTariffTable).
+var DefaultTariffTable = domain.TariffTable{
+	VATRate:         0.15,
+	SurtaxRate:      0.10,
+	WithholdingRate: 0.03,
+	Default:         domain.TariffCategory{Key: "general", DutyRate: 0.20, ShippingUSD: 6},
+	Categories: []domain.TariffCategory{
+		{Key: "phones", Keywords: []string{"phone", "telecommunication"}, DutyRate: 0.05, ShippingUSD: 8},
+		{Key: "electronics", Keywords: []string{"electronic", "computer", "office", "camera"}, DutyRate: 0.10, ShippingUSD: 8},
+		{Key: "apparel", Keywords: []string{"clothing", "apparel", "shoes", "bags", "wig"}, DutyRate: 0.35, ShippingUSD: 5},
+		{Key: "jewelry", Keywords: []string{"jewelry", "watches"}, DutyRate: 0.30, ExciseRate: 0.20, ShippingUSD: 4},
+		{Key: "beauty", Keywords: []string{"beauty", "health", "cosmetic", "perfume"}, DutyRate: 0.30, ExciseRate: 0.30, ShippingUSD: 5},
+		{Key: "automotive", Keywords: []string{"automobile", "motorcycle"}, DutyRate: 0.35, ShippingUSD: 15},
+		{Key: "home", Keywords: []string{"home", "furniture", "kitchen", "garden", "tools"}, DutyRate: 0.30, ShippingUSD: 10},
+	},
+}
+
+// LandedCostCalculator estimates what a buyer in Ethiopia pays for a product:
+// sale price, seller tax, shipping, and customs duty, excise, VAT, surtax and
+// withholding tax, each computed on the cumulative customs value.
+type LandedCostCalculator struct {
+	table    domain.TariffTable
+	fxClient domain.IFXClient
+}
+
+// NewLandedCostCalculator creates a calculator for the given tariff table.
+// fx is optional; without it only USD amounts are filled.
+func NewLandedCostCalculator(table domain.TariffTable, fx domain.IFXClient) *LandedCostCalculator {
+	return &LandedCostCalculator{table: table, fxClient: fx}
+}
+
+// AnnotateAll sets LandedCost on every product using one FX lookup.
+func (c *LandedCostCalculator) AnnotateAll(ctx context.Context, products []*domain.Product) {
+	rate := c.rate(ctx)
+	for _, p := range products {
+		if p != nil && p.Price.USD > 0 {
+			p.LandedCost = c.Calculate(p, rate)
+		}
+	}
+}
+
+func (c *LandedCostCalculator) rate(ctx context.Context) float64 {
+	if c.fxClient == nil {
+		return 0
+	}
+	rate, err := c.fxClient.GetRate(ctx, "USD", "ETB")
+	if err != nil {
+		landedCostLog.WarnContext(ctx, "FX lookup failed, using USD only", logging.Err(err))
+		return 0
+	}
+	return rate
+}
+
+// Calculate returns the itemized landed cost of p. A zero rate leaves ETB
+// amounts empty.
+func (c *LandedCostCalculator) Calculate(p *domain.Product, rate float64) *domain.LandedCost {
+	cat := c.categoryFor(p)
+	price := p.Price.USD
+	sellerTax := price * fractionRate(p.TaxRate)
+	// Customs value (CIF): what was paid to the seller plus freight.
+	cif := price + sellerTax + cat.ShippingUSD
+	duty := cif * cat.DutyRate
+	excise := (cif + duty) * cat.ExciseRate
+	taxBase := cif + duty + excise
+
+	lc := &domain.LandedCost{TariffCategory: cat.Key, FXRate: rate}
+	add := func(code string, r, usd float64) {
+		if usd <= 0 && code != domain.CostItem {
+			return
+		}
+		line := domain.LandedCostLine{Code: code, Rate: r, USD: roundTo(usd, 2), ETB: roundTo(usd*rate, 2)}
+		lc.Lines = append(lc.Lines, line)
+		lc.TotalUSD += line.USD
+		lc.TotalETB += line.ETB
+	}
+	add(domain.CostItem, 0, price)
+	add(domain.CostSellerTax, fractionRate(p.TaxRate), sellerTax)
+	add(domain.CostShipping, 0, cat.ShippingUSD)
+	add(domain.CostDuty, cat.DutyRate, duty)
+	add(domain.CostExcise, cat.ExciseRate, excise)
+	add(domain.CostVAT, c.table.VATRate, taxBase*c.table.VATRate)
+	add(domain.CostSurtax, c.table.SurtaxRate, taxBase*c.table.SurtaxRate)
+	add(domain.CostWithholding, c.table.WithholdingRate, cif*c.table.WithholdingRate)
+
+	lc.TotalUSD = roundTo(lc.TotalUSD, 2)
+	lc.TotalETB = roundTo(lc.TotalETB, 2)
+	return lc
+}
+
+// categoryFor matches the product's subcategory and category names against
+// the tariff keywords, most specific first.
+func (c *LandedCostCalculator) categoryFor(p *domain.Product) domain.TariffCategory {
+	for _, name := range []string{p.SubCategory.Name, p.Category.Name} {
+		name = strings.ToLower(name)
+		if name == "" {
+			continue
+		}
+		for _, cat := range c.table.Categories {
+			for _, kw := range cat.Keywords {
+				if strings.Contains(name, strings.ToLower(kw)) {
+					return cat
+				}
+			}
+		}
+	}
+	return c.table.Default
+}
+
+// fractionRate accepts rates given either as a fraction (0.1) or a
+// percentage (10).
+func fractionRate(r float64) float64 {
+	if r <= 0 {
+		return 0
+	}
+	if r > 1 {
+		return r / 100
+	}
+	return r
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLandedCostCalculator_Calculate(t *testing.T) {
+	calc := NewLandedCostCalculator(DefaultTariffTable, nil)
+
+	t.Run("itemizes duty and taxes on the cumulative customs value", func(t *testing.T) {
+		p := &domain.Product{
+			Price:    domain.Price{USD: 100},
+			Category: domain.Category{Name: "Cellphones & Telecommunications"},
+		}
+		lc := calc.Calculate(p, 100)
+
+		assert.Equal(t, "phones", lc.TariffCategory)
+		var codes []string
+		for _, l := range lc.Lines {
+			codes = append(codes, l.Code)
+		}
+		assert.Equal(t, []string{domain.CostItem, domain.CostShipping, domain.CostDuty, domain.CostVAT, domain.CostSurtax, domain.CostWithholding}, codes)
+		// CIF 108, duty 5.40, VAT 15% and surtax 10% of 113.40, withholding 3% of CIF.
+		assert.InDelta(t, 5.40, lc.Lines[2].USD, 1e-9)
+		assert.InDelta(t, 17.01, lc.Lines[3].USD, 1e-9)
+		assert.InDelta(t, 11.34, lc.Lines[4].USD, 1e-9)
+		assert.InDelta(t, 3.24, lc.Lines[5].USD, 1e-9)
+		assert.InDelta(t, 144.99, lc.TotalUSD, 1e-9)
+		assert.InDelta(t, 14499, lc.TotalETB, 1e-9)
+	})
+
+	t.Run("applies seller tax and excise and falls back to the default category", func(t *testing.T) {
+		beauty := calc.Calculate(&domain.Product{Price: domain.Price{USD: 10}, TaxRate: 10, SubCategory: domain.Category{Name: "Perfume"}}, 0)
+		assert.Equal(t, "beauty", beauty.TariffCategory)
+		assert.Equal(t, domain.CostSellerTax, beauty.Lines[1].Code)
+		assert.InDelta(t, 1.0, beauty.Lines[1].USD, 1e-9)
+		assert.Equal(t, domain.CostExcise, beauty.Lines[4].Code)
+		assert.Zero(t, beauty.TotalETB)
+
+		other := calc.Calculate(&domain.Product{Price: domain.Price{USD: 10}}, 0)
+		assert.Equal(t, "general", other.TariffCategory)
+	})
+}
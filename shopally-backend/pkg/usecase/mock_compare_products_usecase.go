@@ -14,7 +14,14 @@ type MockCompareProductsUseCase struct {
 
 var _ CompareProductsExecutor = (*MockCompareProductsUseCase)(nil)
 
-func (m *MockCompareProductsUseCase) Execute(ctx context.Context, products []*domain.Product) (interface{}, error) {
-	args := m.Called(ctx, products)
-	return args.Get(0), args.Error(1)
+func (m *MockCompareProductsUseCase) Execute(ctx context.Context, products []*domain.Product, mode string) (*domain.ComparisonResult, error) {
+	args := m.Called(ctx, products, mode)
+	result, _ := args.Get(0).(*domain.ComparisonResult)
+	return result, args.Error(1)
+}
+
+func (m *MockCompareProductsUseCase) Get(ctx context.Context, id string) (*domain.ComparisonResult, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*domain.ComparisonResult)
+	return result, args.Error(1)
 }
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProductsResolver is a testify-based mock for testing.
+type MockProductsResolver struct {
+	mock.Mock
+}
+
+var _ ProductsResolver = (*MockProductsResolver)(nil)
+
+func (m *MockProductsResolver) Resolve(ctx context.Context, refs []domain.ProductRef) ([]*domain.Product, error) {
+	args := m.Called(ctx, refs)
+	products, _ := args.Get(0).([]*domain.Product)
+	return products, args.Error(1)
+}
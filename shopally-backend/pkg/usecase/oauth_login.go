@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// ErrOAuthEmailUnverified is returned when a provider account without a
+// verified email would need a new local account.
+var ErrOAuthEmailUnverified = domain.Forbidden("the provider account has no verified email")
+
+// ErrInvalidHandoffCode is returned for an unknown, expired or already
+// redeemed sign-in hand-off code.
+var ErrInvalidHandoffCode = domain.Unauthorized("sign-in code is invalid or expired").WithCode("INVALID_CODE")
+
+// DefaultOAuthHandoffTTL is how long a hand-off code can be redeemed.
+const DefaultOAuthHandoffTTL = time.Minute
+
+// OAuthFlow holds what the caller keeps between starting a sign-in and the
+// provider's callback. State and Verifier must not leave the caller's
+// browser session.
+type OAuthFlow struct {
+	URL      string
+	State    string
+	Verifier string
+}
+
+// OAuthLoginUseCase signs users in through an external OAuth provider and
+// issues our own session tokens for the linked local account.
+type OAuthLoginUseCase struct {
+	provider domain.OAuthProvider
+	users    domain.UserRepository
+	auth     *AuthUseCase
+
+	handoffs   domain.OneTimeStore
+	handoffTTL time.Duration
+}
+
+// signedIn is what a hand-off code redeems for.
+type signedIn struct {
+	User   *domain.User       `json:"user"`
+	Tokens *domain.AuthTokens `json:"tokens"`
+}
+
+// NewOAuthLoginUseCase creates a new OAuthLoginUseCase.
+func NewOAuthLoginUseCase(provider domain.OAuthProvider, users domain.UserRepository, auth *AuthUseCase) *OAuthLoginUseCase {
+	return &OAuthLoginUseCase{provider: provider, users: users, auth: auth}
+}
+
+// WithHandoff enables HandOff and Redeem, so the provider's callback can
+// send the browser back to the web app with a one-time code instead of the
+// tokens themselves.
+func (uc *OAuthLoginUseCase) WithHandoff(store domain.OneTimeStore, ttl time.Duration) *OAuthLoginUseCase {
+	if ttl <= 0 {
+		ttl = DefaultOAuthHandoffTTL
+	}
+	uc.handoffs = store
+	uc.handoffTTL = ttl
+	return uc
+}
+
+// HandOff stores a signed-in user's tokens under a new one-time code.
+func (uc *OAuthLoginUseCase) HandOff(ctx context.Context, user *domain.User, tokens *domain.AuthTokens) (string, error) {
+	if uc.handoffs == nil {
+		return "", errors.New("oauth hand-off is not configured")
+	}
+	code, err := randomURLToken(24)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(signedIn{User: user, Tokens: tokens})
+	if err != nil {
+		return "", err
+	}
+	if err := uc.handoffs.Set(ctx, handoffKey(code), string(b), uc.handoffTTL); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// Redeem returns the user and tokens of a hand-off code, at most once.
+func (uc *OAuthLoginUseCase) Redeem(ctx context.Context, code string) (*domain.User, *domain.AuthTokens, error) {
+	if uc.handoffs == nil || code == "" {
+		return nil, nil, ErrInvalidHandoffCode
+	}
+	val, ok, err := uc.handoffs.Take(ctx, handoffKey(code))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, ErrInvalidHandoffCode
+	}
+	var s signedIn
+	if err := json.Unmarshal([]byte(val), &s); err != nil {
+		return nil, nil, err
+	}
+	return s.User, s.Tokens, nil
+}
+
+func handoffKey(code string) string {
+	return "oauth:handoff:" + code
+}
+
+// Start generates a fresh state and PKCE verifier and returns the consent URL.
+func (uc *OAuthLoginUseCase) Start() (*OAuthFlow, error) {
+	state, err := randomURLToken(24)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomURLToken(32)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthFlow{URL: uc.provider.AuthCodeURL(state, verifier), State: state, Verifier: verifier}, nil
+}
+
+// Complete redeems the authorization code and signs in the linked user. An
+// unknown provider account is linked to the local account with the same
+// verified email, or gets a new password-less account.
+func (uc *OAuthLoginUseCase) Complete(ctx context.Context, code, verifier string) (*domain.User, *domain.AuthTokens, error) {
+	identity, err := uc.provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := uc.linkedUser(ctx, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokens, err := uc.auth.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+func (uc *OAuthLoginUseCase) linkedUser(ctx context.Context, identity *domain.OAuthIdentity) (*domain.User, error) {
+	user, err := uc.users.GetByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+
+	// Only a verified email may claim an existing account or a new one.
+	email, err := normalizeEmail(identity.Email)
+	if err != nil || !identity.EmailVerified {
+		return nil, ErrOAuthEmailUnverified
+	}
+	now := uc.auth.now().UTC()
+	link := domain.Identity{Provider: identity.Provider, Subject: identity.Subject, Email: email, LinkedAt: now}
+
+	user, err = uc.users.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if err := uc.users.AddIdentity(ctx, user.ID, link); err != nil {
+			return nil, err
+		}
+		user.Identities = append(user.Identities, link)
+		return user, nil
+	case !errors.Is(err, domain.ErrUserNotFound):
+		return nil, err
+	}
+
+	user = &domain.User{
+		ID:         uuid.New().String(),
+		Email:      email,
+		Name:       strings.TrimSpace(identity.Name),
+		Identities: []domain.Identity{link},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := uc.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// randomURLToken returns n random bytes encoded as unpadded base64url, which
+// fits both OAuth state and the PKCE verifier alphabet.
+func randomURLToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
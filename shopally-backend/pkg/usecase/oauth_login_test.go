@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubOAuthProvider signs in a fixed account for any code.
+type stubOAuthProvider struct {
+	identity domain.OAuthIdentity
+	verifier string
+}
+
+func (p *stubOAuthProvider) AuthCodeURL(state, verifier string) string {
+	return "https://provider.test/consent?state=" + state
+}
+
+func (p *stubOAuthProvider) Exchange(ctx context.Context, code, verifier string) (*domain.OAuthIdentity, error) {
+	p.verifier = verifier
+	id := p.identity
+	return &id, nil
+}
+
+// memoryOneTimeStore is an in-process domain.OneTimeStore.
+type memoryOneTimeStore struct {
+	mu   sync.Mutex
+	vals map[string]string
+}
+
+func (s *memoryOneTimeStore) Set(ctx context.Context, key, val string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.vals == nil {
+		s.vals = map[string]string{}
+	}
+	s.vals[key] = val
+	return nil
+}
+
+func (s *memoryOneTimeStore) Take(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	val, ok := s.vals[key]
+	delete(s.vals, key)
+	return val, ok, nil
+}
+
+func TestOAuthLoginUseCase_Start(t *testing.T) {
+	uc := NewOAuthLoginUseCase(&stubOAuthProvider{}, nil, newTestAuth())
+	a, err := uc.Start()
+	require.NoError(t, err)
+	b, err := uc.Start()
+	require.NoError(t, err)
+	assert.NotEqual(t, a.State, b.State)
+	assert.NotEqual(t, a.Verifier, b.Verifier)
+	assert.Len(t, a.Verifier, 43, "RFC 7636 requires at least 43 characters")
+	assert.Contains(t, a.URL, a.State)
+}
+
+func TestOAuthLoginUseCase_LinksIdentity(t *testing.T) {
+	ctx := context.Background()
+	auth := newTestAuth()
+	provider := &stubOAuthProvider{identity: domain.OAuthIdentity{
+		Provider: domain.IdentityProviderGoogle, Subject: "g-1", Email: "Abebe@Example.com", EmailVerified: true, Name: "Abebe",
+	}}
+	uc := NewOAuthLoginUseCase(provider, auth.users, auth)
+
+	// A password account with the same verified email gets the identity linked.
+	registered, _, err := auth.Register(ctx, "abebe@example.com", "correct horse", "")
+	require.NoError(t, err)
+	user, tokens, err := uc.Complete(ctx, "code", "verifier-1")
+	require.NoError(t, err)
+	assert.Equal(t, "verifier-1", provider.verifier)
+	assert.Equal(t, registered.ID, user.ID)
+	require.Len(t, user.Identities, 1)
+	current, err := auth.Authenticate(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, registered.ID, current.ID)
+
+	// Signing in again finds the account by identity, not by email.
+	provider.identity.Email = "changed@example.com"
+	again, _, err := uc.Complete(ctx, "code", "verifier-2")
+	require.NoError(t, err)
+	assert.Equal(t, registered.ID, again.ID)
+	stored, err := auth.Me(ctx, registered.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Identities, 1)
+
+	// An unknown account with a fresh email gets a password-less account.
+	provider.identity = domain.OAuthIdentity{Provider: domain.IdentityProviderGoogle, Subject: "g-2", Email: "new@example.com", EmailVerified: true}
+	created, _, err := uc.Complete(ctx, "code", "verifier-3")
+	require.NoError(t, err)
+	assert.NotEqual(t, registered.ID, created.ID)
+	assert.Empty(t, created.PasswordHash)
+	_, _, err = auth.Login(ctx, "new@example.com", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// An unverified email can neither claim an account nor create one.
+	provider.identity = domain.OAuthIdentity{Provider: domain.IdentityProviderGoogle, Subject: "g-3", Email: "abebe@example.com"}
+	_, _, err = uc.Complete(ctx, "code", "verifier-4")
+	assert.ErrorIs(t, err, ErrOAuthEmailUnverified)
+}
+
+func TestOAuthLoginUseCase_HandOff(t *testing.T) {
+	ctx := context.Background()
+	auth := newTestAuth()
+	provider := &stubOAuthProvider{identity: domain.OAuthIdentity{
+		Provider: domain.IdentityProviderGoogle, Subject: "g-1", Email: "a@example.com", EmailVerified: true,
+	}}
+	uc := NewOAuthLoginUseCase(provider, auth.users, auth).WithHandoff(&memoryOneTimeStore{}, 0)
+
+	user, tokens, err := uc.Complete(ctx, "code", "verifier")
+	require.NoError(t, err)
+	code, err := uc.HandOff(ctx, user, tokens)
+	require.NoError(t, err)
+
+	redeemed, got, err := uc.Redeem(ctx, code)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, redeemed.ID)
+	assert.Equal(t, tokens.AccessToken, got.AccessToken)
+	assert.Equal(t, tokens.RefreshToken, got.RefreshToken)
+
+	// A code is good for one redemption only.
+	_, _, err = uc.Redeem(ctx, code)
+	assert.ErrorIs(t, err, ErrInvalidHandoffCode)
+	_, _, err = uc.Redeem(ctx, "made-up")
+	assert.ErrorIs(t, err, ErrInvalidHandoffCode)
+}
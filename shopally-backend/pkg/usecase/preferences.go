@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+var preferencesLog = logging.Component("preferences")
+
+// DefaultPreferencesCacheTTL is how long resolved preferences are cached.
+// Preferences are read on every request, so updates overwrite the entry.
+const DefaultPreferencesCacheTTL = 10 * time.Minute
+
+// ErrInvalidPreferences is returned when an update has an unsupported value.
+var ErrInvalidPreferences = domain.Validation("invalid preferences")
+
+var (
+	supportedLanguages  = map[string]bool{"en": true, "am": true}
+	supportedCurrencies = map[string]bool{"USD": true, "ETB": true}
+	supportedChannels   = map[string]bool{
+		domain.NotificationChannelPush:  true,
+		domain.NotificationChannelEmail: true,
+	}
+)
+
+// PreferencesUseCase stores the preferences of a user or device and resolves
+// the ones that apply to a request.
+type PreferencesUseCase struct {
+	repo     domain.PreferencesRepository
+	cache    domain.ICachePort
+	cacheTTL time.Duration
+	now      func() time.Time
+}
+
+// NewPreferencesUseCase creates a new PreferencesUseCase.
+func NewPreferencesUseCase(repo domain.PreferencesRepository) *PreferencesUseCase {
+	return &PreferencesUseCase{repo: repo, now: time.Now}
+}
+
+// WithCache caches stored preferences per owner, including their absence.
+// Non-positive ttl uses DefaultPreferencesCacheTTL.
+func (uc *PreferencesUseCase) WithCache(cache domain.ICachePort, ttl time.Duration) *PreferencesUseCase {
+	if ttl <= 0 {
+		ttl = DefaultPreferencesCacheTTL
+	}
+	uc.cache = cache
+	uc.cacheTTL = ttl
+	return uc
+}
+
+// Resolve returns the preferences that apply to owner: the user's, falling
+// back to the device's for a signed-in user who has stored none. It returns
+// nil when neither has any.
+func (uc *PreferencesUseCase) Resolve(ctx context.Context, owner domain.Owner) (*domain.Preferences, error) {
+	if owner.UserID != "" {
+		p, err := uc.load(ctx, domain.Owner{UserID: owner.UserID}.Key())
+		if p != nil || err != nil {
+			return p, err
+		}
+	}
+	if owner.DeviceID == "" {
+		return nil, nil
+	}
+	return uc.load(ctx, domain.Owner{DeviceID: owner.DeviceID}.Key())
+}
+
+// Get returns the preferences that apply to owner with defaults filled in.
+func (uc *PreferencesUseCase) Get(ctx context.Context, owner domain.Owner) (*domain.Preferences, error) {
+	if _, err := ownerKey(owner); err != nil {
+		return nil, err
+	}
+	p, err := uc.Resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = &domain.Preferences{NotificationChannels: []string{domain.NotificationChannelPush}}
+	}
+	p.ShipToCountry = p.ShipTo()
+	return p, nil
+}
+
+// Update validates and replaces the preferences of owner. A signed-in
+// caller always updates the user's preferences. Omitted notification
+// channels default to push; an empty list disables notifications.
+func (uc *PreferencesUseCase) Update(ctx context.Context, owner domain.Owner, p domain.Preferences) (*domain.Preferences, error) {
+	key, err := ownerKey(owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizePreferences(&p); err != nil {
+		return nil, err
+	}
+	p.Owner = key
+	p.UpdatedAt = uc.now().UTC()
+	if err := uc.repo.Upsert(ctx, &p); err != nil {
+		return nil, err
+	}
+	uc.toCache(ctx, key, &p)
+
+	out := p
+	out.ShipToCountry = out.ShipTo()
+	return &out, nil
+}
+
+func (uc *PreferencesUseCase) load(ctx context.Context, key string) (*domain.Preferences, error) {
+	if p, ok := uc.fromCache(ctx, key); ok {
+		return p, nil
+	}
+	p, err := uc.repo.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	uc.toCache(ctx, key, p)
+	return p, nil
+}
+
+func preferencesCacheKey(owner string) string {
+	return "prefs:" + owner
+}
+
+// fromCache reports a hit for cached preferences and for a cached absence
+// (nil preferences).
+func (uc *PreferencesUseCase) fromCache(ctx context.Context, key string) (*domain.Preferences, bool) {
+	if uc.cache == nil {
+		return nil, false
+	}
+	val, ok, err := uc.cache.Get(ctx, preferencesCacheKey(key))
+	if err != nil || !ok {
+		return nil, false
+	}
+	var p *domain.Preferences
+	if err := json.Unmarshal([]byte(val), &p); err != nil {
+		return nil, false
+	}
+	if p != nil {
+		p.Owner = key
+	}
+	return p, true
+}
+
+func (uc *PreferencesUseCase) toCache(ctx context.Context, key string, p *domain.Preferences) {
+	if uc.cache == nil {
+		return
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+	if err := uc.cache.Set(ctx, preferencesCacheKey(key), string(b), uc.cacheTTL); err != nil {
+		preferencesLog.WarnContext(ctx, "preferences cache set failed", "owner", key, logging.Err(err))
+	}
+}
+
+func normalizePreferences(p *domain.Preferences) error {
+	p.Language = strings.ToLower(strings.TrimSpace(p.Language))
+	if p.Language != "" && !supportedLanguages[p.Language] {
+		return ErrInvalidPreferences.WithDetail("language must be one of en, am")
+	}
+	p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+	if p.Currency != "" && !supportedCurrencies[p.Currency] {
+		return ErrInvalidPreferences.WithDetail("currency must be one of USD, ETB")
+	}
+	p.ShipToCountry = strings.ToUpper(strings.TrimSpace(p.ShipToCountry))
+	if p.ShipToCountry != "" && !isCountryCode(p.ShipToCountry) {
+		return ErrInvalidPreferences.WithDetail("shipToCountry must be an ISO 3166-1 alpha-2 code")
+	}
+
+	if p.NotificationChannels == nil {
+		p.NotificationChannels = []string{domain.NotificationChannelPush}
+	}
+	channels := make([]string, 0, len(p.NotificationChannels))
+	seen := map[string]bool{}
+	for _, ch := range p.NotificationChannels {
+		ch = strings.ToLower(strings.TrimSpace(ch))
+		if !supportedChannels[ch] {
+			return ErrInvalidPreferences.WithDetail("notification channel must be one of push, email")
+		}
+		if !seen[ch] {
+			seen[ch] = true
+			channels = append(channels, ch)
+		}
+	}
+	p.NotificationChannels = channels
+
+	if b := p.Budget; b != nil {
+		if (b.MinUSD != nil && *b.MinUSD < 0) || (b.MaxUSD != nil && *b.MaxUSD <= 0) {
+			return ErrInvalidPreferences.WithDetail("budget minUsd must not be negative and maxUsd must be positive")
+		}
+		if b.MinUSD != nil && b.MaxUSD != nil && *b.MinUSD > *b.MaxUSD {
+			return ErrInvalidPreferences.WithDetail("budget minUsd must not exceed maxUsd")
+		}
+		if b.MinUSD == nil && b.MaxUSD == nil {
+			p.Budget = nil
+		}
+	}
+	return nil
+}
+
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferencesUseCase(t *testing.T) {
+	ctx := context.Background()
+	device := domain.Owner{DeviceID: "dev-1"}
+	user := domain.Owner{UserID: "u1", DeviceID: "dev-1"}
+
+	t.Run("defaults when nothing is stored", func(t *testing.T) {
+		uc := NewPreferencesUseCase(repository.NewMockPreferencesRepository())
+		p, err := uc.Get(ctx, device)
+		require.NoError(t, err)
+		assert.Equal(t, "ET", p.ShipToCountry)
+		assert.Equal(t, []string{domain.NotificationChannelPush}, p.NotificationChannels)
+
+		_, err = uc.Get(ctx, domain.Owner{})
+		assert.ErrorIs(t, err, ErrMissingOwner)
+	})
+
+	t.Run("validates and normalizes updates", func(t *testing.T) {
+		uc := NewPreferencesUseCase(repository.NewMockPreferencesRepository())
+		maxUSD := 50.0
+		p, err := uc.Update(ctx, device, domain.Preferences{
+			Language:             " AM ",
+			Currency:             "etb",
+			ShipToCountry:        "ke",
+			NotificationChannels: []string{"email", "EMAIL"},
+			Budget:               &domain.Budget{MaxUSD: &maxUSD},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "am", p.Language)
+		assert.Equal(t, "ETB", p.Currency)
+		assert.Equal(t, "KE", p.ShipToCountry)
+		assert.Equal(t, []string{"email"}, p.NotificationChannels)
+
+		p, err = uc.Update(ctx, device, domain.Preferences{NotificationChannels: []string{}})
+		require.NoError(t, err)
+		assert.Empty(t, p.NotificationChannels)
+
+		minUSD := 80.0
+		for _, bad := range []domain.Preferences{
+			{Language: "fr"},
+			{Currency: "EUR"},
+			{ShipToCountry: "ETH"},
+			{NotificationChannels: []string{"sms"}},
+			{Budget: &domain.Budget{MinUSD: &minUSD, MaxUSD: &maxUSD}},
+		} {
+			_, err := uc.Update(ctx, device, bad)
+			assert.ErrorIs(t, err, ErrInvalidPreferences, "%+v", bad)
+		}
+	})
+
+	t.Run("a user without preferences falls back to the device", func(t *testing.T) {
+		uc := NewPreferencesUseCase(repository.NewMockPreferencesRepository()).WithCache(newMemoryCache(), 0)
+		_, err := uc.Update(ctx, device, domain.Preferences{ShipToCountry: "KE"})
+		require.NoError(t, err)
+
+		p, err := uc.Resolve(ctx, user)
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.Equal(t, "KE", p.ShipToCountry)
+
+		_, err = uc.Update(ctx, user, domain.Preferences{ShipToCountry: "DJ"})
+		require.NoError(t, err)
+		p, err = uc.Resolve(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, "DJ", p.ShipToCountry)
+		assert.Equal(t, "user:u1", p.Owner)
+
+		p, err = uc.Resolve(ctx, domain.Owner{DeviceID: "dev-2"})
+		require.NoError(t, err)
+		assert.Nil(t, p)
+	})
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var priceHistoryLog = logging.Component("price_history")
+
+const (
+	// DefaultPriceDedupWindow suppresses repeat observations of an unchanged price.
+	DefaultPriceDedupWindow = 6 * time.Hour
+	// maxHistoryWindow caps how far back a history request may look.
+	maxHistoryWindow = 365 * 24 * time.Hour
+)
+
+// DefaultHistoryWindows are used when the client does not request any.
+var DefaultHistoryWindows = []string{"7d", "30d", "90d"}
+
+// ErrInvalidWindow is returned for unparsable or out-of-range history windows.
+var ErrInvalidWindow = domain.Validation("invalid price history window")
+
+// PriceHistoryUseCase records price observations and summarizes them for charts.
+type PriceHistoryUseCase struct {
+	repo        domain.PriceHistoryRepository
+	dedupWindow time.Duration
+	now         func() time.Time
+}
+
+// NewPriceHistoryUseCase creates a new PriceHistoryUseCase. A non-positive
+// dedupWindow uses DefaultPriceDedupWindow.
+func NewPriceHistoryUseCase(repo domain.PriceHistoryRepository, dedupWindow time.Duration) *PriceHistoryUseCase {
+	if dedupWindow <= 0 {
+		dedupWindow = DefaultPriceDedupWindow
+	}
+	return &PriceHistoryUseCase{
+		repo:        repo,
+		dedupWindow: dedupWindow,
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Record stores one observation per product. An observation is skipped when
+// the latest stored one has the same USD price and discount and is younger
+// than the dedup window, so repeated searches do not flood the collection
+// while price changes are always kept.
+func (uc *PriceHistoryUseCase) Record(ctx context.Context, products []*domain.Product, source string) {
+	for _, obs := range uc.observations(products, source) {
+		uc.recordOne(ctx, obs)
+	}
+}
+
+// RecordAsync snapshots the products and records them in the background so
+// request latency is unaffected.
+func (uc *PriceHistoryUseCase) RecordAsync(ctx context.Context, products []*domain.Product, source string) {
+	obs := uc.observations(products, source)
+	if len(obs) == 0 {
+		return
+	}
+	bg := context.WithoutCancel(ctx)
+	go func() {
+		ctx, span := tracer.Start(bg, "price_history.record",
+			trace.WithAttributes(attribute.Int("observations.count", len(obs))))
+		defer span.End()
+		for _, o := range obs {
+			uc.recordOne(ctx, o)
+		}
+	}()
+}
+
+func (uc *PriceHistoryUseCase) observations(products []*domain.Product, source string) []*domain.PriceObservation {
+	now := uc.now()
+	out := make([]*domain.PriceObservation, 0, len(products))
+	for _, p := range products {
+		if p == nil || p.ID == "" || p.Price.USD <= 0 {
+			continue
+		}
+		out = append(out, &domain.PriceObservation{
+			ProductID:   p.ID,
+			USD:         p.Price.USD,
+			ETB:         p.Price.ETB,
+			Discount:    p.Discount,
+			FXTimestamp: p.Price.FXTimestamp,
+			Source:      source,
+			ObservedAt:  now,
+		})
+	}
+	return out
+}
+
+func (uc *PriceHistoryUseCase) recordOne(ctx context.Context, obs *domain.PriceObservation) {
+	latest, err := uc.repo.Latest(ctx, obs.ProductID)
+	if err != nil {
+		priceHistoryLog.WarnContext(ctx, "latest price lookup failed", "product_id", obs.ProductID, logging.Err(err))
+	}
+	if latest != nil && samePrice(latest, obs) && obs.ObservedAt.Sub(latest.ObservedAt) < uc.dedupWindow {
+		return
+	}
+	if err := uc.repo.Record(ctx, obs); err != nil {
+		priceHistoryLog.WarnContext(ctx, "recording price failed", "product_id", obs.ProductID, logging.Err(err))
+	}
+}
+
+func samePrice(a, b *domain.PriceObservation) bool {
+	return math.Abs(a.USD-b.USD) < 0.005 && math.Abs(a.Discount-b.Discount) < 0.005
+}
+
+// History returns chart points for the longest requested window and min/max/avg
+// stats for each window. Windows use a number plus a unit: h, d or w ("30d").
+func (uc *PriceHistoryUseCase) History(ctx context.Context, productID string, windows []string) (*domain.PriceHistory, error) {
+	if len(windows) == 0 {
+		windows = DefaultHistoryWindows
+	}
+	durations := make([]time.Duration, len(windows))
+	longest := time.Duration(0)
+	for i, w := range windows {
+		d, err := ParseHistoryWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		durations[i] = d
+		if d > longest {
+			longest = d
+		}
+	}
+
+	now := uc.now()
+	points, err := uc.repo.List(ctx, productID, now.Add(-longest))
+	if err != nil {
+		return nil, err
+	}
+
+	h := &domain.PriceHistory{ProductID: productID, Points: points, Stats: make([]domain.PriceWindowStats, 0, len(windows))}
+	if len(points) > 0 {
+		latest := points[len(points)-1]
+		h.Latest = &latest
+	}
+	for i, w := range windows {
+		from := now.Add(-durations[i])
+		h.Stats = append(h.Stats, windowStats(strings.TrimSpace(w), from, points))
+	}
+	return h, nil
+}
+
+func windowStats(label string, from time.Time, points []domain.PriceObservation) domain.PriceWindowStats {
+	s := domain.PriceWindowStats{Window: label, From: from}
+	var usd, etb []float64
+	for _, p := range points {
+		if p.ObservedAt.Before(from) {
+			continue
+		}
+		s.Count++
+		usd = append(usd, p.USD)
+		if p.ETB > 0 {
+			etb = append(etb, p.ETB)
+		}
+	}
+	s.USD = priceStats(usd)
+	s.ETB = priceStats(etb)
+	return s
+}
+
+func priceStats(vals []float64) domain.PriceStats {
+	if len(vals) == 0 {
+		return domain.PriceStats{}
+	}
+	st := domain.PriceStats{Min: vals[0], Max: vals[0]}
+	sum := 0.0
+	for _, v := range vals {
+		st.Min = math.Min(st.Min, v)
+		st.Max = math.Max(st.Max, v)
+		sum += v
+	}
+	st.Avg = roundTo(sum/float64(len(vals)), 2)
+	return st
+}
+
+// ParseHistoryWindow parses a window such as "24h", "30d" or "2w".
+func ParseHistoryWindow(w string) (time.Duration, error) {
+	w = strings.ToLower(strings.TrimSpace(w))
+	if len(w) < 2 {
+		return 0, ErrInvalidWindow.WithDetail(strconv.Quote(w))
+	}
+	n, err := strconv.Atoi(w[:len(w)-1])
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidWindow.WithDetail(strconv.Quote(w))
+	}
+	var unit time.Duration
+	switch w[len(w)-1] {
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, ErrInvalidWindow.WithDetail(strconv.Quote(w))
+	}
+	d := time.Duration(n) * unit
+	if d > maxHistoryWindow {
+		return 0, ErrInvalidWindow.WithDetail(strconv.Quote(w) + " exceeds 365d")
+	}
+	return d, nil
+}
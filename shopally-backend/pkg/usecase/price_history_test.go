@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistoryUseCase(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMockPriceHistoryRepository()
+	uc := NewPriceHistoryUseCase(repo, time.Hour)
+
+	clock := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return clock }
+	record := func(usd, etb float64) {
+		uc.Record(ctx, []*domain.Product{{ID: "100", Price: domain.Price{USD: usd, ETB: etb}, Discount: 10}}, domain.PriceSourceSearch)
+	}
+
+	record(20, 3000)
+	clock = clock.Add(10 * time.Minute)
+	record(20, 3000) // unchanged within dedup window: skipped
+	clock = clock.Add(10 * time.Minute)
+	record(25, 3750) // price change: kept
+	clock = clock.Add(2 * time.Hour)
+	record(25, 3750) // unchanged but past dedup window: kept
+
+	all, err := repo.List(ctx, "100", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	clock = clock.Add(8 * 24 * time.Hour)
+	record(10, 1500)
+
+	h, err := uc.History(ctx, "100", []string{"7d", "30d"})
+	require.NoError(t, err)
+	assert.Len(t, h.Points, 4)
+	require.NotNil(t, h.Latest)
+	assert.InDelta(t, 10, h.Latest.USD, 1e-9)
+
+	require.Len(t, h.Stats, 2)
+	assert.Equal(t, "7d", h.Stats[0].Window)
+	assert.Equal(t, 1, h.Stats[0].Count)
+	assert.Equal(t, 4, h.Stats[1].Count)
+	assert.Equal(t, domain.PriceStats{Min: 10, Max: 25, Avg: 20}, h.Stats[1].USD)
+	assert.Equal(t, domain.PriceStats{Min: 1500, Max: 3750, Avg: 3000}, h.Stats[1].ETB)
+
+	_, err = uc.History(ctx, "100", []string{"5y"})
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+}
+
+func TestParseHistoryWindow(t *testing.T) {
+	d, err := ParseHistoryWindow("2w")
+	require.NoError(t, err)
+	assert.Equal(t, 14*24*time.Hour, d)
+
+	for _, w := range []string{"", "d", "0d", "-1d", "400d", "abc"} {
+		_, err := ParseHistoryWindow(w)
+		assert.ErrorIs(t, err, ErrInvalidWindow, w)
+	}
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// applyETBPricing fills Price.ETB for each product from its USD price using
+// the current USD->ETB rate. Products keep their existing ETB price when the
+// FX client is unavailable or fails.
+func applyETBPricing(ctx context.Context, fx domain.IFXClient, products []*domain.Product) error {
+	if fx == nil || len(products) == 0 {
+		return nil
+	}
+	rate, err := fx.GetRate(ctx, "USD", "ETB")
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, p := range products {
+		if p == nil || p.Price.USD <= 0 {
+			continue
+		}
+		p.Price.ETB = roundTo(p.Price.USD*rate, 2)
+		p.Price.FXTimestamp = now
+	}
+	return nil
+}
+
+func roundTo(v float64, places int) float64 {
+	pow := math.Pow(10, float64(places))
+	return math.Round(v*pow) / pow
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/util"
+)
+
+// ResolveLinkUseCase turns a pasted AliExpress link into a full product.
+type ResolveLinkUseCase struct {
+	resolver domain.LinkResolver
+	products *GetProductUseCase
+}
+
+// NewResolveLinkUseCase creates a new ResolveLinkUseCase.
+func NewResolveLinkUseCase(resolver domain.LinkResolver, products *GetProductUseCase) *ResolveLinkUseCase {
+	return &ResolveLinkUseCase{
+		resolver: resolver,
+		products: products,
+	}
+}
+
+// DetectLink returns the AliExpress URL contained in a search query, if any.
+func (uc *ResolveLinkUseCase) DetectLink(query string) (string, bool) {
+	u, ok := util.FindAliExpressURL(query)
+	if !ok {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// Execute resolves rawURL to a product ID and returns the product via the
+// detail API. It returns domain.ErrUnsupportedLink for links that do not
+// point at a product and domain.ErrProductNotFound for unknown IDs.
+func (uc *ResolveLinkUseCase) Execute(ctx context.Context, rawURL string) (*domain.Product, error) {
+	id, err := uc.resolver.ResolveProductID(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return uc.products.Execute(ctx, id)
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/tracing"
+	"github.com/shopally-ai/pkg/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var resolveLog = logging.Component("resolve_products")
+
+// ProductsResolver turns product references into server-side product data.
+type ProductsResolver interface {
+	Resolve(ctx context.Context, refs []domain.ProductRef) ([]*domain.Product, error)
+}
+
+// ResolveProductsUseCase resolves product references to fresh product data,
+// falling back to products cached from recent searches when the upstream
+// lookup fails.
+type ResolveProductsUseCase struct {
+	products  *GetProductUseCase
+	snapshots domain.ICachePort
+}
+
+var _ ProductsResolver = (*ResolveProductsUseCase)(nil)
+
+// NewResolveProductsUseCase creates a resolver. snapshots is optional and
+// should be the cache passed to SearchProductsUseCase.WithSnapshotCache.
+func NewResolveProductsUseCase(products *GetProductUseCase, snapshots domain.ICachePort) *ResolveProductsUseCase {
+	return &ResolveProductsUseCase{products: products, snapshots: snapshots}
+}
+
+// Resolve looks up every reference concurrently and returns the products in
+// request order. It fails with domain.ErrUnsupportedProvider or
+// domain.ErrProductNotFound (wrapped with the offending ID), or with the
+// upstream error when neither a fresh nor a cached copy is available.
+func (uc *ResolveProductsUseCase) Resolve(ctx context.Context, refs []domain.ProductRef) (_ []*domain.Product, err error) {
+	ctx, span := tracer.Start(ctx, "products.resolve", trace.WithAttributes(attribute.Int("products.count", len(refs))))
+	defer func() { tracing.End(span, err) }()
+
+	for _, ref := range refs {
+		if ref.NormalizedProvider() != domain.ProviderAliExpress {
+			return nil, domain.ErrUnsupportedProvider.WithDetail(ref.Provider)
+		}
+	}
+
+	out := make([]*domain.Product, len(refs))
+	errs := make([]error, len(refs))
+	var wg sync.WaitGroup
+	wg.Add(len(refs))
+	for i, ref := range refs {
+		go func(i int, id string) {
+			defer wg.Done()
+			out[i], errs[i] = uc.resolveOne(ctx, id)
+		}(i, ref.ID)
+	}
+	wg.Wait()
+
+	for _, e := range errs {
+		if e != nil {
+			return nil, e
+		}
+	}
+	return out, nil
+}
+
+func (uc *ResolveProductsUseCase) resolveOne(ctx context.Context, id string) (*domain.Product, error) {
+	p, err := uc.products.Execute(ctx, id)
+	if err == nil {
+		return p, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if cached := uc.fromSnapshot(ctx, id); cached != nil {
+		resolveLog.InfoContext(ctx, "using cached search result", "product_id", id, logging.Err(err))
+		return cached, nil
+	}
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return nil, domain.ErrProductNotFound.WithDetail(id)
+	}
+	return nil, err
+}
+
+func (uc *ResolveProductsUseCase) fromSnapshot(ctx context.Context, id string) *domain.Product {
+	if uc.snapshots == nil {
+		return nil
+	}
+	val, ok, err := uc.snapshots.Get(ctx, searchSnapshotKey(shipTo(ctx), id))
+	if err != nil || !ok {
+		return nil
+	}
+	var p domain.Product
+	if err := json.Unmarshal([]byte(val), &p); err != nil {
+		return nil
+	}
+	return &p
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyGateway fails detail lookups for the listed IDs.
+type flakyGateway struct {
+	detailGateway
+	failing map[string]bool
+}
+
+func (g *flakyGateway) FetchProductDetail(ctx context.Context, productID string) (*domain.Product, error) {
+	if g.failing[productID] {
+		return nil, errors.New("upstream unavailable")
+	}
+	return g.detailGateway.FetchProductDetail(ctx, productID)
+}
+
+func TestResolveProductsUseCase(t *testing.T) {
+	ag := &flakyGateway{
+		detailGateway: detailGateway{products: map[string]*domain.Product{
+			"1": {ID: "1", Title: "Fresh", Price: domain.Price{USD: 10}},
+		}},
+		failing: map[string]bool{"2": true, "3": true},
+	}
+	snapshots := newMemoryCache()
+	b, _ := json.Marshal(&domain.Product{ID: "2", Title: "From search", Price: domain.Price{USD: 12}})
+	snapshots.data[searchSnapshotKey(domain.DefaultShipToCountry, "2")] = string(b)
+
+	uc := NewResolveProductsUseCase(NewGetProductUseCase(ag, nil, nil, 0), snapshots)
+
+	t.Run("prefers fresh data and falls back to search snapshots", func(t *testing.T) {
+		products, err := uc.Resolve(context.Background(), []domain.ProductRef{{ID: "1"}, {ID: "2", Provider: "AliExpress"}})
+		require.NoError(t, err)
+		require.Len(t, products, 2)
+		assert.Equal(t, "Fresh", products[0].Title)
+		assert.Equal(t, "From search", products[1].Title)
+	})
+
+	t.Run("ignores snapshots taken for another delivery country", func(t *testing.T) {
+		us := context.WithValue(context.Background(), contextkeys.Preferences, &domain.Preferences{ShipToCountry: "US"})
+		_, err := uc.Resolve(us, []domain.ProductRef{{ID: "2"}})
+		assert.EqualError(t, err, "upstream unavailable")
+	})
+
+	t.Run("reports the upstream error without a snapshot", func(t *testing.T) {
+		_, err := uc.Resolve(context.Background(), []domain.ProductRef{{ID: "1"}, {ID: "3"}})
+		assert.EqualError(t, err, "upstream unavailable")
+	})
+
+	t.Run("reports unknown products", func(t *testing.T) {
+		_, err := uc.Resolve(context.Background(), []domain.ProductRef{{ID: "1"}, {ID: "404"}})
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("rejects unsupported providers", func(t *testing.T) {
+		_, err := uc.Resolve(context.Background(), []domain.ProductRef{{ID: "1", Provider: "amazon"}})
+		assert.ErrorIs(t, err, domain.ErrUnsupportedProvider)
+	})
+}
+
+func TestSearch_StoresSnapshots(t *testing.T) {
+	ag := &stubAlibabaGateway{match: func(string, map[string]interface{}) bool { return true }}
+	cache := newMemoryCache()
+	uc := NewSearchProductsUseCase(ag, &stubIntentLLM{intent: map[string]interface{}{}}, nil).WithSnapshotCache(cache, 0)
+
+	_, err := uc.Search(context.Background(), "chair")
+	require.NoError(t, err)
+	assert.Contains(t, cache.data, searchSnapshotKey(domain.DefaultShipToCountry, "P1"))
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/util"
+)
+
+// RuleBasedComparator compares products from their data alone. It is used
+// when the LLM is unavailable or when a client asks for a deterministic
+// comparison.
+type RuleBasedComparator struct{}
+
+// NewRuleBasedComparator creates a rule-based comparator.
+func NewRuleBasedComparator() *RuleBasedComparator {
+	return &RuleBasedComparator{}
+}
+
+// compareMetric is one product attribute scored by the rule-based comparator.
+type compareMetric struct {
+	key            string
+	weight         float64
+	higherIsBetter bool
+	value          func(p *domain.Product) (float64, bool)
+	format         func(v float64) string
+}
+
+var compareMetrics = []compareMetric{
+	{
+		key: "price", weight: 0.35,
+		value:  func(p *domain.Product) (float64, bool) { return p.Price.USD, p.Price.USD > 0 },
+		format: func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+	},
+	{
+		key: "rating", weight: 0.25, higherIsBetter: true,
+		value: func(p *domain.Product) (float64, bool) {
+			r := util.NormalizeRating(p.ProductRating)
+			return r, r > 0
+		},
+		format: func(v float64) string { return fmt.Sprintf("%.1f/5", v) },
+	},
+	{
+		key: "sold", weight: 0.15, higherIsBetter: true,
+		value:  func(p *domain.Product) (float64, bool) { return float64(p.NumberSold), p.NumberSold > 0 },
+		format: func(v float64) string { return strconv.Itoa(int(v)) },
+	},
+	{
+		key: "discount", weight: 0.10, higherIsBetter: true,
+		value:  func(p *domain.Product) (float64, bool) { return p.Discount, p.Discount >= 0 },
+		format: func(v float64) string { return fmt.Sprintf("%.0f%%", v) },
+	},
+	{
+		key: "delivery", weight: 0.10,
+		value: func(p *domain.Product) (float64, bool) {
+			d, ok := util.ParseDeliveryDays(p.DeliveryEstimate)
+			return float64(d), ok
+		},
+		format: func(v float64) string { return strconv.Itoa(int(v)) },
+	},
+	{
+		key: "tax", weight: 0.05,
+		value: func(p *domain.Product) (float64, bool) { return p.TaxRate, p.TaxRate >= 0 },
+	},
+}
+
+// compareMessages holds the localized pros, cons and feature values. Pros and
+// cons of metrics with a format take the formatted value as their argument.
+var compareMessages = map[string]map[string]string{
+	"en": {
+		"price.pro":    "Lowest price (%s)",
+		"price.con":    "Highest price (%s)",
+		"rating.pro":   "Highest rating (%s)",
+		"rating.con":   "Lowest rating (%s)",
+		"sold.pro":     "Most popular (%s sold)",
+		"sold.con":     "Fewest sales (%s sold)",
+		"discount.pro": "Biggest discount (%s)",
+		"discount.con": "Smallest discount (%s)",
+		"delivery.pro": "Fastest delivery (%s days)",
+		"delivery.con": "Slowest delivery (%s days)",
+		"tax.pro":      "Lowest import tax",
+		"tax.con":      "Highest import tax",
+		"unknown":      "Unknown",
+		"yes":          "Yes",
+		"no":           "No",
+	},
+	"am": {
+		"price.pro":    "ዝቅተኛ ዋጋ (%s)",
+		"price.con":    "ከፍተኛ ዋጋ (%s)",
+		"rating.pro":   "ከፍተኛ ደረጃ (%s)",
+		"rating.con":   "ዝቅተኛ ደረጃ (%s)",
+		"sold.pro":     "በብዛት የተሸጠ (%s ተሽጧል)",
+		"sold.con":     "ጥቂት ሽያጭ (%s ተሽጧል)",
+		"discount.pro": "ትልቅ ቅናሽ (%s)",
+		"discount.con": "አነስተኛ ቅናሽ (%s)",
+		"delivery.pro": "ፈጣን ማድረስ (%s ቀናት)",
+		"delivery.con": "ዘገምተኛ ማድረስ (%s ቀናት)",
+		"tax.pro":      "ዝቅተኛ የማስመጫ ግብር",
+		"tax.con":      "ከፍተኛ የማስመጫ ግብር",
+		"unknown":      "አይታወቅም",
+		"yes":          "አዎ",
+		"no":           "የለም",
+	},
+}
+
+// Compare builds pros, cons, a best-value pick and a feature matrix for the
+// given products. Output is localized using contextkeys.RespLang.
+func (c *RuleBasedComparator) Compare(ctx context.Context, products []*domain.Product) *domain.ComparisonResult {
+	msgs := compareMessages["en"]
+	if lang, _ := ctx.Value(contextkeys.RespLang).(string); lang == "am" {
+		msgs = compareMessages["am"]
+	}
+
+	result := &domain.ComparisonResult{
+		Products: make([]domain.ProductComparison, 0, len(products)),
+		Source:   domain.ComparisonSourceRules,
+	}
+	var items []*domain.Product
+	for _, p := range products {
+		if p == nil {
+			continue
+		}
+		items = append(items, p)
+		result.Products = append(result.Products, domain.ProductComparison{
+			Product:   *p,
+			Synthesis: domain.Synthesis{Pros: []string{}, Cons: []string{}},
+		})
+	}
+	if len(items) == 0 {
+		return result
+	}
+
+	scores := make([]float64, len(items))
+	for _, m := range compareMetrics {
+		values := make([]float64, len(items))
+		known := make([]bool, len(items))
+		lo, hi, n := 0.0, 0.0, 0
+		for i, p := range items {
+			v, ok := m.value(p)
+			if !ok {
+				continue
+			}
+			values[i], known[i] = v, true
+			if n == 0 || v < lo {
+				lo = v
+			}
+			if n == 0 || v > hi {
+				hi = v
+			}
+			n++
+		}
+
+		// A metric only discriminates when at least two products differ.
+		if n < 2 || hi == lo {
+			for i := range scores {
+				scores[i] += m.weight * 0.5
+			}
+			continue
+		}
+
+		best, worst := lo, hi
+		if m.higherIsBetter {
+			best, worst = hi, lo
+		}
+		for i := range items {
+			if !known[i] {
+				scores[i] += m.weight * 0.5
+				continue
+			}
+			norm := (values[i] - lo) / (hi - lo)
+			if !m.higherIsBetter {
+				norm = 1 - norm
+			}
+			scores[i] += m.weight * norm
+
+			syn := &result.Products[i].Synthesis
+			switch values[i] {
+			case best:
+				syn.Pros = append(syn.Pros, m.message(msgs, "pro", values[i]))
+			case worst:
+				syn.Cons = append(syn.Cons, m.message(msgs, "con", values[i]))
+			}
+		}
+	}
+
+	bestIdx := 0
+	for i := 1; i < len(items); i++ {
+		if scores[i] > scores[bestIdx] || (scores[i] == scores[bestIdx] && items[i].Price.USD > 0 && items[i].Price.USD < items[bestIdx].Price.USD) {
+			bestIdx = i
+		}
+	}
+	result.Products[bestIdx].Synthesis.IsBestValue = true
+
+	result.Specs = buildSpecMatrix(items)
+	features := buildFeatureMatrix(items, result.Specs, msgs)
+	for i := range result.Products {
+		result.Products[i].Synthesis.Features = features[i]
+	}
+	return result
+}
+
+// message renders the localized pro or con for value.
+func (m compareMetric) message(msgs map[string]string, kind string, value float64) string {
+	text := msgs[m.key+"."+kind]
+	if m.format == nil {
+		return text
+	}
+	return fmt.Sprintf(text, m.format(value))
+}
+
+// specFeatureNames labels spec matrix rows in the feature map.
+var specFeatureNames = map[string]string{
+	domain.SpecStorage:    "Storage",
+	domain.SpecRAM:        "RAM",
+	domain.SpecScreenSize: "Screen Size",
+	domain.SpecBattery:    "Battery",
+	domain.SpecMaterial:   "Material",
+	domain.SpecSize:       "Size",
+}
+
+// featureDetector reports whether a product's title or description mentions
+// a yes/no feature.
+type featureDetector struct {
+	name string
+	re   *regexp.Regexp
+}
+
+var featureDetectors = []featureDetector{
+	{name: "Wireless", re: regexp.MustCompile(`(?i)\b(wireless|bluetooth|wi-?fi)\b`)},
+	{name: "Waterproof", re: regexp.MustCompile(`(?i)\b(waterproof|water[- ]resistant|ip6[78])\b`)},
+}
+
+// buildFeatureMatrix returns one feature map per product, built from the
+// spec matrix plus yes/no detectors. A feature is only included when at least
+// one product mentions it, so every product gets the same keys with
+// "Unknown" (or "No") for missing values.
+func buildFeatureMatrix(products []*domain.Product, specs *domain.SpecMatrix, msgs map[string]string) []map[string]string {
+	out := make([]map[string]string, len(products))
+	for i := range out {
+		out[i] = map[string]string{}
+	}
+
+	for _, row := range specs.Rows {
+		for i, v := range row.Values {
+			if v.Missing {
+				out[i][specFeatureNames[row.Key]] = msgs["unknown"]
+			} else {
+				out[i][specFeatureNames[row.Key]] = v.Display
+			}
+		}
+	}
+
+	for _, d := range featureDetectors {
+		values := make([]string, len(products))
+		found := false
+		for i, p := range products {
+			values[i] = msgs["no"]
+			if d.re.MatchString(p.Title + " " + p.Description) {
+				values[i] = msgs["yes"]
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		for i := range products {
+			out[i][d.name] = values[i]
+		}
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleBasedComparator_Compare(t *testing.T) {
+	products := []*domain.Product{
+		{
+			ID: "A", Title: "Wireless earbuds 400mAh", Price: domain.Price{USD: 25},
+			ProductRating: 96, NumberSold: 5000, Discount: 30, DeliveryEstimate: "7-10 days",
+		},
+		{
+			ID: "B", Title: "Earbuds", Description: "Waterproof IP67 case", Price: domain.Price{USD: 18},
+			ProductRating: 3.8, NumberSold: 120, Discount: 0, DeliveryEstimate: "20-40 days",
+		},
+	}
+
+	out := NewRuleBasedComparator().Compare(context.Background(), products)
+	require.NoError(t, validateComparison(out, products))
+	assert.Equal(t, domain.ComparisonSourceRules, out.Source)
+
+	a, b := out.Products[0].Synthesis, out.Products[1].Synthesis
+	assert.True(t, a.IsBestValue, "better rating, sales, discount and delivery outweigh price")
+	assert.Contains(t, a.Pros, "Highest rating (4.8/5)")
+	assert.Contains(t, a.Pros, "Fastest delivery (10 days)")
+	assert.Contains(t, a.Cons, "Highest price ($25.00)")
+	assert.Contains(t, b.Pros, "Lowest price ($18.00)")
+	assert.Contains(t, b.Cons, "Smallest discount (0%)")
+
+	assert.Equal(t, map[string]string{"Battery": "400 mAh", "Wireless": "Yes", "Waterproof": "No"}, a.Features)
+	assert.Equal(t, map[string]string{"Battery": "Unknown", "Wireless": "No", "Waterproof": "Yes"}, b.Features)
+}
+
+func TestRuleBasedComparator_Localized(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextkeys.RespLang, "am")
+	products := []*domain.Product{
+		{ID: "A", Title: "Leather wallet", Price: domain.Price{USD: 10}},
+		{ID: "B", Title: "Wallet", Price: domain.Price{USD: 12}},
+	}
+
+	out := NewRuleBasedComparator().Compare(ctx, products)
+	assert.True(t, out.Products[0].Synthesis.IsBestValue)
+	assert.Equal(t, []string{"ዝቅተኛ ዋጋ ($10.00)"}, out.Products[0].Synthesis.Pros)
+	assert.Equal(t, "አይታወቅም", out.Products[1].Synthesis.Features["Material"])
+}
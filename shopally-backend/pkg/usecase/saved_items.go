@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/pkg/domain"
+)
+
+var savedLog = logging.Component("saved_items")
+
+// Saved-item paging limits.
+const (
+	DefaultSavedPageSize = 20
+	MaxSavedPageSize     = 50
+	maxSavedListName     = 40
+)
+
+var (
+	// ErrInvalidSavedQuery is returned for an unknown sort or a bad page.
+	ErrInvalidSavedQuery = domain.Validation("invalid saved items query")
+	// ErrInvalidListName is returned for an empty or overlong list name.
+	ErrInvalidListName = domain.Validation("list name must be 1-40 characters")
+	// ErrMissingOwner is returned when the caller is neither signed in nor
+	// identified by a device ID.
+	ErrMissingOwner = domain.Validation("a signed-in user or device ID is required")
+	// ErrProductLookup wraps an upstream failure while snapshotting a product.
+	ErrProductLookup = domain.UpstreamUnavailable("failed to fetch the product", nil)
+)
+
+// ProductLookup returns the current data of a product, or
+// domain.ErrProductNotFound. GetProductUseCase implements it.
+type ProductLookup interface {
+	Execute(ctx context.Context, productID string) (*domain.Product, error)
+}
+
+// SavedItemsUseCase manages saved items of a user or device and shows their
+// current price next to the saved one.
+type SavedItemsUseCase struct {
+	items    domain.SavedItemRepository
+	products ProductLookup
+	alerts   *AlertManager
+	now      func() time.Time
+}
+
+// NewSavedItemsUseCase creates a new SavedItemsUseCase.
+func NewSavedItemsUseCase(items domain.SavedItemRepository, products ProductLookup, alerts *AlertManager) *SavedItemsUseCase {
+	return &SavedItemsUseCase{items: items, products: products, alerts: alerts, now: time.Now}
+}
+
+// Save snapshots the product server-side and saves it to list. Saving a
+// product that is already saved returns the existing item and false.
+func (uc *SavedItemsUseCase) Save(ctx context.Context, owner domain.Owner, productID, list string) (*domain.SavedItem, bool, error) {
+	key, err := ownerKey(owner)
+	if err != nil {
+		return nil, false, err
+	}
+	if list, err = normalizeListName(list); err != nil {
+		return nil, false, err
+	}
+	if existing, err := uc.items.GetByProduct(ctx, key, productID); err == nil {
+		uc.annotate(ctx, []*domain.SavedItem{existing})
+		return existing, false, nil
+	} else if !errors.Is(err, domain.ErrSavedItemNotFound) {
+		return nil, false, err
+	}
+
+	p, err := uc.products.Execute(ctx, productID)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return nil, false, err
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrProductLookup, err)
+	}
+	now := uc.now().UTC()
+	item := &domain.SavedItem{
+		ID:        uuid.New().String(),
+		Owner:     key,
+		ProductID: p.ID,
+		List:      list,
+		Snapshot: domain.SavedSnapshot{
+			Title:         p.Title,
+			ImageURL:      p.ImageURL,
+			DeeplinkURL:   p.DeeplinkURL,
+			Price:         p.Price,
+			ProductRating: p.ProductRating,
+			Discount:      p.Discount,
+		},
+		SavedAt:   now,
+		UpdatedAt: now,
+	}
+	if err := uc.items.Create(ctx, item); err != nil {
+		if errors.Is(err, domain.ErrAlreadySaved) {
+			// Lost a race with a concurrent save of the same product.
+			existing, gerr := uc.items.GetByProduct(ctx, key, productID)
+			if gerr != nil {
+				return nil, false, gerr
+			}
+			return existing, false, nil
+		}
+		return nil, false, err
+	}
+	setCurrentPrice(item, p.Price)
+	return item, true, nil
+}
+
+// List returns one page of saved items with their current prices.
+func (uc *SavedItemsUseCase) List(ctx context.Context, owner domain.Owner, q domain.SavedItemQuery) (*domain.SavedItemPage, error) {
+	key, err := ownerKey(owner)
+	if err != nil {
+		return nil, err
+	}
+	if q, err = normalizeSavedQuery(q); err != nil {
+		return nil, err
+	}
+	items, total, err := uc.items.List(ctx, key, q)
+	if err != nil {
+		return nil, err
+	}
+	ptrs := make([]*domain.SavedItem, len(items))
+	for i := range items {
+		ptrs[i] = &items[i]
+	}
+	uc.annotate(ctx, ptrs)
+	return &domain.SavedItemPage{Items: items, Total: total, Page: q.Page, PageSize: q.PageSize}, nil
+}
+
+// Lists returns the owner's list names with their item counts.
+func (uc *SavedItemsUseCase) Lists(ctx context.Context, owner domain.Owner) ([]domain.SavedList, error) {
+	key, err := ownerKey(owner)
+	if err != nil {
+		return nil, err
+	}
+	return uc.items.Lists(ctx, key)
+}
+
+// Move moves a saved item to another list.
+func (uc *SavedItemsUseCase) Move(ctx context.Context, owner domain.Owner, id, list string) (*domain.SavedItem, error) {
+	key, err := ownerKey(owner)
+	if err != nil {
+		return nil, err
+	}
+	if list, err = normalizeListName(list); err != nil {
+		return nil, err
+	}
+	item, err := uc.items.Get(ctx, key, id)
+	if err != nil {
+		return nil, err
+	}
+	item.List = list
+	item.UpdatedAt = uc.now().UTC()
+	if err := uc.items.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Remove deletes a saved item.
+func (uc *SavedItemsUseCase) Remove(ctx context.Context, owner domain.Owner, id string) error {
+	key, err := ownerKey(owner)
+	if err != nil {
+		return err
+	}
+	return uc.items.Delete(ctx, key, id)
+}
+
+// CreateAlert creates a price alert for a saved item on deviceID at the
+// item's current price, or its saved price when the current one is not
+// available. An item whose alert is still active returns that alert.
+func (uc *SavedItemsUseCase) CreateAlert(ctx context.Context, owner domain.Owner, id, deviceID string) (*domain.Alert, error) {
+	key, err := ownerKey(owner)
+	if err != nil {
+		return nil, err
+	}
+	if deviceID == "" {
+		return nil, ErrMissingOwner
+	}
+	item, err := uc.items.Get(ctx, key, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.AlertID != "" {
+		if existing, err := uc.alerts.GetAlert(item.AlertID); err == nil && existing.IsActive {
+			return existing, nil
+		}
+	}
+
+	uc.annotate(ctx, []*domain.SavedItem{item})
+	price := item.Snapshot.Price.USD
+	if item.CurrentPrice != nil {
+		price = item.CurrentPrice.USD
+	}
+	alert := &domain.Alert{DeviceID: deviceID, UserID: owner.UserID, ProductID: item.ProductID, CurrentPrice: price, IsActive: true}
+	if err := uc.alerts.CreateAlert(alert); err != nil {
+		return nil, err
+	}
+	item.AlertID = alert.ID
+	item.UpdatedAt = uc.now().UTC()
+	if err := uc.items.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// annotate looks up the current price of every item concurrently. Items
+// whose lookup fails keep only their saved price.
+func (uc *SavedItemsUseCase) annotate(ctx context.Context, items []*domain.SavedItem) {
+	var wg sync.WaitGroup
+	wg.Add(len(items))
+	for _, item := range items {
+		go func(item *domain.SavedItem) {
+			defer wg.Done()
+			p, err := uc.products.Execute(ctx, item.ProductID)
+			if err != nil {
+				savedLog.WarnContext(ctx, "current price lookup failed", "product_id", item.ProductID, logging.Err(err))
+				return
+			}
+			setCurrentPrice(item, p.Price)
+		}(item)
+	}
+	wg.Wait()
+}
+
+func setCurrentPrice(item *domain.SavedItem, current domain.Price) {
+	price := current
+	item.CurrentPrice = &price
+	change := math.Round((current.USD-item.Snapshot.Price.USD)*100) / 100
+	item.PriceChangeUSD = &change
+}
+
+func ownerKey(owner domain.Owner) (string, error) {
+	key := owner.Key()
+	if key == "" {
+		return "", ErrMissingOwner
+	}
+	return key, nil
+}
+
+func normalizeListName(list string) (string, error) {
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return domain.DefaultSavedList, nil
+	}
+	if utf8.RuneCountInString(list) > maxSavedListName {
+		return "", ErrInvalidListName
+	}
+	return list, nil
+}
+
+func normalizeSavedQuery(q domain.SavedItemQuery) (domain.SavedItemQuery, error) {
+	q.List = strings.TrimSpace(q.List)
+	switch q.Sort {
+	case "":
+		q.Sort = domain.SavedSortNewest
+	case domain.SavedSortNewest, domain.SavedSortOldest, domain.SavedSortPriceAsc, domain.SavedSortPriceDesc, domain.SavedSortTitle:
+	default:
+		return q, ErrInvalidSavedQuery
+	}
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultSavedPageSize
+	}
+	if q.Page < 1 || q.PageSize < 1 || q.PageSize > MaxSavedPageSize {
+		return q, ErrInvalidSavedQuery
+	}
+	return q, nil
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopally-ai/internal/adapter/repository"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// priceBook is a ProductLookup over a mutable map of USD prices.
+type priceBook struct {
+	mu     sync.Mutex
+	prices map[string]float64
+	down   bool
+}
+
+func (b *priceBook) Execute(ctx context.Context, id string) (*domain.Product, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.down {
+		return nil, errors.New("upstream unavailable")
+	}
+	usd, ok := b.prices[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+	return &domain.Product{ID: id, Title: "Product " + id, Price: domain.Price{USD: usd}}, nil
+}
+
+func (b *priceBook) set(id string, usd float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prices[id] = usd
+}
+
+func newSavedItems(book *priceBook) *SavedItemsUseCase {
+	uc := NewSavedItemsUseCase(repository.NewMockSavedItemRepository(), book, NewAlertManager(repository.NewMockAlertRepository()))
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return uc
+}
+
+func TestSavedItemsUseCase_SaveAndList(t *testing.T) {
+	ctx := context.Background()
+	book := &priceBook{prices: map[string]float64{"1": 30, "2": 10, "3": 20}}
+	uc := newSavedItems(book)
+	owner := domain.Owner{DeviceID: "dev-1"}
+
+	_, _, err := uc.Save(ctx, domain.Owner{}, "1", "")
+	assert.ErrorIs(t, err, ErrMissingOwner)
+	_, _, err = uc.Save(ctx, owner, "404", "")
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	first, created, err := uc.Save(ctx, owner, "1", "")
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, domain.DefaultSavedList, first.List)
+	again, created, err := uc.Save(ctx, owner, "1", "gifts")
+	require.NoError(t, err)
+	assert.False(t, created, "saving twice returns the existing item")
+	assert.Equal(t, first.ID, again.ID)
+
+	_, _, err = uc.Save(ctx, owner, "2", "gifts")
+	require.NoError(t, err)
+	_, _, err = uc.Save(ctx, owner, "3", "")
+	require.NoError(t, err)
+
+	// The list shows the current price next to the saved one.
+	book.set("1", 25)
+	page, err := uc.List(ctx, owner, domain.SavedItemQuery{Sort: domain.SavedSortPriceAsc})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 3)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, []string{"2", "3", "1"}, savedProductIDs(page.Items))
+	saved := page.Items[2]
+	assert.InDelta(t, 30, saved.Snapshot.Price.USD, 1e-9)
+	require.NotNil(t, saved.CurrentPrice)
+	assert.InDelta(t, 25, saved.CurrentPrice.USD, 1e-9)
+	assert.InDelta(t, -5, *saved.PriceChangeUSD, 1e-9)
+
+	page, err = uc.List(ctx, owner, domain.SavedItemQuery{Page: 2, PageSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, savedProductIDs(page.Items), "newest first")
+
+	page, err = uc.List(ctx, owner, domain.SavedItemQuery{List: "gifts"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, savedProductIDs(page.Items))
+
+	_, err = uc.List(ctx, owner, domain.SavedItemQuery{Sort: "random"})
+	assert.ErrorIs(t, err, ErrInvalidSavedQuery)
+	_, err = uc.List(ctx, owner, domain.SavedItemQuery{PageSize: MaxSavedPageSize + 1})
+	assert.ErrorIs(t, err, ErrInvalidSavedQuery)
+
+	// An unavailable upstream still lists the saved snapshot.
+	book.down = true
+	page, err = uc.List(ctx, owner, domain.SavedItemQuery{})
+	require.NoError(t, err)
+	assert.Nil(t, page.Items[0].CurrentPrice)
+
+	// Other owners see nothing.
+	page, err = uc.List(ctx, domain.Owner{UserID: "user-1", DeviceID: "dev-1"}, domain.SavedItemQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+}
+
+func TestSavedItemsUseCase_MoveRemoveAlert(t *testing.T) {
+	ctx := context.Background()
+	book := &priceBook{prices: map[string]float64{"1": 30}}
+	uc := newSavedItems(book)
+	owner := domain.Owner{UserID: "user-1"}
+
+	item, _, err := uc.Save(ctx, owner, "1", "")
+	require.NoError(t, err)
+
+	moved, err := uc.Move(ctx, owner, item.ID, " Birthday ")
+	require.NoError(t, err)
+	assert.Equal(t, "Birthday", moved.List)
+	lists, err := uc.Lists(ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.SavedList{{Name: "Birthday", Count: 1}}, lists)
+	_, err = uc.Move(ctx, domain.Owner{UserID: "user-2"}, item.ID, "mine")
+	assert.ErrorIs(t, err, domain.ErrSavedItemNotFound)
+
+	book.set("1", 27)
+	alert, err := uc.CreateAlert(ctx, owner, item.ID, "dev-9")
+	require.NoError(t, err)
+	assert.Equal(t, "dev-9", alert.DeviceID)
+	assert.InDelta(t, 27, alert.CurrentPrice, 1e-9, "alerts start from the current price")
+	again, err := uc.CreateAlert(ctx, owner, item.ID, "dev-9")
+	require.NoError(t, err)
+	assert.Equal(t, alert.ID, again.ID, "one active alert per saved item")
+
+	require.NoError(t, uc.Remove(ctx, owner, item.ID))
+	assert.ErrorIs(t, uc.Remove(ctx, owner, item.ID), domain.ErrSavedItemNotFound)
+}
+
+func savedProductIDs(items []domain.SavedItem) []string {
+	ids := make([]string, len(items))
+	for i, it := range items {
+		ids[i] = it.ProductID
+	}
+	return ids
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"sort"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/shopally-ai/pkg/util"
+)
+
+// priceHistogramBuckets is the maximum number of equal-width price buckets.
+const priceHistogramBuckets = 5
+
+type bucketDef struct {
+	key   string
+	label string
+	match func(float64) bool
+}
+
+var deliveryBuckets = []bucketDef{
+	{"0-7", "Within a week", func(d float64) bool { return d <= 7 }},
+	{"8-15", "1-2 weeks", func(d float64) bool { return d > 7 && d <= 15 }},
+	{"16-30", "2-4 weeks", func(d float64) bool { return d > 15 && d <= 30 }},
+	{"31+", "Over a month", func(d float64) bool { return d > 30 }},
+}
+
+var ratingBuckets = []bucketDef{
+	{"4.5+", "4.5 & up", func(r float64) bool { return r >= 4.5 }},
+	{"4.0-4.5", "4.0 - 4.5", func(r float64) bool { return r >= 4.0 && r < 4.5 }},
+	{"3.0-4.0", "3.0 - 4.0", func(r float64) bool { return r >= 3.0 && r < 4.0 }},
+	{"<3.0", "Below 3.0", func(r float64) bool { return r > 0 && r < 3.0 }},
+}
+
+// buildFacets computes filter facets over the fetched page of products.
+// Prices are bucketed in currency ("ETB" or "USD"); ETB falls back to USD
+// when products carry no ETB price.
+func buildFacets(products []*domain.Product, currency string) domain.SearchFacets {
+	var items []*domain.Product
+	for _, p := range products {
+		if p != nil {
+			items = append(items, p)
+		}
+	}
+
+	return domain.SearchFacets{
+		Categories:     categoryFacet(items),
+		PriceHistogram: priceHistogram(items, currency),
+		DeliveryTime: bucketFacet(items, deliveryBuckets, func(p *domain.Product) (float64, bool) {
+			d, ok := util.ParseDeliveryDays(p.DeliveryEstimate)
+			return float64(d), ok
+		}),
+		Rating: bucketFacet(items, ratingBuckets, func(p *domain.Product) (float64, bool) {
+			r := util.NormalizeRating(p.ProductRating)
+			return r, r > 0
+		}),
+	}
+}
+
+// categoryFacet counts products by their most specific known category.
+func categoryFacet(products []*domain.Product) []domain.FacetCount {
+	counts := map[string]*domain.FacetCount{}
+	for _, p := range products {
+		c := p.SubCategory
+		if c.ID == "" && c.Name == "" {
+			c = p.Category
+		}
+		if c.ID == "" && c.Name == "" {
+			continue
+		}
+		key := c.ID
+		if key == "" {
+			key = c.Name
+		}
+		fc, ok := counts[key]
+		if !ok {
+			fc = &domain.FacetCount{Key: key, Label: c.Name}
+			counts[key] = fc
+		}
+		fc.Count++
+	}
+
+	out := make([]domain.FacetCount, 0, len(counts))
+	for _, fc := range counts {
+		out = append(out, *fc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Label < out[j].Label
+	})
+	return out
+}
+
+// bucketFacet counts products into fixed buckets, adding an "unknown" bucket
+// for products whose value cannot be determined. Empty buckets are kept so
+// the UI can render a stable set of filters.
+func bucketFacet(products []*domain.Product, defs []bucketDef, value func(*domain.Product) (float64, bool)) []domain.FacetCount {
+	out := make([]domain.FacetCount, len(defs))
+	for i, d := range defs {
+		out[i] = domain.FacetCount{Key: d.key, Label: d.label}
+	}
+	unknown := 0
+	for _, p := range products {
+		v, ok := value(p)
+		if !ok {
+			unknown++
+			continue
+		}
+		for i, d := range defs {
+			if d.match(v) {
+				out[i].Count++
+				break
+			}
+		}
+	}
+	if unknown > 0 {
+		out = append(out, domain.FacetCount{Key: "unknown", Label: "Unknown", Count: unknown})
+	}
+	return out
+}
+
+// priceHistogram splits the observed price range into equal-width buckets.
+func priceHistogram(products []*domain.Product, currency string) domain.PriceHistogram {
+	if currency != "ETB" {
+		currency = "USD"
+	}
+	prices := make([]float64, 0, len(products))
+	for _, p := range products {
+		if v := displayPrice(p, currency); v > 0 {
+			prices = append(prices, v)
+		}
+	}
+	// Fall back to USD if ETB pricing was not available for this page.
+	if currency == "ETB" && len(prices) == 0 {
+		return priceHistogram(products, "USD")
+	}
+
+	h := domain.PriceHistogram{Currency: currency, Buckets: []domain.PriceBucket{}}
+	if len(prices) == 0 {
+		return h
+	}
+	sort.Float64s(prices)
+	lo, hi := prices[0], prices[len(prices)-1]
+
+	n := priceHistogramBuckets
+	if len(prices) < n {
+		n = len(prices)
+	}
+	if hi == lo {
+		n = 1
+	}
+	width := (hi - lo) / float64(n)
+	for i := 0; i < n; i++ {
+		b := domain.PriceBucket{Min: roundTo(lo+float64(i)*width, 2), Max: roundTo(lo+float64(i+1)*width, 2)}
+		if i == n-1 {
+			b.Max = roundTo(hi, 2)
+		}
+		h.Buckets = append(h.Buckets, b)
+	}
+	for _, v := range prices {
+		idx := n - 1
+		if width > 0 {
+			idx = int((v - lo) / width)
+			if idx >= n {
+				idx = n - 1
+			}
+		}
+		h.Buckets[idx].Count++
+	}
+	return h
+}
+
+func displayPrice(p *domain.Product, currency string) float64 {
+	if currency == "ETB" {
+		return p.Price.ETB
+	}
+	return p.Price.USD
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFacets(t *testing.T) {
+	phones := domain.Category{ID: "5090301", Name: "Mobile Phones"}
+	cases := domain.Category{ID: "380230", Name: "Phone Cases"}
+	products := []*domain.Product{
+		{Price: domain.Price{USD: 10, ETB: 1000}, ProductRating: 92, DeliveryEstimate: "ship to ET in 7 days", SubCategory: phones},
+		{Price: domain.Price{USD: 20, ETB: 2000}, ProductRating: 4.2, DeliveryEstimate: "10-20 days", SubCategory: phones},
+		{Price: domain.Price{USD: 30, ETB: 3000}, ProductRating: 3.5, DeliveryEstimate: "12-25 days", Category: cases},
+		{Price: domain.Price{USD: 50, ETB: 5000}, DeliveryEstimate: ""},
+		nil,
+	}
+
+	f := buildFacets(products, "ETB")
+
+	require.Len(t, f.Categories, 2)
+	assert.Equal(t, domain.FacetCount{Key: "5090301", Label: "Mobile Phones", Count: 2}, f.Categories[0])
+	assert.Equal(t, 1, f.Categories[1].Count)
+
+	assert.Equal(t, "ETB", f.PriceHistogram.Currency)
+	require.Len(t, f.PriceHistogram.Buckets, 4)
+	assert.InDelta(t, 1000, f.PriceHistogram.Buckets[0].Min, 1e-9)
+	assert.InDelta(t, 5000, f.PriceHistogram.Buckets[3].Max, 1e-9)
+	total := 0
+	for _, b := range f.PriceHistogram.Buckets {
+		total += b.Count
+	}
+	assert.Equal(t, 4, total)
+
+	counts := func(fc []domain.FacetCount) map[string]int {
+		m := map[string]int{}
+		for _, c := range fc {
+			m[c.Key] = c.Count
+		}
+		return m
+	}
+	assert.Equal(t, map[string]int{"0-7": 1, "8-15": 0, "16-30": 2, "31+": 0, "unknown": 1}, counts(f.DeliveryTime))
+	assert.Equal(t, map[string]int{"4.5+": 1, "4.0-4.5": 1, "3.0-4.0": 1, "<3.0": 0, "unknown": 1}, counts(f.Rating))
+}
+
+func TestPriceHistogram_FallsBackToUSD(t *testing.T) {
+	h := priceHistogram([]*domain.Product{{Price: domain.Price{USD: 12}}}, "ETB")
+	assert.Equal(t, "USD", h.Currency)
+	require.Len(t, h.Buckets, 1)
+	assert.Equal(t, 1, h.Buckets[0].Count)
+}
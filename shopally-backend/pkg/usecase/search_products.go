@@ -2,42 +2,109 @@ package usecase
 
 import (
 	"context"
-	"log"
+	"encoding/json"
 	"sort"
 	"sync"
+	"time"
 
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/internal/logging"
+	"github.com/shopally-ai/internal/tracing"
 	"github.com/shopally-ai/pkg/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var searchLog = logging.Component("search")
+
 // SearchProductsUseCase contains the business logic for searching products.
 // It orchestrates calls to external gateways (LLM, Alibaba, Cache).
 type SearchProductsUseCase struct {
 	alibabaGateway domain.AlibabaGateway
 	llmGateway     domain.LLMGateway
 	cacheGateway   domain.CacheGateway
+	fxClient       domain.IFXClient
+	priceHistory   *PriceHistoryUseCase
+	dealAnalyzer   *DealAnalyzer
+	snapshots      domain.ICachePort
+	snapshotTTL    time.Duration
+
+	// priceRelaxMargin is the fraction by which the price band is widened
+	// when recovering from an empty result set.
+	priceRelaxMargin float64
 }
 
+// DefaultSearchSnapshotTTL is how long search results stay available for
+// lookups by ID, e.g. when comparing products the upstream no longer returns.
+const DefaultSearchSnapshotTTL = 6 * time.Hour
+
+// DefaultPriceRelaxMargin widens min/max price by 25% during zero-result recovery.
+const DefaultPriceRelaxMargin = 0.25
+
 // NewSearchProductsUseCase creates a new SearchProductsUseCase.
 func NewSearchProductsUseCase(ag domain.AlibabaGateway, lg domain.LLMGateway, cg domain.CacheGateway) *SearchProductsUseCase {
 	return &SearchProductsUseCase{
 		alibabaGateway: ag,
 		llmGateway:     lg,
 		cacheGateway:   cg,
+
+		priceRelaxMargin: DefaultPriceRelaxMargin,
 	}
 }
 
+// WithFXClient sets the FX client used to price search results in ETB.
+func (uc *SearchProductsUseCase) WithFXClient(fx domain.IFXClient) *SearchProductsUseCase {
+	uc.fxClient = fx
+	return uc
+}
+
+// WithPriceHistory records the prices seen by every search.
+func (uc *SearchProductsUseCase) WithPriceHistory(ph *PriceHistoryUseCase) *SearchProductsUseCase {
+	uc.priceHistory = ph
+	return uc
+}
+
+// WithDealAnalyzer attaches a deal verdict to every search result.
+func (uc *SearchProductsUseCase) WithDealAnalyzer(da *DealAnalyzer) *SearchProductsUseCase {
+	uc.dealAnalyzer = da
+	return uc
+}
+
+// WithSnapshotCache stores every returned product so it can be resolved by ID
+// later (see ResolveProductsUseCase). Non-positive ttl uses DefaultSearchSnapshotTTL.
+func (uc *SearchProductsUseCase) WithSnapshotCache(cache domain.ICachePort, ttl time.Duration) *SearchProductsUseCase {
+	if ttl <= 0 {
+		ttl = DefaultSearchSnapshotTTL
+	}
+	uc.snapshots = cache
+	uc.snapshotTTL = ttl
+	return uc
+}
+
+// WithPriceRelaxMargin overrides the price band margin used during zero-result
+// recovery. Non-positive values keep the default.
+func (uc *SearchProductsUseCase) WithPriceRelaxMargin(margin float64) *SearchProductsUseCase {
+	if margin > 0 {
+		uc.priceRelaxMargin = margin
+	}
+	return uc
+}
+
 // Search runs the mocked search pipeline: Parse -> Fetch (using intent as filters).
-func (uc *SearchProductsUseCase) Search(ctx context.Context, query string) (interface{}, error) {
+func (uc *SearchProductsUseCase) Search(ctx context.Context, query string) (_ interface{}, err error) {
+	ctx, span := tracer.Start(ctx, "search")
+	defer func() { tracing.End(span, err) }()
+
 	// Parse intent via LLM
-	intent, err := uc.llmGateway.ParseIntent(ctx, query)
+	intentCtx, intentSpan := tracer.Start(ctx, "search.parse_intent")
+	intent, err := uc.llmGateway.ParseIntent(intentCtx, query)
+	tracing.End(intentSpan, err)
 	if err != nil {
 		// For V1 mock, fail soft by using empty filters
-		log.Println("SearchProductsUseCase: LLM intent parsing failed for query:", query, "error:", err)
+		searchLog.WarnContext(ctx, "LLM intent parsing failed", "query", query, logging.Err(err))
 		intent = map[string]interface{}{}
 	}
 
-	log.Println("SearchProductsUseCase: parsed intent for query:", query, "as", intent)
-
 	// Prune empty filters (nil/empty string) before passing to gateway
 	filters := make(map[string]interface{})
 	for k, v := range intent {
@@ -51,7 +118,8 @@ func (uc *SearchProductsUseCase) Search(ctx context.Context, query string) (inte
 		}
 		filters[k] = v
 	}
-	log.Println("SearchProductsUseCase: using filters for query:", query, "as", filters)
+	budget := applyPreferences(ctx, filters)
+	searchLog.DebugContext(ctx, "search filters", "query", query, "filters", filters)
 
 	var keywords string
 
@@ -61,13 +129,18 @@ func (uc *SearchProductsUseCase) Search(ctx context.Context, query string) (inte
 		keywords = query
 	}
 
-	// Fetch products from the gateway
-	products, err := uc.alibabaGateway.FetchProducts(ctx, keywords, filters)
-
-	log.Println("SearchProductsUseCase: fetched", len(products), "products for query:", query, "with filters:", filters)
+	// Fetch products from the gateway, relaxing constraints if nothing matches
+	fetchCtx, fetchSpan := tracer.Start(ctx, "search.fetch")
+	products, relaxed, err := uc.fetchWithRecovery(fetchCtx, keywords, filters)
+	fetchSpan.SetAttributes(
+		attribute.Int("products.count", len(products)),
+		attribute.Int("relaxed.count", len(relaxed)),
+	)
+	tracing.End(fetchSpan, err)
 	if err != nil {
 		return nil, err
 	}
+	searchLog.DebugContext(ctx, "fetched products", "count", len(products))
 
 	// Default ranking if filters are sparse (no price or delivery constraints)
 	if _, ok1 := filters["min_price"]; !ok1 {
@@ -82,11 +155,28 @@ func (uc *SearchProductsUseCase) Search(ctx context.Context, query string) (inte
 		}
 	}
 
-	log.Println("SearchProductsUseCase: ranked products for query:", query)
+	enrichCtx, enrichSpan := tracer.Start(ctx, "search.enrich")
+	if err := applyETBPricing(enrichCtx, uc.fxClient, products); err != nil {
+		searchLog.WarnContext(ctx, "ETB pricing failed", logging.Err(err))
+	}
+	// Judge deals before recording, so the current price is compared
+	// against history rather than against itself.
+	if uc.dealAnalyzer != nil {
+		uc.dealAnalyzer.AnnotateAll(enrichCtx, products)
+	}
+	enrichSpan.End()
+	if uc.priceHistory != nil {
+		uc.priceHistory.RecordAsync(ctx, products, domain.PriceSourceSearch)
+	}
+
+	currency, _ := ctx.Value(contextkeys.RespCurrency).(string)
+	facets := buildFacets(products, currency)
 
 	// Parallel summarization: each product summary is independent.
 	// Parallel summarization: each product summary is independent.
 	if uc.llmGateway != nil {
+		sumCtx, sumSpan := tracer.Start(ctx, "search.summarize",
+			trace.WithAttributes(attribute.Int("products.count", len(products))))
 		var wg sync.WaitGroup
 		wg.Add(len(products))
 
@@ -96,12 +186,15 @@ func (uc *SearchProductsUseCase) Search(ctx context.Context, query string) (inte
 				if products[index] == nil {
 					return
 				}
+				ctx, span := tracer.Start(sumCtx, "search.summarize_product",
+					trace.WithAttributes(attribute.String("product.id", products[index].ID)))
 
 				// Get the original product and user prompt from context if available
 				userPrompt := query
 
 				// Get enhanced product with all details
 				enhancedProduct, err := uc.llmGateway.SummarizeProduct(ctx, products[index], userPrompt)
+				tracing.End(span, err)
 				if err == nil && enhancedProduct != nil {
 					// Replace the entire product with enhanced version
 					products[index] = enhancedProduct
@@ -109,10 +202,71 @@ func (uc *SearchProductsUseCase) Search(ctx context.Context, query string) (inte
 			}(i)
 		}
 		wg.Wait()
+		sumSpan.End()
 	}
 
+	uc.storeSnapshots(ctx, products)
+
 	// Return the envelope-compatible data payload
-	return map[string]interface{}{"products": products}, nil
+	data := map[string]interface{}{"products": products, "facets": facets}
+	if len(relaxed) > 0 {
+		data["relaxedConstraints"] = relaxed
+	}
+	if budget != nil {
+		data["appliedBudget"] = budget
+	}
+	return data, nil
+}
+
+// applyPreferences sets the caller's delivery country and, when the query
+// names no price, their default budget. It returns the budget if applied.
+func applyPreferences(ctx context.Context, filters map[string]interface{}) *domain.Budget {
+	prefs, _ := ctx.Value(contextkeys.Preferences).(*domain.Preferences)
+	filters["ship_to_country"] = shipTo(ctx)
+	if prefs == nil || prefs.Budget == nil {
+		return nil
+	}
+	_, hasMin := filters["min_sale_price"]
+	_, hasMax := filters["max_sale_price"]
+	if hasMin || hasMax {
+		return nil
+	}
+	if prefs.Budget.MinUSD != nil {
+		filters["min_sale_price"] = *prefs.Budget.MinUSD
+	}
+	if prefs.Budget.MaxUSD != nil {
+		filters["max_sale_price"] = *prefs.Budget.MaxUSD
+	}
+	return prefs.Budget
+}
+
+// shipTo returns the caller's delivery country.
+func shipTo(ctx context.Context) string {
+	prefs, _ := ctx.Value(contextkeys.Preferences).(*domain.Preferences)
+	return prefs.ShipTo()
+}
+
+func searchSnapshotKey(country, id string) string {
+	return "search:product:" + country + ":" + id
+}
+
+// storeSnapshots caches each product under its ID. Failures are logged only.
+func (uc *SearchProductsUseCase) storeSnapshots(ctx context.Context, products []*domain.Product) {
+	if uc.snapshots == nil {
+		return
+	}
+	for _, p := range products {
+		if p == nil || p.ID == "" {
+			continue
+		}
+		b, err := json.Marshal(p)
+		if err != nil {
+			continue
+		}
+		if err := uc.snapshots.Set(ctx, searchSnapshotKey(shipTo(ctx), p.ID), string(b), uc.snapshotTTL); err != nil {
+			searchLog.WarnContext(ctx, "snapshot cache set failed", "product_id", p.ID, logging.Err(err))
+		}
+	}
 }
 
 func defaultScore(p *domain.Product) float64 {
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopally-ai/internal/contextkeys"
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// stubAlibabaGateway returns products only once match reports true for the request.
+type stubAlibabaGateway struct {
+	match    func(keywords string, filters map[string]interface{}) bool
+	keywords []string
+}
+
+func (s *stubAlibabaGateway) FetchProducts(ctx context.Context, keywords string, filters map[string]interface{}) ([]*domain.Product, error) {
+	s.keywords = append(s.keywords, keywords)
+	if s.match(keywords, filters) {
+		return []*domain.Product{{ID: "P1", Title: keywords}}, nil
+	}
+	return []*domain.Product{}, nil
+}
+
+func (s *stubAlibabaGateway) FetchProductDetail(ctx context.Context, productID string) (*domain.Product, error) {
+	return nil, nil
+}
+
+// stubIntentLLM returns a fixed intent and leaves products unchanged.
+type stubIntentLLM struct {
+	intent map[string]interface{}
+}
+
+func (s *stubIntentLLM) ParseIntent(ctx context.Context, query string) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(s.intent))
+	for k, v := range s.intent {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (s *stubIntentLLM) SummarizeProduct(ctx context.Context, p *domain.Product, _ string) (*domain.Product, error) {
+	return p, nil
+}
+
+func (s *stubIntentLLM) CompareProducts(ctx context.Context, products []*domain.Product) (*domain.ComparisonResult, error) {
+	return nil, nil
+}
+
+func TestSearch_ZeroResultRecovery(t *testing.T) {
+	intent := map[string]interface{}{
+		"keywords":       "red leather gaming chair",
+		"category_ids":   "100",
+		"delivery_days":  float64(5),
+		"min_sale_price": 40.0,
+		"max_sale_price": 80.0,
+	}
+
+	t.Run("stops after the first relaxation that yields results", func(t *testing.T) {
+		ag := &stubAlibabaGateway{match: func(_ string, f map[string]interface{}) bool {
+			_, hasCat := f["category_ids"]
+			return !hasCat
+		}}
+		uc := NewSearchProductsUseCase(ag, &stubIntentLLM{intent: intent}, nil)
+
+		data, err := uc.Search(context.Background(), "chair")
+		require.NoError(t, err)
+
+		out := data.(map[string]interface{})
+		assert.Len(t, out["products"], 1)
+		relaxed := out["relaxedConstraints"].([]domain.RelaxedConstraint)
+		require.Len(t, relaxed, 2)
+		assert.Equal(t, "delivery_days", relaxed[0].Filter)
+		assert.Equal(t, "category_ids", relaxed[1].Filter)
+		assert.Equal(t, domain.RelaxDropped, relaxed[1].Action)
+	})
+
+	t.Run("widens the price band by the configured margin then simplifies keywords", func(t *testing.T) {
+		ag := &stubAlibabaGateway{match: func(k string, _ map[string]interface{}) bool {
+			return k == "gaming chair"
+		}}
+		uc := NewSearchProductsUseCase(ag, &stubIntentLLM{intent: intent}, nil).WithPriceRelaxMargin(0.5)
+
+		data, err := uc.Search(context.Background(), "chair")
+		require.NoError(t, err)
+
+		relaxed := data.(map[string]interface{})["relaxedConstraints"].([]domain.RelaxedConstraint)
+		require.Len(t, relaxed, 4)
+		assert.Equal(t, "price", relaxed[2].Filter)
+		to := relaxed[2].To.(map[string]interface{})
+		assert.InDelta(t, 20.0, to["min_sale_price"], 1e-9)
+		assert.InDelta(t, 120.0, to["max_sale_price"], 1e-9)
+		assert.Equal(t, domain.RelaxSimplified, relaxed[3].Action)
+		assert.Equal(t, []string{"red leather gaming chair", "red leather gaming chair", "red leather gaming chair", "red leather gaming chair", "gaming chair"}, ag.keywords)
+	})
+
+	t.Run("does not report relaxations when the first fetch succeeds", func(t *testing.T) {
+		ag := &stubAlibabaGateway{match: func(string, map[string]interface{}) bool { return true }}
+		uc := NewSearchProductsUseCase(ag, &stubIntentLLM{intent: intent}, nil)
+
+		data, err := uc.Search(context.Background(), "chair")
+		require.NoError(t, err)
+		_, ok := data.(map[string]interface{})["relaxedConstraints"]
+		assert.False(t, ok)
+		assert.Len(t, ag.keywords, 1)
+	})
+}
+
+func TestSearch_AppliesPreferences(t *testing.T) {
+	var seen map[string]interface{}
+	ag := &stubAlibabaGateway{match: func(_ string, f map[string]interface{}) bool {
+		seen = f
+		return true
+	}}
+	max := 30.0
+	prefs := &domain.Preferences{ShipToCountry: "KE", Budget: &domain.Budget{MaxUSD: &max}}
+	ctx := context.WithValue(context.Background(), contextkeys.Preferences, prefs)
+
+	uc := NewSearchProductsUseCase(ag, &stubIntentLLM{intent: map[string]interface{}{"keywords": "phone", "ship_to_country": "ET"}}, nil)
+	data, err := uc.Search(ctx, "phone")
+	require.NoError(t, err)
+	assert.Equal(t, "KE", seen["ship_to_country"])
+	assert.Equal(t, 30.0, seen["max_sale_price"])
+	assert.Equal(t, prefs.Budget, data.(map[string]interface{})["appliedBudget"])
+
+	// A price in the query wins over the budget.
+	uc = NewSearchProductsUseCase(ag, &stubIntentLLM{intent: map[string]interface{}{"keywords": "phone", "min_sale_price": 100.0}}, nil)
+	data, err = uc.Search(ctx, "phone over 100")
+	require.NoError(t, err)
+	_, hasMax := seen["max_sale_price"]
+	assert.False(t, hasMax)
+	_, applied := data.(map[string]interface{})["appliedBudget"]
+	assert.False(t, applied)
+}
+
+func TestSimplifyKeywords(t *testing.T) {
+	assert.Equal(t, "gaming chair", simplifyKeywords("Red leather Gaming Chair"))
+	assert.Equal(t, "phone", simplifyKeywords("5g phone"))
+	assert.Equal(t, "", simplifyKeywords("a 12"))
+}
+
+func TestSearch_Spans(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+
+	ag := &stubAlibabaGateway{match: func(string, map[string]interface{}) bool { return true }}
+	uc := NewSearchProductsUseCase(ag, &stubIntentLLM{intent: map[string]interface{}{"keywords": "phone"}}, nil)
+	_, err := uc.Search(context.Background(), "phone")
+	require.NoError(t, err)
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		byName[s.Name()] = s
+	}
+	root := byName["search"]
+	require.NotNil(t, root)
+	for _, name := range []string{"search.parse_intent", "search.fetch", "search.enrich", "search.summarize"} {
+		require.Contains(t, byName, name)
+		assert.Equal(t, root.SpanContext().SpanID(), byName[name].Parent().SpanID(), name)
+	}
+	// Summaries run in goroutines but stay under the summarize stage.
+	require.Contains(t, byName, "search.summarize_product")
+	assert.Equal(t, byName["search.summarize"].SpanContext().SpanID(), byName["search.summarize_product"].Parent().SpanID())
+	assert.Equal(t, root.SpanContext().TraceID(), byName["search.summarize_product"].SpanContext().TraceID())
+}
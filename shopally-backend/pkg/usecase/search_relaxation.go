@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// relaxStep loosens a single constraint in place. It returns the applied
+// relaxation, or ok=false when the constraint is absent and the step is skipped.
+type relaxStep func(keywords *string, filters map[string]interface{}) (domain.RelaxedConstraint, bool)
+
+// fetchWithRecovery fetches products and, when the gateway returns nothing,
+// relaxes the constraints one at a time (delivery, category, price band,
+// keywords) until results appear. Relaxations are cumulative and reported in
+// the order they were applied.
+func (uc *SearchProductsUseCase) fetchWithRecovery(ctx context.Context, keywords string, filters map[string]interface{}) ([]*domain.Product, []domain.RelaxedConstraint, error) {
+	products, err := uc.alibabaGateway.FetchProducts(ctx, keywords, filters)
+	if err != nil || len(products) > 0 {
+		return products, nil, err
+	}
+
+	steps := []relaxStep{
+		dropFilter("delivery_days"),
+		dropFilter("category_ids"),
+		widenPriceBand(uc.priceRelaxMargin),
+		simplifyKeywordsStep,
+	}
+
+	var relaxed []domain.RelaxedConstraint
+	for _, step := range steps {
+		rc, ok := step(&keywords, filters)
+		if !ok {
+			continue
+		}
+		relaxed = append(relaxed, rc)
+		searchLog.InfoContext(ctx, "no results, relaxing filter and retrying", "filter", rc.Filter, "action", rc.Action)
+
+		products, err = uc.alibabaGateway.FetchProducts(ctx, keywords, filters)
+		if err != nil {
+			return nil, relaxed, err
+		}
+		if len(products) > 0 {
+			return products, relaxed, nil
+		}
+	}
+
+	return products, relaxed, nil
+}
+
+// dropFilter removes the given filter key when it is present.
+func dropFilter(key string) relaxStep {
+	return func(_ *string, filters map[string]interface{}) (domain.RelaxedConstraint, bool) {
+		v, ok := filters[key]
+		if !ok {
+			return domain.RelaxedConstraint{}, false
+		}
+		delete(filters, key)
+		return domain.RelaxedConstraint{Filter: key, Action: domain.RelaxDropped, From: v}, true
+	}
+}
+
+// widenPriceBand lowers min_sale_price and raises max_sale_price by margin.
+func widenPriceBand(margin float64) relaxStep {
+	return func(_ *string, filters map[string]interface{}) (domain.RelaxedConstraint, bool) {
+		minP, hasMin := toFloat(filters["min_sale_price"])
+		maxP, hasMax := toFloat(filters["max_sale_price"])
+		if !hasMin && !hasMax {
+			return domain.RelaxedConstraint{}, false
+		}
+
+		from := map[string]interface{}{}
+		to := map[string]interface{}{}
+		if hasMin {
+			from["min_sale_price"] = minP
+			newMin := minP * (1 - margin)
+			if newMin < 0 {
+				newMin = 0
+			}
+			filters["min_sale_price"] = newMin
+			to["min_sale_price"] = newMin
+		}
+		if hasMax {
+			from["max_sale_price"] = maxP
+			newMax := maxP * (1 + margin)
+			filters["max_sale_price"] = newMax
+			to["max_sale_price"] = newMax
+		}
+		return domain.RelaxedConstraint{Filter: "price", Action: domain.RelaxWidened, From: from, To: to}, true
+	}
+}
+
+// simplifyKeywordsStep reduces the keywords to their core terms.
+func simplifyKeywordsStep(keywords *string, _ map[string]interface{}) (domain.RelaxedConstraint, bool) {
+	simplified := simplifyKeywords(*keywords)
+	if simplified == "" || strings.EqualFold(simplified, strings.TrimSpace(*keywords)) {
+		return domain.RelaxedConstraint{}, false
+	}
+	rc := domain.RelaxedConstraint{Filter: "keywords", Action: domain.RelaxSimplified, From: *keywords, To: simplified}
+	*keywords = simplified
+	return rc, true
+}
+
+// simplifyKeywords drops short tokens and numbers and keeps the last two
+// remaining words, which in English product queries usually carry the noun
+// ("red leather gaming chair" -> "gaming chair").
+func simplifyKeywords(keywords string) string {
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(keywords)) {
+		w = strings.Trim(w, ",.;:!?\"'()")
+		if len([]rune(w)) < 3 || isNumeric(w) {
+			continue
+		}
+		words = append(words, w)
+	}
+	if len(words) > 2 {
+		words = words[len(words)-2:]
+	}
+	return strings.Join(words, " ")
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && r != '.' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	}
+	return 0, false
+}
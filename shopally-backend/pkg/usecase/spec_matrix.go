@@ -0,0 +1,272 @@
+package usecase
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopally-ai/pkg/domain"
+)
+
+// Spec groups decide which specifications are extracted for a product, so
+// that e.g. "256GB" in a clothing title is not read as storage.
+const (
+	specGroupElectronics = "electronics"
+	specGroupApparel     = "apparel"
+	specGroupUnknown     = ""
+)
+
+var specGroupKeywords = map[string][]string{
+	specGroupElectronics: {"phone", "telecommunication", "electronic", "computer", "tablet", "laptop", "camera", "office"},
+	specGroupApparel:     {"clothing", "apparel", "shoes", "bags", "sportswear", "underwear", "wig"},
+}
+
+// specExtractor reads one normalized specification from product text.
+type specExtractor struct {
+	key     string
+	unit    string
+	skip    []string // spec groups the spec does not apply to
+	extract func(text string) (domain.SpecValue, bool)
+}
+
+var specExtractors = []specExtractor{
+	{key: domain.SpecStorage, unit: "GB", skip: []string{specGroupApparel}, extract: func(text string) (domain.SpecValue, bool) {
+		_, storage := memorySpecs(text)
+		return numericSpec(storage, "GB")
+	}},
+	{key: domain.SpecRAM, unit: "GB", skip: []string{specGroupApparel}, extract: func(text string) (domain.SpecValue, bool) {
+		ram, _ := memorySpecs(text)
+		return numericSpec(ram, "GB")
+	}},
+	{key: domain.SpecScreenSize, unit: "in", skip: []string{specGroupApparel}, extract: screenSizeSpec},
+	{key: domain.SpecBattery, unit: "mAh", skip: []string{specGroupApparel}, extract: batterySpec},
+	{key: domain.SpecMaterial, extract: materialSpec},
+	{key: domain.SpecSize, skip: []string{specGroupElectronics}, extract: sizeSpec},
+}
+
+// buildSpecMatrix extracts normalized specifications from each product's title
+// and description and aligns them in product order. A row is only included
+// when at least one product states the spec; other products are marked missing.
+func buildSpecMatrix(products []*domain.Product) *domain.SpecMatrix {
+	m := &domain.SpecMatrix{ProductIDs: make([]string, len(products)), Rows: []domain.SpecRow{}}
+	texts := make([]string, len(products))
+	groups := make([]string, len(products))
+	for i, p := range products {
+		m.ProductIDs[i] = p.ID
+		texts[i] = p.Title + " \n " + p.Description
+		groups[i] = specGroup(p)
+	}
+
+	for _, ex := range specExtractors {
+		row := domain.SpecRow{Key: ex.key, Unit: ex.unit, Values: make([]domain.SpecValue, len(products))}
+		found := false
+		for i := range products {
+			row.Values[i] = domain.SpecValue{Missing: true}
+			if containsString(ex.skip, groups[i]) {
+				continue
+			}
+			if v, ok := ex.extract(texts[i]); ok {
+				row.Values[i] = v
+				found = true
+			}
+		}
+		if found {
+			m.Rows = append(m.Rows, row)
+		}
+	}
+	return m
+}
+
+func specGroup(p *domain.Product) string {
+	for _, name := range []string{p.SubCategory.Name, p.Category.Name} {
+		name = strings.ToLower(name)
+		if name == "" {
+			continue
+		}
+		for _, group := range []string{specGroupElectronics, specGroupApparel} {
+			for _, kw := range specGroupKeywords[group] {
+				if strings.Contains(name, kw) {
+					return group
+				}
+			}
+		}
+	}
+	return specGroupUnknown
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s && s != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func numericSpec(v float64, unit string) (domain.SpecValue, bool) {
+	if v <= 0 {
+		return domain.SpecValue{}, false
+	}
+	return domain.SpecValue{Value: v, Display: strconv.FormatFloat(v, 'f', -1, 64) + " " + unit}, true
+}
+
+func textSpec(s string) (domain.SpecValue, bool) {
+	if s == "" {
+		return domain.SpecValue{}, false
+	}
+	return domain.SpecValue{Text: s, Display: s}, true
+}
+
+var (
+	// "8+256GB", "8GB/128GB": RAM then storage.
+	memoryComboRe = regexp.MustCompile(`(?i)\b(\d{1,2})\s?(?:gb)?\s?[+/]\s?(\d{2,4})\s?(gb|tb)\b`)
+	ramRe         = regexp.MustCompile(`(?i)\b(\d{1,2})\s?gb\s?(?:of\s)?(?:ram|lpddr\d*x?|ddr\d*)\b|\b(?:ram|memory)\s?:?\s?(\d{1,2})\s?gb\b`)
+	capacityRe    = regexp.MustCompile(`(?i)\b(\d+(?:\.\d+)?)\s?(gb|tb)\b`)
+)
+
+// memorySpecs returns RAM and storage in GB, or zero when not stated.
+func memorySpecs(text string) (ram, storage float64) {
+	if m := memoryComboRe.FindStringSubmatch(text); m != nil {
+		ram, _ = strconv.ParseFloat(m[1], 64)
+		storage = toGB(m[2], m[3])
+		if ram < storage {
+			return ram, storage
+		}
+		ram, storage = 0, 0
+	}
+
+	if m := ramRe.FindStringSubmatch(text); m != nil {
+		n := m[1]
+		if n == "" {
+			n = m[2]
+		}
+		ram, _ = strconv.ParseFloat(n, 64)
+	}
+	for _, m := range capacityRe.FindAllStringSubmatch(text, -1) {
+		if gb := toGB(m[1], m[2]); gb > storage && gb != ram {
+			storage = gb
+		}
+	}
+	if storage > 0 && storage < ram {
+		storage = 0
+	}
+	return ram, storage
+}
+
+func toGB(n, unit string) float64 {
+	v, _ := strconv.ParseFloat(n, 64)
+	if strings.EqualFold(unit, "tb") {
+		v *= 1024
+	}
+	return v
+}
+
+var screenRe = regexp.MustCompile(`(?i)\b(\d{1,2}(?:\.\d{1,2})?)\s?(?:"|”|''|-?\s?inch(?:es)?\b)`)
+
+func screenSizeSpec(text string) (domain.SpecValue, bool) {
+	m := screenRe.FindStringSubmatch(text)
+	if m == nil {
+		return domain.SpecValue{}, false
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+	if v < 1 || v > 100 {
+		return domain.SpecValue{}, false
+	}
+	return numericSpec(math.Round(v*10)/10, "in")
+}
+
+var (
+	mahRe = regexp.MustCompile(`(?i)\b(\d{3,6})\s?mah\b`)
+	ahRe  = regexp.MustCompile(`(?i)\b(\d{1,2}(?:\.\d+)?)\s?ah\b`)
+)
+
+func batterySpec(text string) (domain.SpecValue, bool) {
+	if m := mahRe.FindStringSubmatch(text); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		return numericSpec(v, "mAh")
+	}
+	if m := ahRe.FindStringSubmatch(text); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		return numericSpec(math.Round(v*1000), "mAh")
+	}
+	return domain.SpecValue{}, false
+}
+
+// materials maps canonical material names to the phrases that denote them.
+var materials = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"faux leather", regexp.MustCompile(`(?i)\b(?:pu|faux|synthetic|vegan)\s?leather\b`)},
+	{"leather", regexp.MustCompile(`(?i)\bleather\b`)},
+	{"stainless steel", regexp.MustCompile(`(?i)\bstainless(?:\s?steel)?\b`)},
+	{"aluminum", regexp.MustCompile(`(?i)\balumin(?:i)?um\b`)},
+	{"cotton", regexp.MustCompile(`(?i)\bcotton\b`)},
+	{"polyester", regexp.MustCompile(`(?i)\bpolyester\b`)},
+	{"nylon", regexp.MustCompile(`(?i)\bnylon\b`)},
+	{"spandex", regexp.MustCompile(`(?i)\b(?:spandex|elastane|lycra)\b`)},
+	{"wool", regexp.MustCompile(`(?i)\bwool\b`)},
+	{"silk", regexp.MustCompile(`(?i)\bsilk\b`)},
+	{"silicone", regexp.MustCompile(`(?i)\bsilicone?\b`)},
+	{"wood", regexp.MustCompile(`(?i)\bwood(?:en)?\b`)},
+	{"glass", regexp.MustCompile(`(?i)\bglass\b`)},
+	{"plastic", regexp.MustCompile(`(?i)\b(?:plastic|abs)\b`)},
+}
+
+// materialSpec lists up to two materials in the order they are mentioned.
+func materialSpec(text string) (domain.SpecValue, bool) {
+	type hit struct {
+		name string
+		pos  int
+	}
+	var hits []hit
+	faux := false
+	for _, mat := range materials {
+		loc := mat.re.FindStringIndex(text)
+		if loc == nil || (mat.name == "leather" && faux) {
+			continue
+		}
+		faux = faux || mat.name == "faux leather"
+		hits = append(hits, hit{mat.name, loc[0]})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].pos < hits[j].pos })
+	if len(hits) > 2 {
+		hits = hits[:2]
+	}
+	names := make([]string, len(hits))
+	for i, h := range hits {
+		names[i] = h.name
+	}
+	return textSpec(strings.Join(names, ", "))
+}
+
+const sizeLabel = `(XXXL|XXL|XXS|XS|XL|[2-6]XL|S|M|L)`
+
+var (
+	sizeRangeRe = regexp.MustCompile(`(?i)\b` + sizeLabel + `\s?(?:-|~|–|to)\s?` + sizeLabel + `\b`)
+	sizeRe      = regexp.MustCompile(`(?i)\bsize\s?:?\s?` + sizeLabel + `\b`)
+)
+
+// sizeSpec reads apparel sizes as canonical labels (XXL -> 2XL), either a
+// single size or a range such as "S-3XL".
+func sizeSpec(text string) (domain.SpecValue, bool) {
+	if m := sizeRangeRe.FindStringSubmatch(text); m != nil {
+		return textSpec(canonicalSize(m[1]) + "-" + canonicalSize(m[2]))
+	}
+	if m := sizeRe.FindStringSubmatch(text); m != nil {
+		return textSpec(canonicalSize(m[1]))
+	}
+	return domain.SpecValue{}, false
+}
+
+func canonicalSize(s string) string {
+	switch s = strings.ToUpper(s); s {
+	case "XXL":
+		return "2XL"
+	case "XXXL":
+		return "3XL"
+	}
+	return s
+}
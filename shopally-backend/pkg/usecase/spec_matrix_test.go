@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/shopally-ai/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSpecMatrix(t *testing.T) {
+	phones := domain.Category{Name: "Cellphones & Telecommunications"}
+	products := []*domain.Product{
+		{ID: "1", Title: `Smartphone 8GB+256GB 6.7" 5000mAh`, Category: phones},
+		{ID: "2", Title: "Phone 1TB storage, 12GB RAM, 6.1 inch", Description: "Aluminium frame, 4.5Ah battery", Category: phones},
+		{ID: "3", Title: "Phone case PU leather size XXL 128GB", Category: domain.Category{Name: "Women's Clothing"}},
+	}
+
+	m := buildSpecMatrix(products)
+	assert.Equal(t, []string{"1", "2", "3"}, m.ProductIDs)
+
+	rows := map[string]domain.SpecRow{}
+	var keys []string
+	for _, r := range m.Rows {
+		rows[r.Key] = r
+		keys = append(keys, r.Key)
+		require.Len(t, r.Values, 3, r.Key)
+	}
+	assert.Equal(t, []string{domain.SpecStorage, domain.SpecRAM, domain.SpecScreenSize, domain.SpecBattery, domain.SpecMaterial, domain.SpecSize}, keys)
+
+	storage := rows[domain.SpecStorage]
+	assert.Equal(t, "GB", storage.Unit)
+	assert.Equal(t, 256.0, storage.Values[0].Value)
+	assert.Equal(t, 1024.0, storage.Values[1].Value)
+	assert.True(t, storage.Values[2].Missing, "apparel is not read for storage")
+
+	assert.Equal(t, 8.0, rows[domain.SpecRAM].Values[0].Value)
+	assert.Equal(t, 12.0, rows[domain.SpecRAM].Values[1].Value)
+	assert.Equal(t, 6.7, rows[domain.SpecScreenSize].Values[0].Value)
+	assert.Equal(t, "6.1 in", rows[domain.SpecScreenSize].Values[1].Display)
+	assert.Equal(t, 4500.0, rows[domain.SpecBattery].Values[1].Value)
+
+	material := rows[domain.SpecMaterial]
+	assert.True(t, material.Values[0].Missing)
+	assert.Equal(t, "aluminum", material.Values[1].Text)
+	assert.Equal(t, "faux leather", material.Values[2].Text)
+
+	size := rows[domain.SpecSize]
+	assert.True(t, size.Values[0].Missing, "electronics are not read for size")
+	assert.Equal(t, "2XL", size.Values[2].Text)
+}
+
+func TestSizeSpec(t *testing.T) {
+	v, ok := sizeSpec("Summer dress S-XXXL")
+	require.True(t, ok)
+	assert.Equal(t, "S-3XL", v.Text)
+
+	_, ok = sizeSpec("USB-C charger")
+	assert.False(t, ok)
+}
+
+func TestMemorySpecs(t *testing.T) {
+	ram, storage := memorySpecs("USB flash drive 64GB")
+	assert.Zero(t, ram)
+	assert.Equal(t, 64.0, storage)
+
+	ram, storage = memorySpecs("Laptop 16GB DDR4 512GB SSD")
+	assert.Equal(t, 16.0, ram)
+	assert.Equal(t, 512.0, storage)
+}
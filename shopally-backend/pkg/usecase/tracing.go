@@ -0,0 +1,6 @@
+package usecase
+
+import "github.com/shopally-ai/internal/tracing"
+
+// tracer starts the spans of the use case stages.
+var tracer = tracing.Tracer("usecase")
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// urlInTextRe matches http(s) URLs and bare aliexpress hosts inside free text.
+	urlInTextRe = regexp.MustCompile(`(?i)(https?://[^\s]+|(?:[a-z0-9-]+\.)*aliexpress\.[a-z.]+/[^\s]*)`)
+
+	// itemPathRe matches the product ID in item paths such as /item/123.html,
+	// /i/123.html, /s/item/123.html and legacy /store/product/name/1_123.html.
+	itemPathRe = regexp.MustCompile(`(?i)/(?:item|i)/(?:[^/]*/)?(\d{6,})(?:\.html?)?`)
+	legacyRe   = regexp.MustCompile(`(?i)/store/product/[^/]+/\d+_(\d{6,})\.html`)
+	digitsRe   = regexp.MustCompile(`^\d{6,}$`)
+
+	tldRe   = regexp.MustCompile(`^[a-z]{2,6}$`)
+	ccTLDRe = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// shortLinkHosts serve opaque short links that must be followed to find the item.
+var shortLinkHosts = []string{"a.aliexpress.com", "s.click.aliexpress.com", "click.aliexpress.com"}
+
+// IsAliExpressHost reports whether host belongs to an AliExpress storefront,
+// including regional (aliexpress.us, aliexpress.ru, aliexpress.com.br) and
+// mobile subdomains. "aliexpress" must be the registrable label, so hosts
+// such as aliexpress.evil.com are rejected.
+func IsAliExpressHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if i := strings.LastIndex(host, ":"); i != -1 {
+		host = host[:i]
+	}
+	labels := strings.Split(host, ".")
+	n := len(labels)
+	switch {
+	case n >= 2 && labels[n-2] == "aliexpress":
+		return tldRe.MatchString(labels[n-1])
+	case n >= 3 && labels[n-3] == "aliexpress" && labels[n-2] == "com":
+		return ccTLDRe.MatchString(labels[n-1])
+	}
+	return false
+}
+
+// IsAliExpressShortLink reports whether u is a short/affiliate link that
+// redirects to the actual item page.
+func IsAliExpressShortLink(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	for _, h := range shortLinkHosts {
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAliExpressURL returns the first AliExpress URL contained in text.
+func FindAliExpressURL(text string) (*url.URL, bool) {
+	for _, m := range urlInTextRe.FindAllString(text, -1) {
+		m = strings.TrimRight(m, ".,;!?)\"'")
+		if !strings.Contains(strings.ToLower(m), "://") {
+			m = "https://" + m
+		}
+		u, err := url.Parse(m)
+		if err != nil || !IsAliExpressHost(u.Hostname()) {
+			continue
+		}
+		return u, true
+	}
+	return nil, false
+}
+
+// ExtractAliExpressProductID pulls the product ID out of a desktop, mobile or
+// share URL. Short links carry no ID and return false.
+func ExtractAliExpressProductID(u *url.URL) (string, bool) {
+	if u == nil || !IsAliExpressHost(u.Hostname()) {
+		return "", false
+	}
+	if m := legacyRe.FindStringSubmatch(u.Path); m != nil {
+		return m[1], true
+	}
+	if m := itemPathRe.FindStringSubmatch(u.Path); m != nil {
+		return m[1], true
+	}
+
+	q := u.Query()
+	for _, k := range []string{"productId", "product_id", "itemId", "objectId"} {
+		if v := strings.TrimSpace(q.Get(k)); digitsRe.MatchString(v) {
+			return v, true
+		}
+	}
+	// Share pages wrap the real item URL in a redirect parameter.
+	for _, k := range []string{"redirectUrl", "redirect_url", "dl_target_url"} {
+		if v := q.Get(k); v != "" {
+			if inner, err := url.Parse(v); err == nil {
+				if id, ok := ExtractAliExpressProductID(inner); ok {
+					return id, true
+				}
+			}
+		}
+	}
+	return "", false
+}
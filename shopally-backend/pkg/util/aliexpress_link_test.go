@@ -0,0 +1,70 @@
+package util
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractAliExpressProductID(t *testing.T) {
+	cases := map[string]string{
+		"https://www.aliexpress.com/item/1005001234567890.html?spm=a2g0o":                                                         "1005001234567890",
+		"https://aliexpress.us/item/3256801234567890.html":                                                                        "3256801234567890",
+		"https://m.aliexpress.com/item/1005001234567890.html":                                                                     "1005001234567890",
+		"https://m.aliexpress.com/s/item/1005001234567890.html":                                                                   "1005001234567890",
+		"https://www.aliexpress.com/i/33006951782.html":                                                                           "33006951782",
+		"https://m.aliexpress.com/item/detail.htm?productId=33006951782":                                                          "33006951782",
+		"https://www.aliexpress.com/store/product/Some-Dress/1234_33006951782.html":                                               "33006951782",
+		"https://star.aliexpress.com/share/share.htm?redirectUrl=https%3A%2F%2Fwww.aliexpress.com%2Fitem%2F1005001234567890.html": "1005001234567890",
+	}
+	for raw, want := range cases {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		got, ok := ExtractAliExpressProductID(u)
+		assert.True(t, ok, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	for _, raw := range []string{
+		"https://a.aliexpress.com/_mKxyz12",
+		"https://www.aliexpress.com/store/912345",
+		"https://www.amazon.com/item/1005001234567890.html",
+	} {
+		u, _ := url.Parse(raw)
+		_, ok := ExtractAliExpressProductID(u)
+		assert.False(t, ok, raw)
+	}
+}
+
+func TestFindAliExpressURL(t *testing.T) {
+	u, ok := FindAliExpressURL("look at this https://a.aliexpress.com/_mKxyz12, nice!")
+	require.True(t, ok)
+	assert.Equal(t, "https://a.aliexpress.com/_mKxyz12", u.String())
+	assert.True(t, IsAliExpressShortLink(u))
+
+	u, ok = FindAliExpressURL("aliexpress.com/item/33006951782.html")
+	require.True(t, ok)
+	assert.Equal(t, "aliexpress.com", u.Hostname())
+
+	_, ok = FindAliExpressURL("cheap phone under 5000 birr")
+	assert.False(t, ok)
+	_, ok = FindAliExpressURL("https://example.com/item/1.html")
+	assert.False(t, ok)
+}
+
+func TestIsAliExpressHost(t *testing.T) {
+	for _, host := range []string{
+		"aliexpress.com", "www.aliexpress.com", "m.aliexpress.com", "a.aliexpress.com",
+		"aliexpress.us", "aliexpress.ru", "pt.aliexpress.com.br", "www.aliexpress.com:443", "aliexpress.com.",
+	} {
+		assert.True(t, IsAliExpressHost(host), host)
+	}
+	for _, host := range []string{
+		"aliexpress.evil.com", "aliexpress.attacker.io", "aliexpress.com.evil.io", "aliexpress.com.evil",
+		"evil-aliexpress.com", "aliexpress", "localhost", "169.254.169.254",
+	} {
+		assert.False(t, IsAliExpressHost(host), host)
+	}
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var deliveryDaysRe = regexp.MustCompile(`\d+`)
+
+// ParseDeliveryDays extracts the worst-case number of days from a free-form
+// delivery estimate such as "15-30 days" or "ship to ET in 7 days".
+// It returns false when the estimate contains no number.
+func ParseDeliveryDays(estimate string) (int, bool) {
+	nums := deliveryDaysRe.FindAllString(estimate, -1)
+	if len(nums) == 0 {
+		return 0, false
+	}
+	max := 0
+	for _, n := range nums {
+		if v, err := strconv.Atoi(n); err == nil && v > max {
+			max = v
+		}
+	}
+	return max, max > 0
+}
+
+// NormalizeRating maps a product rating onto a 0..5 scale. AliExpress reports
+// evaluate_rate as a positive-feedback percentage (e.g. 92.1), while other
+// sources already use stars.
+func NormalizeRating(r float64) float64 {
+	if r > 5 {
+		return r / 20.0
+	}
+	if r < 0 {
+		return 0
+	}
+	return r
+}
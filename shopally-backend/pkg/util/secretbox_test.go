@@ -0,0 +1,37 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretBox(t *testing.T) {
+	box, err := NewSecretBox(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+
+	a, err := box.Seal("access-token")
+	require.NoError(t, err)
+	b, err := box.Seal("access-token")
+	require.NoError(t, err)
+	assert.NotContains(t, a, "access-token")
+	assert.NotEqual(t, a, b, "every seal uses a fresh nonce")
+
+	plain, err := box.Open(a)
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", plain)
+
+	other, err := NewSecretBox(bytes.Repeat([]byte{8}, 32))
+	require.NoError(t, err)
+	_, err = other.Open(a)
+	assert.Error(t, err, "a different key must not open the value")
+
+	empty, err := box.Seal("")
+	require.NoError(t, err)
+	assert.Equal(t, "", empty)
+
+	_, err = NewSecretBox([]byte("short"))
+	assert.Error(t, err)
+}